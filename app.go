@@ -2,42 +2,283 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ARUMANDESU/todo-app/internal"
+	"github.com/ARUMANDESU/todo-app/internal/api"
+	"github.com/ARUMANDESU/todo-app/internal/config"
 	"github.com/ARUMANDESU/todo-app/internal/domain"
 	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 // App struct
 type App struct {
-	ctx         context.Context
-	taskService TaskService
+	ctx       context.Context
+	config    config.Config
+	profileMu sync.RWMutex     // held for writing while the profile is switched
+	active    *profileServices // of the active profile, read it with useProfile
+
+	settingsMu sync.Mutex
+	settings   domain.Settings // of the active profile, kept up to date by UpdateSettings
+
+	todoTxtMu       sync.Mutex
+	todoTxtSync     *internal.TodoTxtSync // nil while the todo.txt sync is off
+	stopTodoTxtSync context.CancelFunc
+}
+
+// profileServices are the database of a profile and the services on top of it, they are replaced together
+// when the profile is switched.
+type profileServices struct {
+	storage         *sqlite.Storage
+	stopJobs        context.CancelFunc // stops the background jobs, set by startJobs
+	taskService     TaskService
+	projectService  ProjectService
+	tagService      TagService
+	transferService TransferService
+	backupService   BackupService
+	settingsService SettingsService
+	folderSync      *internal.FolderSync
+	caldavSync      *internal.CalDAVSync
+	scheduler       *internal.Scheduler
+
+	// calls counts the calls and background jobs using the database, it is closed once they are done
+	calls sync.WaitGroup
 }
 
 type TaskService interface {
 	GetAll(ctx context.Context) ([]domain.Task, error)
+	GetByProject(ctx context.Context, projectID string) ([]domain.Task, error)
+	List(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error)
+	Search(ctx context.Context, query string) ([]domain.SearchResult, error)
 	GetByID(ctx context.Context, id string) (domain.Task, error)
 	Create(ctx context.Context, request domain.CreateTaskRequest) (domain.Task, error)
+	CreateSubtask(ctx context.Context, parentID string, request domain.CreateTaskRequest) (domain.Task, error)
+	GetChildren(ctx context.Context, parentID string) ([]domain.Task, error)
 	Update(ctx context.Context, request domain.UpdateTaskRequest) (domain.Task, error)
+	MoveTask(ctx context.Context, request domain.MoveTaskRequest) (domain.Task, error)
 	Delete(ctx context.Context, id string) error
+	GetTrash(ctx context.Context) ([]domain.Task, error)
+	Restore(ctx context.Context, id string) (domain.Task, error)
+	EmptyTrash(ctx context.Context) (int64, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	GetTaskHistory(ctx context.Context, id string) ([]domain.TaskEvent, error)
+	Undo(ctx context.Context) (domain.HistoryEntry, error)
+	Redo(ctx context.Context) (domain.HistoryEntry, error)
+}
+
+type ProjectService interface {
+	GetAll(ctx context.Context) ([]domain.Project, error)
+	GetByID(ctx context.Context, id string) (domain.Project, error)
+	Create(ctx context.Context, request domain.CreateProjectRequest) (domain.Project, error)
+	Update(ctx context.Context, request domain.UpdateProjectRequest) (domain.Project, error)
+	Delete(ctx context.Context, id string, mode domain.ProjectDeleteMode) error
+}
+
+type TagService interface {
+	GetAll(ctx context.Context) ([]domain.Tag, error)
+	Update(ctx context.Context, request domain.UpdateTagRequest) (domain.Tag, error)
+	Merge(ctx context.Context, request domain.MergeTagsRequest) (domain.Tag, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type TransferService interface {
+	ExportJSON(ctx context.Context, w io.Writer) error
+	ImportJSON(ctx context.Context, r io.Reader, mode domain.ImportMode) (domain.ImportReport, error)
+	ExportCSV(ctx context.Context, w io.Writer, columns []domain.CSVColumn) error
+	ImportCSV(ctx context.Context, r io.Reader, options domain.CSVImportOptions) (domain.CSVImportResult, error)
+	ExportTodoTxt(ctx context.Context, w io.Writer) error
+	ImportTodoTxt(ctx context.Context, r io.Reader) (domain.ImportReport, error)
+	TodoTxtSync(path string, clock internal.Clock) *internal.TodoTxtSync
+	ExportICal(ctx context.Context, w io.Writer) error
+	ImportICal(ctx context.Context, r io.Reader) (domain.ImportReport, error)
+	ExportMarkdown(ctx context.Context, w io.Writer, grouping domain.MarkdownGrouping) error
+	ImportMarkdown(ctx context.Context, r io.Reader) (domain.ImportReport, error)
+}
+
+type BackupService interface {
+	List(ctx context.Context) ([]domain.Backup, error)
+	Create(ctx context.Context, reason domain.BackupReason) (domain.Backup, error)
+	CreateIfDue(ctx context.Context, interval time.Duration, now time.Time) (bool, error)
+	Restore(ctx context.Context, name string) error
+}
+
+type SettingsService interface {
+	Get(ctx context.Context) (domain.Settings, error)
+	Update(ctx context.Context, request domain.UpdateSettingsRequest) (domain.Settings, error)
+}
+
+// ProfileEvent is emitted to the frontend with the new domain.Profile after the profile is switched.
+const ProfileEvent = "profile"
+
+// SettingsEvent is emitted to the frontend with the new domain.Settings whenever they change,
+// including after the profile is switched.
+const SettingsEvent = "settings"
+
+// TasksEvent is emitted to the frontend when the tasks were changed outside of the window,
+// through the REST API or by the folder or CalDAV sync.
+const TasksEvent = "tasks"
+
+// caldavTimeout bounds each request to the CalDAV server, so a server that stopped answering does not hold up the sync.
+const caldavTimeout = 30 * time.Second
+
+// NewApp creates a new App application struct with the database of the active profile opened
+func NewApp(cfg config.Config) (*App, error) {
+	app := &App{
+		config: cfg,
+	}
+	services, settings, err := openProfile(cfg.DatabasePath(cfg.Profile))
+	if err != nil {
+		return nil, err
+	}
+	app.active = services
+	app.setSettings(settings)
+
+	return app, nil
 }
 
-// NewApp creates a new App application struct
-func NewApp() *App {
-	sqliteDB, err := sqlite.NewStorage()
+// openProfile opens the database at the path and sets up the services on top of it.
+func openProfile(path string) (*profileServices, domain.Settings, error) {
+	sqliteDB, err := sqlite.NewStorage(path)
 	if err != nil {
-		panic(err)
+		return nil, domain.Settings{}, err
 	}
-	taskService := internal.NewTask(sqliteDB, sqliteDB)
 
-	return &App{taskService: taskService}
+	settingsService := internal.NewSettings(sqliteDB)
+	settings, err := settingsService.Get(context.Background())
+	if err != nil {
+		sqliteDB.Close()
+		return nil, domain.Settings{}, err
+	}
+
+	return &profileServices{
+		storage:         sqliteDB,
+		taskService:     internal.NewTask(sqliteDB, sqliteDB, sqliteDB),
+		projectService:  internal.NewProject(sqliteDB, sqliteDB),
+		tagService:      internal.NewTag(sqliteDB, sqliteDB),
+		transferService: internal.NewTransfer(sqliteDB, sqliteDB),
+		backupService:   internal.NewBackups(sqliteDB, domain.DefaultBackupsKept),
+		settingsService: settingsService,
+		folderSync:      internal.NewFolderSync(sqliteDB, internal.SystemClock{}),
+		caldavSync:      internal.NewCalDAVSync(sqliteDB, config.CalDAVPassword(path), &http.Client{Timeout: caldavTimeout}, internal.SystemClock{}),
+		scheduler:       internal.NewScheduler(sqliteDB, eventNotifier{}, internal.SystemClock{}),
+	}, settings, nil
+}
+
+// useProfile returns the services of the active profile, done must be called once the caller no longer
+// needs them. A profile switch does not wait for the callers, but closes the database only after them.
+func (a *App) useProfile() (*profileServices, func()) {
+	a.profileMu.RLock()
+	defer a.profileMu.RUnlock()
+
+	services := a.active
+	services.calls.Add(1)
+	return services, services.calls.Done
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.startJobs(a.active)
+
+	if address, ok := a.config.APIAddress(); ok {
+		go a.serveAPI(ctx, address)
+	}
+}
+
+// serveAPI serves the REST API with the services of the active profile until the app shuts down.
+func (a *App) serveAPI(ctx context.Context, address string) {
+	token, err := a.config.APIToken()
+	if err != nil {
+		fmt.Println("Error starting api:", err)
+		return
+	}
+
+	server := api.NewServer(token, a.apiTaskService, a.tasksChanged)
+	if err := server.Run(ctx, address); err != nil {
+		fmt.Println("Error serving api:", err)
+	}
+}
+
+// apiTaskService returns the task service of the active profile for an API request.
+func (a *App) apiTaskService() (api.TaskService, func()) {
+	services, done := a.useProfile()
+	return services.taskService, done
+}
+
+// startJobs runs the background jobs of the profile until the profile is switched or the app shuts down.
+func (a *App) startJobs(services *profileServices) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	services.stopJobs = cancel
+
+	jobs := []func(){
+		func() { a.purgeTrash(ctx, services.taskService) },
+		func() { a.backUpOnSchedule(ctx, services.backupService) },
+		func() { services.scheduler.Run(ctx) },
+		func() { services.folderSync.Run(ctx, a.tasksChanged) },
+		func() { services.caldavSync.Run(ctx, a.tasksChanged) },
+	}
+	for _, job := range jobs {
+		services.calls.Add(1)
+		go func(job func()) {
+			defer services.calls.Done()
+			job()
+		}(job)
+	}
+}
+
+// tasksChanged refreshes the reminders and the window after the tasks were changed outside of the window.
+func (a *App) tasksChanged() {
+	p, done := a.useProfile()
+	defer done()
+
+	p.scheduler.Reschedule()
+	runtime.EventsEmit(a.ctx, TasksEvent)
+}
+
+// purgeTrash permanently deletes the tasks kept in the trash for longer than the retention setting,
+// once at startup and then every hour until the app shuts down.
+func (a *App) purgeTrash(ctx context.Context, taskService TaskService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := taskService.PurgeTrash(ctx, a.currentSettings().TrashRetention()); err != nil {
+			fmt.Println("Error purging trash:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// backUpOnSchedule backs the database up whenever the last scheduled backup is older than the backup interval,
+// checking once at startup and then every hour until the app shuts down.
+func (a *App) backUpOnSchedule(ctx context.Context, backupService BackupService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := backupService.CreateIfDue(ctx, domain.DefaultBackupInterval, time.Now()); err != nil {
+			fmt.Println("Error backing up:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // Greet returns a greeting for the given name
@@ -46,34 +287,783 @@ func (a *App) Greet(name string) string {
 }
 
 func (a *App) GetAllTasks() ([]domain.Task, error) {
-	return a.taskService.GetAll(a.ctx)
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetAll(a.ctx)
+}
+
+// ListTasks returns a page of tasks matching the query, pass the returned NextCursor to get the next page.
+func (a *App) ListTasks(query domain.TaskQuery) (domain.TaskPage, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.List(a.ctx, query)
+}
+
+// SearchTasks finds tasks by words in their title, description or tags, e.g. `deploy* tag:backend "release notes"`.
+func (a *App) SearchTasks(query string) ([]domain.SearchResult, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.Search(a.ctx, query)
+}
+
+// GetProjectTasks returns the tasks of the project, an empty projectID returns the tasks in the inbox.
+func (a *App) GetProjectTasks(projectID string) ([]domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetByProject(a.ctx, projectID)
 }
 
 func (a *App) GetTaskByID(id string) (domain.Task, error) {
-	return a.taskService.GetByID(a.ctx, id)
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetByID(a.ctx, id)
+}
+
+// GetTaskHistory returns the changes made to the task, oldest first.
+func (a *App) GetTaskHistory(id string) ([]domain.TaskEvent, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetTaskHistory(a.ctx, id)
 }
 
 func (a *App) CreateTask(request domain.CreateTaskRequest) (domain.Task, error) {
-	return a.taskService.Create(a.ctx, request)
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Create(a.ctx, request)
+}
+
+func (a *App) CreateSubtask(parentID string, request domain.CreateTaskRequest) (domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.CreateSubtask(a.ctx, parentID, request)
+}
+
+func (a *App) GetChildren(parentID string) ([]domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetChildren(a.ctx, parentID)
 }
 
 func (a *App) UpdateTask(request domain.UpdateTaskRequest) (domain.Task, error) {
-	return a.taskService.Update(a.ctx, request)
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Update(a.ctx, request)
+}
+
+func (a *App) MoveTask(request domain.MoveTaskRequest) (domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.MoveTask(a.ctx, request)
 }
 
 func (a *App) DeleteTask(id string) error {
-	if !a.confirmDeleteTask() {
+	if a.currentSettings().ConfirmDeletion && !a.confirmDeletion("Are you sure you want to move this task to the trash?") {
+		return domain.ErrCancelled
+	}
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Delete(a.ctx, id)
+}
+
+// GetTrash returns the deleted tasks that can still be restored.
+func (a *App) GetTrash() ([]domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.taskService.GetTrash(a.ctx)
+}
+
+func (a *App) RestoreTask(id string) (domain.Task, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Restore(a.ctx, id)
+}
+
+// EmptyTrash permanently deletes every task in the trash.
+func (a *App) EmptyTrash() error {
+	if !a.confirmDeletion("Are you sure you want to permanently delete all tasks in the trash?") {
+		return domain.ErrCancelled
+	}
+
+	p, done := a.useProfile()
+	defer done()
+
+	_, err := p.taskService.EmptyTrash(a.ctx)
+	return err
+}
+
+// Undo reverts the most recent task change, the returned entry holds the task before and after it.
+func (a *App) Undo() (domain.HistoryEntry, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Undo(a.ctx)
+}
+
+// Redo applies the most recently undone task change again.
+func (a *App) Redo() (domain.HistoryEntry, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.taskService.Redo(a.ctx)
+}
+
+func (a *App) GetAllProjects() ([]domain.Project, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.projectService.GetAll(a.ctx)
+}
+
+func (a *App) GetProjectByID(id string) (domain.Project, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.projectService.GetByID(a.ctx, id)
+}
+
+func (a *App) CreateProject(request domain.CreateProjectRequest) (domain.Project, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.projectService.Create(a.ctx, request)
+}
+
+func (a *App) UpdateProject(request domain.UpdateProjectRequest) (domain.Project, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.projectService.Update(a.ctx, request)
+}
+
+func (a *App) DeleteProject(id string, mode domain.ProjectDeleteMode) error {
+	message := "Are you sure you want to delete this project? Its tasks will be moved to the inbox."
+	if mode == domain.ProjectDeleteTasks {
+		message = "Are you sure you want to delete this project and move all of its tasks to the trash?"
+	}
+	if !a.confirmDeletion(message) {
+		return domain.ErrCancelled
+	}
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.projectService.Delete(a.ctx, id, mode)
+}
+
+// GetAllTags returns every tag with the number of tasks using it.
+func (a *App) GetAllTags() ([]domain.Tag, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.tagService.GetAll(a.ctx)
+}
+
+// UpdateTag renames or recolors the tag on every task.
+func (a *App) UpdateTag(request domain.UpdateTagRequest) (domain.Tag, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.tagService.Update(a.ctx, request)
+}
+
+func (a *App) MergeTags(request domain.MergeTagsRequest) (domain.Tag, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.tagService.Merge(a.ctx, request)
+}
+
+func (a *App) DeleteTag(id string) error {
+	if !a.confirmDeletion("Are you sure you want to delete this tag? It will be removed from all tasks.") {
+		return domain.ErrCancelled
+	}
+
+	p, done := a.useProfile()
+	defer done()
+
+	return p.tagService.Delete(a.ctx, id)
+}
+
+// ExportJSON asks where to save the export and writes every task and project there.
+// It returns the path of the written file.
+func (a *App) ExportJSON() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Tasks",
+		DefaultFilename: "tasks.json",
+		Filters:         []runtime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.transferService.ExportJSON(a.ctx, file); err != nil {
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// ImportJSON asks for a file written by ExportJSON and imports it, replacing everything in replace mode.
+func (a *App) ImportJSON(mode domain.ImportMode) (domain.ImportReport, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Tasks",
+		Filters: []runtime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+	})
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	if path == "" {
+		return domain.ImportReport{}, domain.ErrCancelled
+	}
+	if mode == domain.ImportReplace && !a.confirmDeletion("Are you sure you want to replace all tasks and projects with the imported ones?") {
+		return domain.ImportReport{}, domain.ErrCancelled
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.transferService.ImportJSON(a.ctx, file, mode)
+}
+
+// ExportCSV asks where to save the export and writes the tasks there with the given columns.
+// It returns the path of the written file.
+func (a *App) ExportCSV(columns []domain.CSVColumn) (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Tasks",
+		DefaultFilename: "tasks.csv",
+		Filters:         []runtime.FileFilter{{DisplayName: "CSV (*.csv)", Pattern: "*.csv"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.transferService.ExportCSV(a.ctx, file, columns); err != nil {
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// OpenCSVFile asks for a CSV file to import and returns its path, so it can be previewed
+// with a dry run before it is imported.
+func (a *App) OpenCSVFile() (string, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Tasks",
+		Filters: []runtime.FileFilter{{DisplayName: "CSV (*.csv)", Pattern: "*.csv"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	return path, nil
+}
+
+// ImportCSV imports the CSV file at the path, with DryRun it only returns what would be imported.
+func (a *App) ImportCSV(path string, options domain.CSVImportOptions) (domain.CSVImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.CSVImportResult{}, err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.transferService.ImportCSV(a.ctx, file, options)
+}
+
+// ExportTodoTxt asks where to save the export and writes the tasks there in the todo.txt format.
+// It returns the path of the written file.
+func (a *App) ExportTodoTxt() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Tasks",
+		DefaultFilename: "todo.txt",
+		Filters:         []runtime.FileFilter{{DisplayName: "todo.txt (*.txt)", Pattern: "*.txt"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.transferService.ExportTodoTxt(a.ctx, file); err != nil {
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// ImportTodoTxt asks for a todo.txt file and imports its tasks, updating the tasks named by the id: tags of its lines
+// and, for lines without a tag, the tasks with the same title.
+func (a *App) ImportTodoTxt() (domain.ImportReport, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Tasks",
+		Filters: []runtime.FileFilter{{DisplayName: "todo.txt (*.txt)", Pattern: "*.txt"}},
+	})
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	if path == "" {
+		return domain.ImportReport{}, domain.ErrCancelled
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.transferService.ImportTodoTxt(a.ctx, file)
+}
+
+// ExportICal asks where to save the export and writes the tasks there as iCalendar to-dos.
+// It returns the path of the written file.
+func (a *App) ExportICal() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Tasks",
+		DefaultFilename: "tasks.ics",
+		Filters:         []runtime.FileFilter{{DisplayName: "iCalendar (*.ics)", Pattern: "*.ics"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.transferService.ExportICal(a.ctx, file); err != nil {
+		return "", err
+	}
+
+	return path, file.Close()
+}
+
+// ImportICal asks for an iCalendar file and imports its to-dos, updating the tasks with the same UID.
+func (a *App) ImportICal() (domain.ImportReport, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "Import Tasks",
+		Filters: []runtime.FileFilter{{DisplayName: "iCalendar (*.ics)", Pattern: "*.ics"}},
+	})
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	if path == "" {
+		return domain.ImportReport{}, domain.ErrCancelled
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	defer file.Close()
+
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.transferService.ImportICal(a.ctx, file)
+}
+
+// ExportMarkdown returns the tasks as a Markdown checklist, grouped into sections by tag or status,
+// ready to be copied into notes or a pull request description.
+func (a *App) ExportMarkdown(grouping domain.MarkdownGrouping) (string, error) {
+	var b strings.Builder
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.transferService.ExportMarkdown(a.ctx, &b, grouping); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ImportMarkdown creates a task for every checklist item of the pasted Markdown document.
+func (a *App) ImportMarkdown(document string) (domain.ImportReport, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.transferService.ImportMarkdown(a.ctx, strings.NewReader(document))
+}
+
+// StartTodoTxtSync asks for a todo.txt file, which may not exist yet, and keeps it in sync with the tasks
+// until StopTodoTxtSync is called. It returns the path of the synced file.
+func (a *App) StartTodoTxtSync() (string, error) {
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Sync with todo.txt",
+		DefaultFilename: "todo.txt",
+		Filters:         []runtime.FileFilter{{DisplayName: "todo.txt (*.txt)", Pattern: "*.txt"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", domain.ErrCancelled
+	}
+
+	// profileMu is held until the sync is registered, so a profile switch waits for it and then stops it,
+	// instead of leaving it running on the database of the previous profile
+	a.profileMu.RLock()
+	defer a.profileMu.RUnlock()
+	p := a.active
+
+	a.todoTxtMu.Lock()
+	defer a.todoTxtMu.Unlock()
+
+	a.stopTodoTxtSyncLocked()
+
+	todoTxtSync := p.transferService.TodoTxtSync(path, internal.SystemClock{})
+	defer p.scheduler.Reschedule()
+	// the first sync merges the file with the tasks, a file that cannot be read is reported right away
+	if err := todoTxtSync.Sync(a.ctx); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.todoTxtSync = todoTxtSync
+	a.stopTodoTxtSync = cancel
+	p.calls.Add(1)
+	go func() {
+		defer p.calls.Done()
+		todoTxtSync.Run(ctx)
+	}()
+
+	return path, nil
+}
+
+// StopTodoTxtSync stops syncing the todo.txt file, the file and the tasks are left as they are.
+func (a *App) StopTodoTxtSync() {
+	a.todoTxtMu.Lock()
+	defer a.todoTxtMu.Unlock()
+
+	a.stopTodoTxtSyncLocked()
+}
+
+func (a *App) stopTodoTxtSyncLocked() {
+	if a.stopTodoTxtSync != nil {
+		a.stopTodoTxtSync()
+	}
+	a.todoTxtSync = nil
+	a.stopTodoTxtSync = nil
+}
+
+// StartFolderSync asks for a shared folder, e.g. one synced by Syncthing or Dropbox, and syncs the tasks
+// of the profile with the other devices using the same folder. It returns the path of the folder.
+func (a *App) StartFolderSync() (string, error) {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Sync through a shared folder",
+		CanCreateDirectories: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		return "", domain.ErrCancelled
+	}
+
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.folderSync.SetDirectory(a.ctx, dir); err != nil {
+		return "", err
+	}
+	defer p.scheduler.Reschedule()
+	// the first sync merges the tasks of the devices that already sync through the folder
+	if _, err := p.folderSync.Sync(a.ctx); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// StopFolderSync stops syncing through the shared folder, the tasks and the folder are left as they are.
+func (a *App) StopFolderSync() error {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.folderSync.SetDirectory(a.ctx, "")
+}
+
+// GetFolderSyncPath returns the path of the shared folder, empty while the folder sync is off.
+func (a *App) GetFolderSyncPath() (string, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.folderSync.Directory(a.ctx)
+}
+
+// ConnectCalDAV syncs the tasks of the profile with a task list on a CalDAV server, which phones can sync with too.
+// The URL is the one of the task list, e.g. https://dav.example.com/alice/tasks/, the first sync is made right away.
+func (a *App) ConnectCalDAV(account domain.CalDAVAccount) (domain.CalDAVReport, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	if err := p.caldavSync.SetAccount(a.ctx, account); err != nil {
+		return domain.CalDAVReport{}, err
+	}
+	defer p.scheduler.Reschedule()
+
+	return p.caldavSync.Sync(a.ctx)
+}
+
+// SyncCalDAV syncs with the CalDAV server now instead of waiting for the next sync.
+func (a *App) SyncCalDAV() (domain.CalDAVReport, error) {
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.caldavSync.Sync(a.ctx)
+}
+
+// DisconnectCalDAV stops syncing with the CalDAV server, the tasks are left as they are on both sides.
+func (a *App) DisconnectCalDAV() error {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.caldavSync.SetAccount(a.ctx, domain.CalDAVAccount{})
+}
+
+// GetCalDAVAccount returns the CalDAV account without its password, its URL is empty while the CalDAV sync is off.
+func (a *App) GetCalDAVAccount() (domain.CalDAVAccount, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.caldavSync.Account(a.ctx)
+}
+
+// GetTodoTxtSyncPath returns the path of the synced todo.txt file, empty while the sync is off.
+func (a *App) GetTodoTxtSyncPath() string {
+	a.todoTxtMu.Lock()
+	defer a.todoTxtMu.Unlock()
+
+	if a.todoTxtSync == nil {
+		return ""
+	}
+	return a.todoTxtSync.Path()
+}
+
+// ListBackups returns the backups of the database, newest first.
+func (a *App) ListBackups() ([]domain.Backup, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.backupService.List(a.ctx)
+}
+
+// CreateBackup backs the database up right away.
+func (a *App) CreateBackup() (domain.Backup, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.backupService.Create(a.ctx, domain.BackupManual)
+}
+
+// RestoreBackup replaces every task and project with the ones in the backup, after asking for confirmation.
+// The current data is backed up first.
+func (a *App) RestoreBackup(name string) error {
+	if !a.confirmDeletion("Are you sure you want to replace all tasks and projects with the backup?") {
 		return domain.ErrCancelled
 	}
 
-	return a.taskService.Delete(a.ctx, id)
+	p, done := a.useProfile()
+	defer done()
+	defer p.scheduler.Reschedule()
+	return p.backupService.Restore(a.ctx, name)
+}
+
+// ListProfiles returns the profiles that can be switched to.
+func (a *App) ListProfiles() ([]domain.Profile, error) {
+	a.profileMu.RLock()
+	defer a.profileMu.RUnlock()
+
+	return a.config.ListProfiles()
+}
+
+// GetActiveProfile returns the profile whose tasks are shown.
+func (a *App) GetActiveProfile() domain.Profile {
+	a.profileMu.RLock()
+	defer a.profileMu.RUnlock()
+
+	return a.config.ProfileInfo(a.config.Profile)
+}
+
+// SwitchProfile opens the database of the profile, creating the profile when it does not exist yet,
+// and remembers it for the next start. The todo.txt sync of the previous profile is stopped.
+func (a *App) SwitchProfile(name string) (domain.Profile, error) {
+	if err := config.ValidateProfile(name); err != nil {
+		return domain.Profile{}, err
+	}
+
+	a.profileMu.Lock()
+	defer a.profileMu.Unlock()
+
+	if name == a.config.Profile {
+		return a.config.ProfileInfo(name), nil
+	}
+
+	services, settings, err := openProfile(a.config.DatabasePath(name))
+	if err != nil {
+		return domain.Profile{}, err
+	}
+
+	// the jobs of the previous profile are stopped before its services are replaced, its database is closed
+	// once the calls still using it are done
+	previous := a.active
+	previous.stopJobs()
+	a.StopTodoTxtSync()
+	a.active = services
+	a.setSettings(settings)
+	a.startJobs(services)
+	go func() {
+		previous.calls.Wait()
+		if err := previous.storage.Close(); err != nil {
+			fmt.Println("Error closing database:", err)
+		}
+	}()
+
+	a.config.Profile = name
+	if err := a.config.Save(); err != nil {
+		fmt.Println("Error saving config:", err)
+	}
+
+	profile := a.config.ProfileInfo(name)
+	runtime.EventsEmit(a.ctx, ProfileEvent, profile)
+	runtime.EventsEmit(a.ctx, SettingsEvent, a.currentSettings())
+	return profile, nil
+}
+
+// GetSettings returns the settings of the active profile.
+func (a *App) GetSettings() (domain.Settings, error) {
+	p, done := a.useProfile()
+	defer done()
+
+	return p.settingsService.Get(a.ctx)
+}
+
+// UpdateSettings changes the given settings of the active profile and applies them right away.
+func (a *App) UpdateSettings(request domain.UpdateSettingsRequest) (domain.Settings, error) {
+	previous := a.currentSettings()
+	p, done := a.useProfile()
+	defer done()
+
+	settings, err := p.settingsService.Update(a.ctx, request)
+	if err != nil {
+		return domain.Settings{}, err
+	}
+
+	a.setSettings(settings)
+	if settings.WindowWidth != previous.WindowWidth || settings.WindowHeight != previous.WindowHeight {
+		runtime.WindowSetSize(a.ctx, settings.WindowWidth, settings.WindowHeight)
+	}
+	runtime.EventsEmit(a.ctx, SettingsEvent, settings)
+
+	return settings, nil
+}
+
+// currentSettings returns the settings of the active profile without reading the database.
+func (a *App) currentSettings() domain.Settings {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	return a.settings
+}
+
+func (a *App) setSettings(settings domain.Settings) {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	a.settings = settings
+}
+
+// formatError is how a rejected call reports its error to the frontend. A domain.ValidationError
+// is passed as an object with a message and the fields, so the invalid fields can be highlighted,
+// other errors are passed as their message.
+func formatError(err error) any {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return struct {
+			Message string              `json:"message"`
+			Fields  []domain.FieldError `json:"fields"`
+		}{
+			Message: validationErr.Error(),
+			Fields:  validationErr.Fields,
+		}
+	}
+	return err.Error()
 }
 
-func (a *App) confirmDeleteTask() bool {
+func (a *App) confirmDeletion(message string) bool {
 	response, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
 		Type:          runtime.QuestionDialog,
 		Title:         "Confirm Deletion",
-		Message:       "Are you sure you want to delete this task?",
+		Message:       message,
 		Buttons:       []string{"Yes", "No"},
 		DefaultButton: "No",
 	})
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// listFlag collects a flag that may be repeated or separated by commas, e.g. -tag work,home -tag urgent.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+func runAdd(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("add", "<title>")
+	priority := flagSet.String("priority", "", "priority: none, low, medium or high (default from the settings)")
+	due := flagSet.String("due", "", "due date, YYYY-MM-DD or in the date format of the settings")
+	project := flagSet.String("project", "", "id of the project, the task goes to the inbox by default")
+	description := flagSet.String("description", "", "description of the task")
+	var tags listFlag
+	flagSet.Var(&tags, "tag", "tag of the task, may be repeated")
+	asJSON := flagSet.Bool("json", false, "print the task as JSON")
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	request := domain.CreateTaskRequest{
+		Title:       strings.Join(arguments, " "),
+		Description: *description,
+		Tags:        tags,
+		Priority:    domain.TaskPriority(*priority),
+	}
+	if *due != "" {
+		dueDate, err := c.parseDate(*due)
+		if err != nil {
+			return err
+		}
+		request.DueDate = &dueDate
+	}
+	if *project != "" {
+		request.ProjectID = project
+	}
+
+	task, err := c.tasks.Create(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return c.printTasks([]domain.Task{task}, *asJSON)
+}
+
+func runList(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("list", "")
+	var statuses, priorities, tags listFlag
+	flagSet.Var(&statuses, "status", "show only tasks with the status: todo or done, may be repeated")
+	flagSet.Var(&priorities, "priority", "show only tasks with the priority: none, low, medium or high, may be repeated")
+	flagSet.Var(&tags, "tag", "show only tasks with the tag, may be repeated to require every tag")
+	project := flagSet.String("project", "", "show only tasks of the project with the id")
+	inbox := flagSet.Bool("inbox", false, "show only tasks in the inbox")
+	subtasks := flagSet.Bool("subtasks", false, "show subtasks too")
+	sortBy := flagSet.String("sort", string(domain.TaskSortCreatedAt), "sort by created_at, modified_at, due_date, priority, title or position")
+	desc := flagSet.Bool("desc", false, "sort in descending order")
+	limit := flagSet.Int("limit", 0, "show at most this many tasks, all of them by default")
+	asJSON := flagSet.Bool("json", false, "print the tasks as JSON")
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	query := domain.TaskQuery{
+		IncludeSubtasks: *subtasks,
+		Tags:            tags,
+		SortBy:          domain.TaskSortKey(*sortBy),
+		SortDirection:   domain.SortAsc,
+		Limit:           domain.MaxTaskQueryLimit,
+	}
+	for _, status := range statuses {
+		query.Statuses = append(query.Statuses, domain.TaskStatus(status))
+	}
+	for _, priority := range priorities {
+		query.Priorities = append(query.Priorities, domain.TaskPriority(priority))
+	}
+	switch {
+	case *inbox:
+		query.ProjectID = new(string)
+	case *project != "":
+		query.ProjectID = project
+	}
+	if *desc {
+		query.SortDirection = domain.SortDesc
+	}
+	if *limit > 0 {
+		query.Limit = min(*limit, domain.MaxTaskQueryLimit)
+	}
+
+	var tasks []domain.Task
+	for {
+		page, err := c.tasks.List(ctx, query)
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, page.Tasks...)
+		if page.NextCursor == "" || (*limit > 0 && len(tasks) >= *limit) {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if *limit > 0 && len(tasks) > *limit {
+		tasks = tasks[:*limit]
+	}
+
+	return c.printTasks(tasks, *asJSON)
+}
+
+func runEdit(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("edit", "<id>")
+	title := flagSet.String("title", "", "new title")
+	description := flagSet.String("description", "", "new description")
+	priority := flagSet.String("priority", "", "new priority: none, low, medium or high")
+	status := flagSet.String("status", "", "new status: todo or done")
+	due := flagSet.String("due", "", "new due date, YYYY-MM-DD or in the date format of the settings")
+	project := flagSet.String("project", "", "id of the project to move the task to, empty for the inbox")
+	var tags listFlag
+	flagSet.Var(&tags, "tag", "tag of the task, replaces the current tags, may be repeated")
+	asJSON := flagSet.Bool("json", false, "print the task as JSON")
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) != 1 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	task, err := c.getTask(ctx, arguments[0])
+	if err != nil {
+		return err
+	}
+
+	request := domain.UpdateTaskRequest{
+		ID:       task.ID,
+		Version:  task.Version,
+		Title:    *title,
+		Status:   domain.TaskStatus(*status),
+		Priority: domain.TaskPriority(*priority),
+	}
+	set := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["description"] {
+		request.Description = description
+	}
+	if set["project"] {
+		request.ProjectID = project
+	}
+	if set["tag"] {
+		request.Tags = append([]string{}, tags...) // no tags given removes them
+	}
+	if set["due"] {
+		dueDate, err := c.parseDate(*due)
+		if err != nil {
+			return err
+		}
+		request.DueDate = &dueDate
+	}
+
+	task, err = c.tasks.Update(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return c.printTasks([]domain.Task{task}, *asJSON)
+}
+
+func runComplete(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("complete", "<id>...")
+	children := flagSet.Bool("children", false, "complete the unfinished subtasks too")
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	for _, id := range arguments {
+		task, err := c.getTask(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.tasks.Update(ctx, domain.UpdateTaskRequest{
+			ID:               task.ID,
+			Version:          task.Version,
+			Status:           domain.TaskStatusDone,
+			CompleteChildren: *children,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func runDelete(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("delete", "<id>...")
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	for _, id := range arguments {
+		task, err := c.getTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := c.tasks.Delete(ctx, task.ID); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// parseDate reads a date in the ISO format or the date format of the settings, in the local time zone.
+func (c *cli) parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{domain.DateFormatISO.Layout(), c.dateLayout} {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, domain.NewValidationError("due_date", domain.ValidationInvalid, fmt.Sprintf("%q is not a date like %s", value, c.dateLayout))
+}
@@ -0,0 +1,222 @@
+// Command todo manages the tasks of the todo app from the terminal, on the same database as the app.
+//
+//	todo [-config file] [-db file] [-profile name] <command> [flags] [arguments]
+//
+// The commands are add, list, edit, complete, delete and migrate, run "todo <command> -h" for their flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ARUMANDESU/todo-app/internal"
+	"github.com/ARUMANDESU/todo-app/internal/config"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+)
+
+const (
+	exitError = 1
+	exitUsage = 2
+)
+
+// command runs with the flags and arguments following its name.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, cli *cli, args []string) error
+	// schema is set for commands working on the schema of the database,
+	// which is then neither migrated nor opened before they run.
+	schema bool
+}
+
+var commands = []command{
+	{"add", "add a task", runAdd, false},
+	{"list", "list tasks", runList, false},
+	{"edit", "change a task", runEdit, false},
+	{"complete", "mark tasks as done", runComplete, false},
+	{"delete", "move tasks to the trash", runDelete, false},
+	{"migrate", "show, apply or roll back schema migrations", runMigrate, true},
+}
+
+// cli is what the commands share: the task service of the profile and where to write.
+type cli struct {
+	database   string // path of the database of the profile
+	tasks      internal.Task
+	dateLayout string // of the date format setting of the profile
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+// errUsage is returned for invalid flags or arguments, after the usage has been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	log.SetOutput(io.Discard) // the storage logs what it does, which is noise in a terminal
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command line and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var flags config.Flags
+	flagSet := flag.NewFlagSet("todo", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	flagSet.StringVar(&flags.Config, "config", "", "path of the config file")
+	flagSet.StringVar(&flags.Database, "db", "", "path of the database of the active profile")
+	flagSet.StringVar(&flags.Profile, "profile", "", "name of the profile to use")
+	flagSet.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: todo [flags] <command> [command flags] [arguments]")
+		fmt.Fprintln(stderr, "\nCommands:")
+		for _, command := range commands {
+			fmt.Fprintf(stderr, "  %-10s %s\n", command.name, command.summary)
+		}
+		fmt.Fprintln(stderr, "\nFlags:")
+		flagSet.PrintDefaults()
+	}
+	if err := flagSet.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		return exitUsage
+	}
+
+	if flagSet.NArg() == 0 {
+		flagSet.Usage()
+		return exitUsage
+	}
+	name := flagSet.Arg(0)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "todo: unknown command %q\n", name)
+		flagSet.Usage()
+		return exitUsage
+	}
+
+	database, err := databasePath(flags, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "todo:", err)
+		return exitError
+	}
+
+	c := &cli{database: database, stdout: stdout, stderr: stderr}
+	if !cmd.schema {
+		storage, err := sqlite.NewStorage(database)
+		if err != nil {
+			fmt.Fprintln(stderr, "todo:", err)
+			if errors.Is(err, sqlite.ErrMigration) {
+				fmt.Fprintln(stderr, `todo: run "todo migrate status" to check the schema of the database`)
+			}
+			return exitError
+		}
+		defer storage.Close()
+
+		settings, err := internal.NewSettings(storage).Get(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, "todo:", err)
+			return exitError
+		}
+
+		c.tasks = internal.NewTask(storage, storage, storage)
+		c.dateLayout = settings.DateFormat.Layout()
+	}
+
+	if err := cmd.run(ctx, c, flagSet.Args()[1:]); errors.Is(err, errUsage) {
+		return exitUsage
+	} else if err != nil {
+		fmt.Fprintf(stderr, "todo %s: %v\n", name, err)
+		return exitError
+	}
+
+	return 0
+}
+
+// databasePath finds the database of the profile the same way the app does.
+func databasePath(flags config.Flags, stderr io.Writer) (string, error) {
+	cfg, err := config.Load(flags)
+	if err != nil {
+		return "", err
+	}
+	if moved, err := cfg.MigrateLegacyData(); err != nil {
+		fmt.Fprintln(stderr, "todo: failed to move the database out of the cache directory:", err)
+	} else if moved {
+		fmt.Fprintln(stderr, "todo: moved the database to", cfg.DatabasePath(config.DefaultProfile))
+	}
+
+	return cfg.DatabasePath(cfg.Profile), nil
+}
+
+// newFlagSet returns the flag set of a command, its usage lists the arguments after the flags.
+func (c *cli) newFlagSet(name, arguments string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.SetOutput(c.stderr)
+	flagSet.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: todo %s [flags] %s\n", name, arguments)
+		flagSet.PrintDefaults()
+	}
+	return flagSet
+}
+
+// parse parses the flags of a command, the flags may come before or after the arguments.
+func parse(flagSet *flag.FlagSet, args []string) ([]string, error) {
+	var arguments []string
+	for {
+		if err := flagSet.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = flagSet.Args()
+		if len(args) == 0 {
+			return arguments, nil
+		}
+		arguments = append(arguments, args[0])
+		args = args[1:]
+	}
+}
+
+// getTask returns the task with the id, a unique prefix of the id is enough.
+func (c *cli) getTask(ctx context.Context, id string) (domain.Task, error) {
+	if id == "" {
+		return domain.Task{}, fmt.Errorf("empty id: %w", domain.ErrTaskNotFound)
+	}
+
+	task, err := c.tasks.GetByID(ctx, id)
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		return task, err
+	}
+
+	var matches []domain.Task
+	query := domain.TaskQuery{IncludeSubtasks: true, Limit: domain.MaxTaskQueryLimit}
+	for {
+		page, err := c.tasks.List(ctx, query)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		for _, task := range page.Tasks {
+			if strings.HasPrefix(task.ID, id) {
+				matches = append(matches, task)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	switch len(matches) {
+	case 0:
+		return domain.Task{}, fmt.Errorf("%s: %w", id, domain.ErrTaskNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		return domain.Task{}, fmt.Errorf("%s: matches %d tasks, give more of the id", id, len(matches))
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testCLI struct {
+	t        *testing.T
+	database string
+}
+
+func newTestCLI(t *testing.T) *testCLI {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	for _, env := range []string{"TODO_APP_CONFIG", "TODO_APP_DB", "TODO_APP_PROFILE"} {
+		t.Setenv(env, "")
+	}
+	return &testCLI{t: t, database: filepath.Join(dir, "tasks.db")}
+}
+
+func (c *testCLI) run(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(context.Background(), append([]string{"-db", c.database}, args...), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func (c *testCLI) list(args ...string) []domain.Task {
+	code, stdout, stderr := c.run(append([]string{"list", "-json"}, args...)...)
+	require.Equal(c.t, 0, code, stderr)
+
+	var tasks []domain.Task
+	require.NoError(c.t, json.Unmarshal([]byte(stdout), &tasks))
+	return tasks
+}
+
+func TestCLI(t *testing.T) {
+	c := newTestCLI(t)
+
+	code, stdout, stderr := c.run("add", "-priority", "high", "-tag", "work,urgent", "-due", "2099-01-02", "-description", "Quarterly numbers", "Write", "the", "report")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "Write the report")
+	assert.Contains(t, stdout, "2099-01-02")
+
+	code, _, stderr = c.run("add", "Buy milk", "-tag", "home")
+	require.Equal(t, 0, code, stderr)
+
+	tasks := c.list()
+	require.Len(t, tasks, 2)
+	assert.Equal(t, domain.TaskPriorityHigh, tasks[0].Priority)
+	assert.Equal(t, []string{"work", "urgent"}, []string(tasks[0].Tags))
+	assert.Equal(t, "Quarterly numbers", tasks[0].Description)
+	assert.Equal(t, domain.TaskPriorityNone, tasks[1].Priority, "the default priority of the settings")
+
+	t.Run("filters", func(t *testing.T) {
+		assert.Len(t, c.list("-tag", "work"), 1)
+		assert.Len(t, c.list("-priority", "low,medium"), 0)
+		assert.Len(t, c.list("-status", "todo", "-limit", "1"), 1)
+		assert.Equal(t, "Buy milk", c.list("-sort", "title")[0].Title)
+	})
+
+	milk := tasks[1].ID
+
+	t.Run("edit by id prefix", func(t *testing.T) {
+		code, _, stderr := c.run("edit", "-title", "Buy oat milk", "-tag", "", milk[:13])
+		require.Equal(t, 0, code, stderr)
+
+		edited := c.list("-sort", "title")[0]
+		assert.Equal(t, "Buy oat milk", edited.Title)
+		assert.Empty(t, edited.Tags)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		code, _, stderr := c.run("complete", milk)
+		require.Equal(t, 0, code, stderr)
+
+		done := c.list("-status", "done")
+		require.Len(t, done, 1)
+		assert.Equal(t, milk, done[0].ID)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		code, _, stderr := c.run("delete", milk)
+		require.Equal(t, 0, code, stderr)
+
+		assert.Len(t, c.list(), 1)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		code, stdout, _ := c.run("list")
+		require.Equal(t, 0, code)
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "ID"))
+		assert.True(t, strings.HasPrefix(lines[1], tasks[0].ID[:shortIDLength]))
+	})
+}
+
+func TestCLI_Errors(t *testing.T) {
+	c := newTestCLI(t)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"no command", nil, exitUsage, "Usage"},
+		{"unknown command", []string{"start"}, exitUsage, "unknown command"},
+		{"missing title", []string{"add"}, exitUsage, "Usage: todo add"},
+		{"unknown flag", []string{"list", "-color"}, exitUsage, "-color"},
+		{"invalid title", []string{"add", "ab"}, exitError, "title"},
+		{"invalid date", []string{"add", "-due", "someday", "Write report"}, exitError, "due_date"},
+		{"invalid tag", []string{"add", "-tag", "ab", "Buy bread"}, exitError, "tags"},
+		{"unknown priority", []string{"add", "-priority", "urgent", "Buy bread"}, exitError, "priority"},
+		{"unknown task", []string{"complete", "nope"}, exitError, domain.ErrTaskNotFound.Error()},
+		{"unknown project", []string{"add", "-project", "nope", "Buy bread"}, exitError, domain.ErrProjectNotFound.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := c.run(tt.args...)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+
+	assert.Empty(t, c.list(), "a rejected task is not created")
+}
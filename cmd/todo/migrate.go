@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+)
+
+const migrateUsage = `Usage:
+  todo migrate status          show the schema version of the database
+  todo migrate up [steps]      apply the next steps migrations, all pending ones by default
+  todo migrate down <steps>    roll back the last steps migrations
+  todo migrate force <version> mark the database as migrated to the version, after repairing a failed migration
+
+The database is backed up before migrating, into the backups directory next to it.`
+
+func runMigrate(ctx context.Context, c *cli, args []string) error {
+	flagSet := c.newFlagSet("migrate", "")
+	flagSet.Usage = func() {
+		fmt.Fprintln(c.stderr, migrateUsage)
+	}
+
+	arguments, err := parse(flagSet, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+
+	switch action, arguments := arguments[0], arguments[1:]; {
+	case action == "status" && len(arguments) == 0:
+	case action == "up" && len(arguments) <= 1:
+		steps := 0
+		if len(arguments) == 1 {
+			if steps, err = parseCount(arguments[0]); err != nil {
+				flagSet.Usage()
+				return err
+			}
+		}
+		if err := c.migrateSteps(steps); err != nil {
+			return err
+		}
+	case action == "down" && len(arguments) == 1:
+		steps, err := parseCount(arguments[0])
+		if err != nil {
+			flagSet.Usage()
+			return err
+		}
+		if err := c.migrateSteps(-steps); err != nil {
+			return err
+		}
+	case action == "force" && len(arguments) == 1:
+		version, err := strconv.ParseUint(arguments[0], 10, 32)
+		if err != nil {
+			flagSet.Usage()
+			return errUsage
+		}
+		if err := sqlite.ForceMigrationVersion(c.database, uint(version)); err != nil {
+			return err
+		}
+	default:
+		flagSet.Usage()
+		return errUsage
+	}
+
+	status, err := sqlite.MigrationStatus(c.database)
+	if err != nil {
+		return err
+	}
+	c.printMigrationStatus(status)
+	return nil
+}
+
+func (c *cli) migrateSteps(steps int) error {
+	backup, err := sqlite.MigrateSteps(c.database, steps)
+	if backup.Name != "" {
+		fmt.Fprintln(c.stdout, "backed up to", filepath.Join(filepath.Dir(c.database), "backups", backup.Name))
+	}
+	return err
+}
+
+func (c *cli) printMigrationStatus(status domain.MigrationStatus) {
+	fmt.Fprintf(c.stdout, "%s: version %d of %d", c.database, status.Version, status.Latest)
+	switch {
+	case status.Dirty:
+		fmt.Fprintf(c.stdout, ", dirty\nthe migration to version %d failed halfway, repair the database and then run\n", status.Version)
+		fmt.Fprintln(c.stdout, `"todo migrate force <version>" with the version its schema is at`)
+	case status.Pending > 0:
+		fmt.Fprintf(c.stdout, ", %d pending\n", status.Pending)
+	default:
+		fmt.Fprintln(c.stdout, ", up to date")
+	}
+}
+
+// parseCount reads a positive number of steps.
+func parseCount(value string) (int, error) {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 0, errUsage
+	}
+	return count, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"database/sql"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestCLI_Migrate(t *testing.T) {
+	c := newTestCLI(t)
+
+	code, _, stderr := c.run("migrate", "status")
+	assert.Equal(t, exitError, code, "the database is not created by mistake")
+	assert.Contains(t, stderr, "no such file")
+
+	code, _, stderr = c.run("add", "Buy milk")
+	require.Equal(t, 0, code, stderr)
+
+	latest, err := sqlite.MigrationStatus(c.database)
+	require.NoError(t, err)
+	assert.Zero(t, latest.Pending)
+
+	t.Run("down backs up first", func(t *testing.T) {
+		code, stdout, stderr := c.run("migrate", "down", "2")
+		require.Equal(t, 0, code, stderr)
+		assert.Contains(t, stdout, "backed up to")
+		assert.Contains(t, stdout, "2 pending")
+
+		status, err := sqlite.MigrationStatus(c.database)
+		require.NoError(t, err)
+		assert.Equal(t, latest.Version-2, status.Version)
+
+		backups, err := os.ReadDir(filepath.Join(filepath.Dir(c.database), "backups"))
+		require.NoError(t, err)
+		assert.Len(t, backups, 1)
+	})
+
+	t.Run("up", func(t *testing.T) {
+		code, _, stderr := c.run("migrate", "up", "1")
+		require.Equal(t, 0, code, stderr)
+		code, stdout, stderr := c.run("migrate", "up")
+		require.Equal(t, 0, code, stderr)
+		assert.Contains(t, stdout, "up to date")
+
+		code, _, stderr = c.run("migrate", "up", "1")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "only 0 migrations are pending")
+	})
+
+	t.Run("force after a failure", func(t *testing.T) {
+		db, err := sql.Open("sqlite", c.database)
+		require.NoError(t, err)
+		_, err = db.Exec(`UPDATE migrations SET dirty = 1`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		code, _, stderr := c.run("list")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "todo migrate status")
+
+		code, stdout, _ := c.run("migrate", "status")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "dirty")
+
+		code, _, _ = c.run("migrate", "down", "1")
+		assert.Equal(t, exitError, code, "a dirty database is not migrated")
+
+		code, _, stderr = c.run("migrate", "force", "99")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "no migration 99")
+
+		code, stdout, stderr = c.run("migrate", "force", strconv.Itoa(int(latest.Version)))
+		require.Equal(t, 0, code, stderr)
+		assert.Contains(t, stdout, "up to date")
+
+		assert.Len(t, c.list(), 1)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{{}, {"down"}, {"down", "0"}, {"up", "all"}, {"force"}, {"sideways"}} {
+			code, _, _ := c.run(append([]string{"migrate"}, args...)...)
+			assert.Equal(t, exitUsage, code, args)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// shortIDLength is how much of the id the table shows, the commands take any unique prefix of an id.
+const shortIDLength = 8
+
+// printTasks prints the tasks as a table, or as a JSON array of domain.Task.
+func (c *cli) printTasks(tasks []domain.Task, asJSON bool) error {
+	if asJSON {
+		if tasks == nil {
+			tasks = []domain.Task{}
+		}
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(tasks)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tPRIORITY\tDUE\tTAGS\tTITLE")
+	for _, task := range tasks {
+		due := ""
+		if task.DueDate != nil {
+			due = task.DueDate.Local().Format(c.dateLayout)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.ID[:min(len(task.ID), shortIDLength)],
+			task.Status,
+			task.Priority,
+			due,
+			strings.Join(task.Tags, ","),
+			task.Title,
+		)
+	}
+	return w.Flush()
+}
@@ -29,6 +29,7 @@ function TaskItem({task, onClick, isCurrent, onUpdate, onDelete, isHighlighted =
         const request = domain.UpdateTaskRequest.createFrom(
             {
                 id: task.id,
+                version: task.version,
                 status: task.status === TaskStatus.DONE ? TaskStatus.TODO : TaskStatus.DONE
             })
         UpdateTask(request).then(onUpdate).catch((err) => {
@@ -61,7 +61,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
             try {
                 const updatedTask = await UpdateTask(
                     domain.UpdateTaskRequest.createFrom(
-                        {id:task.id, title: e.target.value}
+                        {id: task.id, version: task.version, title: e.target.value}
                     )
                 );
                 onUpdate(updatedTask);
@@ -81,7 +81,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
             try {
                 const updatedTask = await UpdateTask(
                     domain.UpdateTaskRequest.createFrom(
-                        {id: task.id, priority: value}
+                        {id: task.id, version: task.version, priority: value}
                     )
                 );
                 onUpdate(updatedTask);
@@ -101,6 +101,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
                     domain.UpdateTaskRequest.createFrom(
                         {
                             id: task.id,
+                            version: task.version,
                             due_date: new Date(e.target.value).toISOString()
                         }
                     )
@@ -129,7 +130,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
             try {
                 const updatedTask = await UpdateTask(
                     domain.UpdateTaskRequest.createFrom(
-                        {id: task.id, description: e.target.value, tags: tags}
+                        {id: task.id, version: task.version, description: e.target.value, tags: tags}
                     )
                 );
                 onUpdate(updatedTask);
@@ -145,7 +146,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
         try {
             const updatedTask = await UpdateTask(
                 domain.UpdateTaskRequest.createFrom(
-                    {id: task.id, description: description, tags: tags.filter((t) => t !== tag)}
+                    {id: task.id, version: task.version, description: description, tags: tags.filter((t) => t !== tag)}
                 )
             );
             onUpdate(updatedTask);
@@ -163,7 +164,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
         try {
             const updatedTask = await UpdateTask(
                 domain.UpdateTaskRequest.createFrom(
-                    {id: task.id, description: description, tags: [...tags, tag]}
+                    {id: task.id, version: task.version, description: description, tags: [...tags, tag]}
                 )
             );
             onUpdate(updatedTask);
@@ -177,6 +178,7 @@ function TaskSidePanel({task, onUpdate}: TaskSidePanelProps) {
         const request = domain.UpdateTaskRequest.createFrom(
             {
                 id: task.id,
+                version: task.version,
                 status: task.status === TaskStatus.DONE ? TaskStatus.TODO : TaskStatus.DONE
             })
         UpdateTask(request).then(onUpdate).catch((err) => {
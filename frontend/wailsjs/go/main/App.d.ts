@@ -2,14 +2,114 @@
 // This file is automatically generated. DO NOT EDIT
 import {domain} from '../models';
 
+export function ConnectCalDAV(arg1:domain.CalDAVAccount):Promise<domain.CalDAVReport>;
+
+export function CreateBackup():Promise<domain.Backup>;
+
+export function CreateProject(arg1:domain.CreateProjectRequest):Promise<domain.Project>;
+
+export function CreateSubtask(arg1:string,arg2:domain.CreateTaskRequest):Promise<domain.Task>;
+
 export function CreateTask(arg1:domain.CreateTaskRequest):Promise<domain.Task>;
 
+export function DeleteProject(arg1:string,arg2:domain.ProjectDeleteMode):Promise<void>;
+
+export function DeleteTag(arg1:string):Promise<void>;
+
 export function DeleteTask(arg1:string):Promise<void>;
 
+export function DisconnectCalDAV():Promise<void>;
+
+export function EmptyTrash():Promise<void>;
+
+export function ExportCSV(arg1:Array<domain.CSVColumn>):Promise<string>;
+
+export function ExportICal():Promise<string>;
+
+export function ExportJSON():Promise<string>;
+
+export function ExportMarkdown(arg1:domain.MarkdownGrouping):Promise<string>;
+
+export function ExportTodoTxt():Promise<string>;
+
+export function GetActiveProfile():Promise<domain.Profile>;
+
+export function GetAllProjects():Promise<Array<domain.Project>>;
+
+export function GetAllTags():Promise<Array<domain.Tag>>;
+
 export function GetAllTasks():Promise<Array<domain.Task>>;
 
+export function GetCalDAVAccount():Promise<domain.CalDAVAccount>;
+
+export function GetChildren(arg1:string):Promise<Array<domain.Task>>;
+
+export function GetFolderSyncPath():Promise<string>;
+
+export function GetProjectByID(arg1:string):Promise<domain.Project>;
+
+export function GetProjectTasks(arg1:string):Promise<Array<domain.Task>>;
+
+export function GetSettings():Promise<domain.Settings>;
+
 export function GetTaskByID(arg1:string):Promise<domain.Task>;
 
+export function GetTaskHistory(arg1:string):Promise<Array<domain.TaskEvent>>;
+
+export function GetTodoTxtSyncPath():Promise<string>;
+
+export function GetTrash():Promise<Array<domain.Task>>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportCSV(arg1:string,arg2:domain.CSVImportOptions):Promise<domain.CSVImportResult>;
+
+export function ImportICal():Promise<domain.ImportReport>;
+
+export function ImportJSON(arg1:domain.ImportMode):Promise<domain.ImportReport>;
+
+export function ImportMarkdown(arg1:string):Promise<domain.ImportReport>;
+
+export function ImportTodoTxt():Promise<domain.ImportReport>;
+
+export function ListBackups():Promise<Array<domain.Backup>>;
+
+export function ListProfiles():Promise<Array<domain.Profile>>;
+
+export function ListTasks(arg1:domain.TaskQuery):Promise<domain.TaskPage>;
+
+export function MergeTags(arg1:domain.MergeTagsRequest):Promise<domain.Tag>;
+
+export function MoveTask(arg1:domain.MoveTaskRequest):Promise<domain.Task>;
+
+export function OpenCSVFile():Promise<string>;
+
+export function Redo():Promise<domain.HistoryEntry>;
+
+export function RestoreBackup(arg1:string):Promise<void>;
+
+export function RestoreTask(arg1:string):Promise<domain.Task>;
+
+export function SearchTasks(arg1:string):Promise<Array<domain.SearchResult>>;
+
+export function StartFolderSync():Promise<string>;
+
+export function StartTodoTxtSync():Promise<string>;
+
+export function StopFolderSync():Promise<void>;
+
+export function StopTodoTxtSync():Promise<void>;
+
+export function SwitchProfile(arg1:string):Promise<domain.Profile>;
+
+export function SyncCalDAV():Promise<domain.CalDAVReport>;
+
+export function Undo():Promise<domain.HistoryEntry>;
+
+export function UpdateProject(arg1:domain.UpdateProjectRequest):Promise<domain.Project>;
+
+export function UpdateSettings(arg1:domain.UpdateSettingsRequest):Promise<domain.Settings>;
+
+export function UpdateTag(arg1:domain.UpdateTagRequest):Promise<domain.Tag>;
+
 export function UpdateTask(arg1:domain.UpdateTaskRequest):Promise<domain.Task>;
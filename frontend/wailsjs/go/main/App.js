@@ -2,26 +2,226 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ConnectCalDAV(arg1) {
+  return window['go']['main']['App']['ConnectCalDAV'](arg1);
+}
+
+export function CreateBackup() {
+  return window['go']['main']['App']['CreateBackup']();
+}
+
+export function CreateProject(arg1) {
+  return window['go']['main']['App']['CreateProject'](arg1);
+}
+
+export function CreateSubtask(arg1, arg2) {
+  return window['go']['main']['App']['CreateSubtask'](arg1, arg2);
+}
+
 export function CreateTask(arg1) {
   return window['go']['main']['App']['CreateTask'](arg1);
 }
 
+export function DeleteProject(arg1, arg2) {
+  return window['go']['main']['App']['DeleteProject'](arg1, arg2);
+}
+
+export function DeleteTag(arg1) {
+  return window['go']['main']['App']['DeleteTag'](arg1);
+}
+
 export function DeleteTask(arg1) {
   return window['go']['main']['App']['DeleteTask'](arg1);
 }
 
+export function DisconnectCalDAV() {
+  return window['go']['main']['App']['DisconnectCalDAV']();
+}
+
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function ExportCSV(arg1) {
+  return window['go']['main']['App']['ExportCSV'](arg1);
+}
+
+export function ExportICal() {
+  return window['go']['main']['App']['ExportICal']();
+}
+
+export function ExportJSON() {
+  return window['go']['main']['App']['ExportJSON']();
+}
+
+export function ExportMarkdown(arg1) {
+  return window['go']['main']['App']['ExportMarkdown'](arg1);
+}
+
+export function ExportTodoTxt() {
+  return window['go']['main']['App']['ExportTodoTxt']();
+}
+
+export function GetActiveProfile() {
+  return window['go']['main']['App']['GetActiveProfile']();
+}
+
+export function GetAllProjects() {
+  return window['go']['main']['App']['GetAllProjects']();
+}
+
+export function GetAllTags() {
+  return window['go']['main']['App']['GetAllTags']();
+}
+
 export function GetAllTasks() {
   return window['go']['main']['App']['GetAllTasks']();
 }
 
+export function GetCalDAVAccount() {
+  return window['go']['main']['App']['GetCalDAVAccount']();
+}
+
+export function GetChildren(arg1) {
+  return window['go']['main']['App']['GetChildren'](arg1);
+}
+
+export function GetFolderSyncPath() {
+  return window['go']['main']['App']['GetFolderSyncPath']();
+}
+
+export function GetProjectByID(arg1) {
+  return window['go']['main']['App']['GetProjectByID'](arg1);
+}
+
+export function GetProjectTasks(arg1) {
+  return window['go']['main']['App']['GetProjectTasks'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
 export function GetTaskByID(arg1) {
   return window['go']['main']['App']['GetTaskByID'](arg1);
 }
 
+export function GetTaskHistory(arg1) {
+  return window['go']['main']['App']['GetTaskHistory'](arg1);
+}
+
+export function GetTodoTxtSyncPath() {
+  return window['go']['main']['App']['GetTodoTxtSyncPath']();
+}
+
+export function GetTrash() {
+  return window['go']['main']['App']['GetTrash']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportCSV(arg1, arg2) {
+  return window['go']['main']['App']['ImportCSV'](arg1, arg2);
+}
+
+export function ImportICal() {
+  return window['go']['main']['App']['ImportICal']();
+}
+
+export function ImportJSON(arg1) {
+  return window['go']['main']['App']['ImportJSON'](arg1);
+}
+
+export function ImportMarkdown(arg1) {
+  return window['go']['main']['App']['ImportMarkdown'](arg1);
+}
+
+export function ImportTodoTxt() {
+  return window['go']['main']['App']['ImportTodoTxt']();
+}
+
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListProfiles() {
+  return window['go']['main']['App']['ListProfiles']();
+}
+
+export function ListTasks(arg1) {
+  return window['go']['main']['App']['ListTasks'](arg1);
+}
+
+export function MergeTags(arg1) {
+  return window['go']['main']['App']['MergeTags'](arg1);
+}
+
+export function MoveTask(arg1) {
+  return window['go']['main']['App']['MoveTask'](arg1);
+}
+
+export function OpenCSVFile() {
+  return window['go']['main']['App']['OpenCSVFile']();
+}
+
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function RestoreTask(arg1) {
+  return window['go']['main']['App']['RestoreTask'](arg1);
+}
+
+export function SearchTasks(arg1) {
+  return window['go']['main']['App']['SearchTasks'](arg1);
+}
+
+export function StartFolderSync() {
+  return window['go']['main']['App']['StartFolderSync']();
+}
+
+export function StartTodoTxtSync() {
+  return window['go']['main']['App']['StartTodoTxtSync']();
+}
+
+export function StopFolderSync() {
+  return window['go']['main']['App']['StopFolderSync']();
+}
+
+export function StopTodoTxtSync() {
+  return window['go']['main']['App']['StopTodoTxtSync']();
+}
+
+export function SwitchProfile(arg1) {
+  return window['go']['main']['App']['SwitchProfile'](arg1);
+}
+
+export function SyncCalDAV() {
+  return window['go']['main']['App']['SyncCalDAV']();
+}
+
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
+export function UpdateProject(arg1) {
+  return window['go']['main']['App']['UpdateProject'](arg1);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function UpdateTag(arg1) {
+  return window['go']['main']['App']['UpdateTag'](arg1);
+}
+
 export function UpdateTask(arg1) {
   return window['go']['main']['App']['UpdateTask'](arg1);
 }
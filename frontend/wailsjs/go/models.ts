@@ -1,30 +1,180 @@
 export namespace domain {
 	
+	export enum BackupReason {
+	    SCHEDULED = "scheduled",
+	    MANUAL = "manual",
+	    MIGRATION = "migration",
+	    RESTORE = "restore",
+	}
+	export enum CSVColumn {
+	    ID = "id",
+	    TITLE = "title",
+	    DESCRIPTION = "description",
+	    STATUS = "status",
+	    PRIORITY = "priority",
+	    DUE_DATE = "due_date",
+	    TAGS = "tags",
+	    PROJECT = "project",
+	    CREATED_AT = "created_at",
+	    MODIFIED_AT = "modified_at",
+	}
+	export enum DateFormat {
+	    ISO = "YYYY-MM-DD",
+	    EUROPEAN = "DD.MM.YYYY",
+	    BRITISH = "DD/MM/YYYY",
+	    AMERICAN = "MM/DD/YYYY",
+	}
+	export enum HistoryAction {
+	    CREATE = "create",
+	    UPDATE = "update",
+	    DELETE = "delete",
+	}
+	export enum ImportMode {
+	    MERGE = "merge",
+	    REPLACE = "replace",
+	}
+	export enum MarkdownGrouping {
+	    NONE = "none",
+	    TAG = "tag",
+	    STATUS = "status",
+	}
+	export enum ProjectDeleteMode {
+	    MOVE_TO_INBOX = "move_to_inbox",
+	    DELETE_TASKS = "delete_tasks",
+	}
+	export enum RecurrenceFrequency {
+	    DAILY = "daily",
+	    WEEKLY = "weekly",
+	    MONTHLY = "monthly",
+	    AFTER_COMPLETION = "after_completion",
+	}
+	export enum SortDirection {
+	    ASC = "asc",
+	    DESC = "desc",
+	}
+	export enum TaskEventAction {
+	    CREATED = "created",
+	    UPDATED = "updated",
+	    DELETED = "deleted",
+	    RESTORED = "restored",
+	}
 	export enum TaskPriority {
 	    NONE = "none",
 	    LOW = "low",
 	    MEDIUM = "medium",
 	    HIGH = "high",
 	}
+	export enum TaskSortKey {
+	    CREATED_AT = "created_at",
+	    MODIFIED_AT = "modified_at",
+	    DUE_DATE = "due_date",
+	    PRIORITY = "priority",
+	    TITLE = "title",
+	    POSITION = "position",
+	}
 	export enum TaskStatus {
 	    TODO = "todo",
 	    DONE = "done",
 	}
-	export class CreateTaskRequest {
-	    title: string;
-	    priority: TaskPriority;
+	export enum Theme {
+	    SYSTEM = "system",
+	    LIGHT = "light",
+	    DARK = "dark",
+	}
+	export enum ValidationCode {
+	    REQUIRED = "required",
+	    TOO_SHORT = "too_short",
+	    TOO_LONG = "too_long",
+	    TOO_SMALL = "too_small",
+	    TOO_LARGE = "too_large",
+	    NOT_ALLOWED = "not_allowed",
+	    INVALID = "invalid",
+	}
+	export class Backup {
+	    name: string;
+	    reason: BackupReason;
+	    size: number;
 	    // Go type: time
-	    due_date?: any;
+	    created_at: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreateTaskRequest(source);
+	        return new Backup(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.title = source["title"];
-	        this.priority = source["priority"];
-	        this.due_date = this.convertValues(source["due_date"], null);
+	        this.name = source["name"];
+	        this.reason = source["reason"];
+	        this.size = source["size"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CSVImportOptions {
+	    mapping: {[key: string]: string};
+	    dry_run: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mapping = source["mapping"];
+	        this.dry_run = source["dry_run"];
+	    }
+	}
+	export class CSVRowError {
+	    row: number;
+	    field: CSVColumn;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVRowError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.field = source["field"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Reminder {
+	    id: string;
+	    // Go type: time
+	    at?: any;
+	    before_minutes: number;
+	    // Go type: time
+	    fired_at?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Reminder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.at = this.convertValues(source["at"], null);
+	        this.before_minutes = source["before_minutes"];
+	        this.fired_at = this.convertValues(source["fired_at"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -45,8 +195,29 @@ export namespace domain {
 		    return a;
 		}
 	}
+	export class Recurrence {
+	    frequency: RecurrenceFrequency;
+	    interval: number;
+	    weekdays?: number[];
+	    month_day?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Recurrence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frequency = source["frequency"];
+	        this.interval = source["interval"];
+	        this.weekdays = source["weekdays"];
+	        this.month_day = source["month_day"];
+	    }
+	}
 	export class Task {
 	    id: string;
+	    project_id?: string;
+	    parent_id?: string;
+	    position: number;
 	    title: string;
 	    description: string;
 	    tags: string[];
@@ -54,10 +225,15 @@ export namespace domain {
 	    priority: TaskPriority;
 	    // Go type: time
 	    due_date?: any;
+	    recurrence?: Recurrence;
+	    reminders: Reminder[];
 	    // Go type: time
 	    created_at: any;
 	    // Go type: time
 	    modified_at: any;
+	    // Go type: time
+	    deleted_at?: any;
+	    version: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new Task(source);
@@ -66,12 +242,352 @@ export namespace domain {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.project_id = source["project_id"];
+	        this.parent_id = source["parent_id"];
+	        this.position = source["position"];
 	        this.title = source["title"];
 	        this.description = source["description"];
 	        this.tags = source["tags"];
 	        this.status = source["status"];
 	        this.priority = source["priority"];
 	        this.due_date = this.convertValues(source["due_date"], null);
+	        this.recurrence = this.convertValues(source["recurrence"], Recurrence);
+	        this.reminders = this.convertValues(source["reminders"], Reminder);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.modified_at = this.convertValues(source["modified_at"], null);
+	        this.deleted_at = this.convertValues(source["deleted_at"], null);
+	        this.version = source["version"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CSVImportResult {
+	    headers: string[];
+	    tasks: Task[];
+	    errors: CSVRowError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.headers = source["headers"];
+	        this.tasks = this.convertValues(source["tasks"], Task);
+	        this.errors = this.convertValues(source["errors"], CSVRowError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CalDAVAccount {
+	    url: string;
+	    username: string;
+	    password?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CalDAVAccount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	    }
+	}
+	export class CalDAVReport {
+	    pulled: number;
+	    pushed: number;
+	    deleted: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CalDAVReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pulled = source["pulled"];
+	        this.pushed = source["pushed"];
+	        this.deleted = source["deleted"];
+	    }
+	}
+	export class CreateProjectRequest {
+	    name: string;
+	    color: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateProjectRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.color = source["color"];
+	    }
+	}
+	export class CreateTaskRequest {
+	    title: string;
+	    description: string;
+	    tags: string[];
+	    priority: TaskPriority;
+	    // Go type: time
+	    due_date?: any;
+	    recurrence?: Recurrence;
+	    project_id?: string;
+	    reminders: Reminder[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateTaskRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.tags = source["tags"];
+	        this.priority = source["priority"];
+	        this.due_date = this.convertValues(source["due_date"], null);
+	        this.recurrence = this.convertValues(source["recurrence"], Recurrence);
+	        this.project_id = source["project_id"];
+	        this.reminders = this.convertValues(source["reminders"], Reminder);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryEntry {
+	    id: number;
+	    action: HistoryAction;
+	    before?: Task;
+	    after?: Task;
+	    undone: boolean;
+	    // Go type: time
+	    created_at: any;
+	    effects?: HistoryEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.action = source["action"];
+	        this.before = this.convertValues(source["before"], Task);
+	        this.after = this.convertValues(source["after"], Task);
+	        this.undone = source["undone"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	        this.effects = this.convertValues(source["effects"], HistoryEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportConflict {
+	    task_id: string;
+	    title: string;
+	    // Go type: time
+	    imported_modified_at: any;
+	    // Go type: time
+	    existing_modified_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_id = source["task_id"];
+	        this.title = source["title"];
+	        this.imported_modified_at = this.convertValues(source["imported_modified_at"], null);
+	        this.existing_modified_at = this.convertValues(source["existing_modified_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportReport {
+	    created: number;
+	    updated: number;
+	    unchanged: number;
+	    conflicts: ImportConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.created = source["created"];
+	        this.updated = source["updated"];
+	        this.unchanged = source["unchanged"];
+	        this.conflicts = this.convertValues(source["conflicts"], ImportConflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MergeTagsRequest {
+	    source_ids: string[];
+	    target_id: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeTagsRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source_ids = source["source_ids"];
+	        this.target_id = source["target_id"];
+	    }
+	}
+	export class MoveTaskRequest {
+	    id: string;
+	    parent_id?: string;
+	    position: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MoveTaskRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.parent_id = source["parent_id"];
+	        this.position = source["position"];
+	    }
+	}
+	export class Profile {
+	    name: string;
+	    database_path: string;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Profile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.database_path = source["database_path"];
+	        this.active = source["active"];
+	    }
+	}
+	export class Project {
+	    id: string;
+	    name: string;
+	    color: string;
+	    archived: boolean;
+	    sort_order: number;
+	    // Go type: time
+	    created_at: any;
+	    // Go type: time
+	    modified_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.color = source["color"];
+	        this.archived = source["archived"];
+	        this.sort_order = source["sort_order"];
 	        this.created_at = this.convertValues(source["created_at"], null);
 	        this.modified_at = this.convertValues(source["modified_at"], null);
 	    }
@@ -94,8 +610,329 @@ export namespace domain {
 		    return a;
 		}
 	}
+	
+	
+	export class SearchResult {
+	    task: Task;
+	    title: string;
+	    snippet: string;
+	    rank: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = this.convertValues(source["task"], Task);
+	        this.title = source["title"];
+	        this.snippet = source["snippet"];
+	        this.rank = source["rank"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Settings {
+	    default_priority: TaskPriority;
+	    confirm_deletion: boolean;
+	    week_start: number;
+	    date_format: DateFormat;
+	    theme: Theme;
+	    trash_retention_days: number;
+	    window_width: number;
+	    window_height: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.default_priority = source["default_priority"];
+	        this.confirm_deletion = source["confirm_deletion"];
+	        this.week_start = source["week_start"];
+	        this.date_format = source["date_format"];
+	        this.theme = source["theme"];
+	        this.trash_retention_days = source["trash_retention_days"];
+	        this.window_width = source["window_width"];
+	        this.window_height = source["window_height"];
+	    }
+	}
+	export class Tag {
+	    id: string;
+	    name: string;
+	    color: string;
+	    usage_count: number;
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.color = source["color"];
+	        this.usage_count = source["usage_count"];
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TaskFieldChange {
+	    field: string;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskFieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	}
+	export class TaskEvent {
+	    id: number;
+	    task_id: string;
+	    action: TaskEventAction;
+	    changes: TaskFieldChange[];
+	    // Go type: time
+	    created_at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.task_id = source["task_id"];
+	        this.action = source["action"];
+	        this.changes = this.convertValues(source["changes"], TaskFieldChange);
+	        this.created_at = this.convertValues(source["created_at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TaskPage {
+	    tasks: Task[];
+	    next_cursor: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = this.convertValues(source["tasks"], Task);
+	        this.next_cursor = source["next_cursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskQuery {
+	    project_id?: string;
+	    parent_id?: string;
+	    include_subtasks: boolean;
+	    statuses: string[];
+	    priorities: string[];
+	    tags: string[];
+	    // Go type: time
+	    due_after?: any;
+	    // Go type: time
+	    due_before?: any;
+	    // Go type: time
+	    created_after?: any;
+	    // Go type: time
+	    created_before?: any;
+	    // Go type: time
+	    modified_after?: any;
+	    // Go type: time
+	    modified_before?: any;
+	    sort_by: TaskSortKey;
+	    sort_direction: SortDirection;
+	    cursor: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project_id = source["project_id"];
+	        this.parent_id = source["parent_id"];
+	        this.include_subtasks = source["include_subtasks"];
+	        this.statuses = source["statuses"];
+	        this.priorities = source["priorities"];
+	        this.tags = source["tags"];
+	        this.due_after = this.convertValues(source["due_after"], null);
+	        this.due_before = this.convertValues(source["due_before"], null);
+	        this.created_after = this.convertValues(source["created_after"], null);
+	        this.created_before = this.convertValues(source["created_before"], null);
+	        this.modified_after = this.convertValues(source["modified_after"], null);
+	        this.modified_before = this.convertValues(source["modified_before"], null);
+	        this.sort_by = source["sort_by"];
+	        this.sort_direction = source["sort_direction"];
+	        this.cursor = source["cursor"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UpdateProjectRequest {
+	    id: string;
+	    name: string;
+	    color: string;
+	    archived?: boolean;
+	    sort_order?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateProjectRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.color = source["color"];
+	        this.archived = source["archived"];
+	        this.sort_order = source["sort_order"];
+	    }
+	}
+	export class UpdateSettingsRequest {
+	    default_priority: TaskPriority;
+	    confirm_deletion?: boolean;
+	    week_start?: number;
+	    date_format: DateFormat;
+	    theme: Theme;
+	    trash_retention_days?: number;
+	    window_width?: number;
+	    window_height?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateSettingsRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.default_priority = source["default_priority"];
+	        this.confirm_deletion = source["confirm_deletion"];
+	        this.week_start = source["week_start"];
+	        this.date_format = source["date_format"];
+	        this.theme = source["theme"];
+	        this.trash_retention_days = source["trash_retention_days"];
+	        this.window_width = source["window_width"];
+	        this.window_height = source["window_height"];
+	    }
+	}
+	export class UpdateTagRequest {
+	    id: string;
+	    name: string;
+	    color: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateTagRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.color = source["color"];
+	    }
+	}
 	export class UpdateTaskRequest {
 	    id: string;
+	    version: number;
 	    title: string;
 	    description?: string;
 	    tags: string[];
@@ -103,6 +940,10 @@ export namespace domain {
 	    priority: TaskPriority;
 	    // Go type: time
 	    due_date?: any;
+	    recurrence?: Recurrence;
+	    project_id?: string;
+	    reminders: Reminder[];
+	    complete_children: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new UpdateTaskRequest(source);
@@ -111,12 +952,17 @@ export namespace domain {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.version = source["version"];
 	        this.title = source["title"];
 	        this.description = source["description"];
 	        this.tags = source["tags"];
 	        this.status = source["status"];
 	        this.priority = source["priority"];
 	        this.due_date = this.convertValues(source["due_date"], null);
+	        this.recurrence = this.convertValues(source["recurrence"], Recurrence);
+	        this.project_id = source["project_id"];
+	        this.reminders = this.convertValues(source["reminders"], Reminder);
+	        this.complete_children = source["complete_children"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
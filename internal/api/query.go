@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// parseTaskQuery reads a domain.TaskQuery from the query parameters, which use the JSON names of its fields.
+// List parameters may be repeated or separated by commas, times are RFC 3339.
+func parseTaskQuery(values url.Values) (domain.TaskQuery, error) {
+	var query domain.TaskQuery
+
+	if values.Has("project_id") {
+		projectID := values.Get("project_id")
+		query.ProjectID = &projectID
+	}
+	if values.Has("parent_id") {
+		parentID := values.Get("parent_id")
+		query.ParentID = &parentID
+	}
+	for _, status := range list(values, "status") {
+		query.Statuses = append(query.Statuses, domain.TaskStatus(status))
+	}
+	for _, priority := range list(values, "priority") {
+		query.Priorities = append(query.Priorities, domain.TaskPriority(priority))
+	}
+	query.Tags = list(values, "tag")
+	query.SortBy = domain.TaskSortKey(values.Get("sort_by"))
+	query.SortDirection = domain.SortDirection(values.Get("sort_direction"))
+	query.Cursor = values.Get("cursor")
+
+	if value := values.Get("include_subtasks"); value != "" {
+		includeSubtasks, err := strconv.ParseBool(value)
+		if err != nil {
+			return domain.TaskQuery{}, domain.NewValidationError("include_subtasks", domain.ValidationInvalid, "must be true or false")
+		}
+		query.IncludeSubtasks = includeSubtasks
+	}
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return domain.TaskQuery{}, domain.NewValidationError("limit", domain.ValidationInvalid, "must be a number")
+		}
+		query.Limit = limit
+	}
+
+	times := []struct {
+		field string
+		value **time.Time
+	}{
+		{"due_after", &query.DueAfter},
+		{"due_before", &query.DueBefore},
+		{"created_after", &query.CreatedAfter},
+		{"created_before", &query.CreatedBefore},
+		{"modified_after", &query.ModifiedAfter},
+		{"modified_before", &query.ModifiedBefore},
+	}
+	for _, t := range times {
+		value := values.Get(t.field)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return domain.TaskQuery{}, domain.NewValidationError(t.field, domain.ValidationInvalid, "must be an RFC 3339 time")
+		}
+		*t.value = &parsed
+	}
+
+	return query, nil
+}
+
+// list returns the values of a parameter that may be repeated or separated by commas.
+func list(values url.Values, key string) []string {
+	var items []string
+	for _, value := range values[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// maxBodySize limits request bodies, a task is far smaller.
+const maxBodySize = 1 << 20
+
+type errorResponse struct {
+	Error  string              `json:"error"`
+	Fields []domain.FieldError `json:"fields,omitempty"`
+}
+
+// statusCodes maps the errors of the services to the status of the response, checked in order.
+var statusCodes = []struct {
+	err    error
+	status int
+}{
+	{domain.ErrTaskNotFound, http.StatusNotFound},
+	{domain.ErrProjectNotFound, http.StatusNotFound},
+	{domain.ErrTagNotFound, http.StatusNotFound},
+	{domain.ErrReminderNotFound, http.StatusNotFound},
+	{domain.ErrInvalidArguments, http.StatusUnprocessableEntity},
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrTagAlreadyExists, http.StatusConflict},
+	{domain.ErrIncompleteSubtasks, http.StatusConflict},
+	{domain.ErrNothingToUndo, http.StatusConflict},
+	{domain.ErrNothingToRedo, http.StatusConflict},
+}
+
+func statusCode(err error) int {
+	for _, code := range statusCodes {
+		if errors.Is(err, code.err) {
+			return code.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := statusCode(err)
+	response := errorResponse{Error: err.Error()}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		response.Fields = validationErr.Fields
+	}
+	if status == http.StatusInternalServerError {
+		// the wrapped errors of the storage are not meant for clients
+		response.Error = domain.ErrInternal.Error()
+	}
+
+	writeJSON(w, status, response)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// readJSON decodes the request body into v, on failure it writes a 400 response and returns false.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "malformed request body: " + err.Error()})
+		return false
+	}
+	return true
+}
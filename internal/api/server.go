@@ -0,0 +1,416 @@
+// Package api serves the task operations of the app as a JSON REST API on localhost,
+// so tasks can be created from scripts, editor plugins and CI jobs.
+//
+//	GET    /api/tasks                 list tasks, filtered by the query parameters of domain.TaskQuery
+//	POST   /api/tasks                 create a task from a domain.CreateTaskRequest
+//	GET    /api/tasks/{id}            get a task
+//	PATCH  /api/tasks/{id}            update a task with a domain.UpdateTaskRequest
+//	DELETE /api/tasks/{id}            move a task to the trash
+//	GET    /api/tasks/{id}/subtasks   list the subtasks of a task
+//	POST   /api/tasks/{id}/subtasks   create a subtask
+//	POST   /api/tasks/{id}/move       move a task with a domain.MoveTaskRequest
+//	POST   /api/tasks/{id}/restore    restore a task from the trash
+//	GET    /api/tasks/{id}/history    get the changes made to a task
+//	GET    /api/search?q=             search tasks
+//	GET    /api/trash                 list the tasks in the trash
+//	DELETE /api/trash                 empty the trash
+//	POST   /api/undo, /api/redo       undo or redo the most recent change
+//
+// Every request needs the header "Authorization: Bearer <token>".
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// shutdownTimeout is how long requests in flight may take to finish when the server stops.
+const shutdownTimeout = 5 * time.Second
+
+//go:generate mockery --name TaskService --output ../mocks
+type TaskService interface {
+	List(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error)
+	Search(ctx context.Context, query string) ([]domain.SearchResult, error)
+	GetByID(ctx context.Context, id string) (domain.Task, error)
+	Create(ctx context.Context, request domain.CreateTaskRequest) (domain.Task, error)
+	CreateSubtask(ctx context.Context, parentID string, request domain.CreateTaskRequest) (domain.Task, error)
+	GetChildren(ctx context.Context, parentID string) ([]domain.Task, error)
+	Update(ctx context.Context, request domain.UpdateTaskRequest) (domain.Task, error)
+	MoveTask(ctx context.Context, request domain.MoveTaskRequest) (domain.Task, error)
+	Delete(ctx context.Context, id string) error
+	GetTrash(ctx context.Context) ([]domain.Task, error)
+	Restore(ctx context.Context, id string) (domain.Task, error)
+	EmptyTrash(ctx context.Context) (int64, error)
+	GetTaskHistory(ctx context.Context, id string) ([]domain.TaskEvent, error)
+	Undo(ctx context.Context) (domain.HistoryEntry, error)
+	Redo(ctx context.Context) (domain.HistoryEntry, error)
+}
+
+// Server handles the API requests with the task service of the active profile.
+type Server struct {
+	token string
+	// tasks returns the service of the active profile, which changes when the profile is switched,
+	// and a function to call once the request is done with it
+	tasks   func() (TaskService, func())
+	changed func() // called after the tasks were changed, e.g. to refresh the window
+}
+
+func NewServer(token string, tasks func() (TaskService, func()), changed func()) *Server {
+	return &Server{
+		token:   token,
+		tasks:   tasks,
+		changed: changed,
+	}
+}
+
+// Run serves the API on the address until the context is done. Only loopback addresses are accepted,
+// the API is not meant to be reachable from other machines.
+func (s *Server) Run(ctx context.Context, address string) error {
+	if err := checkLoopback(address); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// checkLoopback accepts loopback IP literals only, a host name like "localhost" could resolve to any address.
+func checkLoopback(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("api address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("api address %q: must be a loopback IP address, e.g. 127.0.0.1 or [::1]", address)
+	}
+	return nil
+}
+
+// Handler returns the handler of every API route, with the token checked first.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="todo-app"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid token"})
+			return
+		}
+
+		s.route(w, r)
+	})
+}
+
+// route dispatches the request by its path, the segments after /api/ select the operation.
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/api/")
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+		return
+	}
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+
+	switch {
+	case len(segments) == 1 && segments[0] == "tasks":
+		s.methods(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:  s.listTasks,
+			http.MethodPost: s.createTask,
+		})
+	case len(segments) == 2 && segments[0] == "tasks":
+		id := segments[1]
+		s.methods(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:    func(w http.ResponseWriter, r *http.Request) { s.getTask(w, r, id) },
+			http.MethodPatch:  func(w http.ResponseWriter, r *http.Request) { s.updateTask(w, r, id) },
+			http.MethodDelete: func(w http.ResponseWriter, r *http.Request) { s.deleteTask(w, r, id) },
+		})
+	case len(segments) == 3 && segments[0] == "tasks":
+		id := segments[1]
+		switch segments[2] {
+		case "subtasks":
+			s.methods(w, r, map[string]http.HandlerFunc{
+				http.MethodGet:  func(w http.ResponseWriter, r *http.Request) { s.getSubtasks(w, r, id) },
+				http.MethodPost: func(w http.ResponseWriter, r *http.Request) { s.createSubtask(w, r, id) },
+			})
+		case "move":
+			s.methods(w, r, map[string]http.HandlerFunc{
+				http.MethodPost: func(w http.ResponseWriter, r *http.Request) { s.moveTask(w, r, id) },
+			})
+		case "restore":
+			s.methods(w, r, map[string]http.HandlerFunc{
+				http.MethodPost: func(w http.ResponseWriter, r *http.Request) { s.restoreTask(w, r, id) },
+			})
+		case "history":
+			s.methods(w, r, map[string]http.HandlerFunc{
+				http.MethodGet: func(w http.ResponseWriter, r *http.Request) { s.getTaskHistory(w, r, id) },
+			})
+		default:
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+		}
+	case len(segments) == 1 && segments[0] == "search":
+		s.methods(w, r, map[string]http.HandlerFunc{http.MethodGet: s.search})
+	case len(segments) == 1 && segments[0] == "trash":
+		s.methods(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:    s.getTrash,
+			http.MethodDelete: s.emptyTrash,
+		})
+	case len(segments) == 1 && segments[0] == "undo":
+		s.methods(w, r, map[string]http.HandlerFunc{http.MethodPost: s.undo})
+	case len(segments) == 1 && segments[0] == "redo":
+		s.methods(w, r, map[string]http.HandlerFunc{http.MethodPost: s.redo})
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	}
+}
+
+func (s *Server) methods(w http.ResponseWriter, r *http.Request, handlers map[string]http.HandlerFunc) {
+	if handler, ok := handlers[r.Method]; ok {
+		handler(w, r)
+		return
+	}
+
+	allowed := make([]string, 0, len(handlers))
+	for method := range handlers {
+		allowed = append(allowed, method)
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	query, err := parseTaskQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	service, done := s.tasks()
+	defer done()
+	page, err := service.List(r.Context(), query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateTaskRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	service, done := s.tasks()
+	defer done()
+	task, err := service.Create(r.Context(), request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	writeJSON(w, http.StatusCreated, task)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request, id string) {
+	service, done := s.tasks()
+	defer done()
+	task, err := service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request, id string) {
+	var request domain.UpdateTaskRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	request.ID = id
+
+	service, done := s.tasks()
+	defer done()
+	task, err := service.Update(r.Context(), request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request, id string) {
+	service, done := s.tasks()
+	defer done()
+	if err := service.Delete(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getSubtasks(w http.ResponseWriter, r *http.Request, id string) {
+	service, done := s.tasks()
+	defer done()
+	tasks, err := service.GetChildren(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(tasks))
+}
+
+func (s *Server) createSubtask(w http.ResponseWriter, r *http.Request, id string) {
+	var request domain.CreateTaskRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	service, done := s.tasks()
+	defer done()
+	task, err := service.CreateSubtask(r.Context(), id, request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	writeJSON(w, http.StatusCreated, task)
+}
+
+func (s *Server) moveTask(w http.ResponseWriter, r *http.Request, id string) {
+	var request domain.MoveTaskRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	request.ID = id
+
+	service, done := s.tasks()
+	defer done()
+	task, err := service.MoveTask(r.Context(), request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) restoreTask(w http.ResponseWriter, r *http.Request, id string) {
+	service, done := s.tasks()
+	defer done()
+	task, err := service.Restore(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) getTaskHistory(w http.ResponseWriter, r *http.Request, id string) {
+	service, done := s.tasks()
+	defer done()
+	events, err := service.GetTaskHistory(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(events))
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	service, done := s.tasks()
+	defer done()
+	results, err := service.Search(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(results))
+}
+
+func (s *Server) getTrash(w http.ResponseWriter, r *http.Request) {
+	service, done := s.tasks()
+	defer done()
+	tasks, err := service.GetTrash(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(tasks))
+}
+
+func (s *Server) emptyTrash(w http.ResponseWriter, r *http.Request) {
+	service, done := s.tasks()
+	defer done()
+	deleted, err := service.EmptyTrash(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, struct {
+		Deleted int64 `json:"deleted"`
+	}{deleted})
+}
+
+func (s *Server) undo(w http.ResponseWriter, r *http.Request) {
+	service, done := s.tasks()
+	defer done()
+	entry, err := service.Undo(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) redo(w http.ResponseWriter, r *http.Request) {
+	service, done := s.tasks()
+	defer done()
+	entry, err := service.Redo(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.changed()
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// nonNil makes an empty result encode as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testToken = "secret"
+
+type testServer struct {
+	tasks   *mocks.TaskService
+	handler http.Handler
+	changed int
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{tasks: mocks.NewTaskService(t)}
+	s.handler = NewServer(testToken, func() (TaskService, func()) { return s.tasks, func() {} }, func() { s.changed++ }).Handler()
+	return s
+}
+
+func (s *testServer) do(method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+testToken)
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		request := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		s.handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, header)
+		assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func TestServer_Tasks(t *testing.T) {
+	t.Run("list with filters", func(t *testing.T) {
+		s := newTestServer(t)
+		projectID := ""
+		s.tasks.On("List", mock.Anything, domain.TaskQuery{
+			ProjectID:  &projectID,
+			Statuses:   []domain.TaskStatus{domain.TaskStatusTodo},
+			Priorities: []domain.TaskPriority{domain.TaskPriorityHigh, domain.TaskPriorityMedium},
+			Tags:       []string{"work"},
+			SortBy:     domain.TaskSortCreatedAt,
+			Limit:      10,
+		}).Return(domain.TaskPage{Tasks: []domain.Task{{ID: "1"}}, NextCursor: "next"}, nil)
+
+		response := s.do(http.MethodGet, "/api/tasks?project_id=&status=todo&priority=high,medium&tag=work&sort_by=created_at&limit=10", "")
+
+		require.Equal(t, http.StatusOK, response.Code)
+		var page domain.TaskPage
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&page))
+		assert.Equal(t, "next", page.NextCursor)
+		assert.Len(t, page.Tasks, 1)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		s := newTestServer(t)
+		s.tasks.On("Create", mock.Anything, domain.CreateTaskRequest{Title: "Write report"}).Return(domain.Task{ID: "1", Title: "Write report"}, nil)
+
+		response := s.do(http.MethodPost, "/api/tasks", `{"title": "Write report"}`)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, "/api/tasks/1", response.Header().Get("Location"))
+		assert.Equal(t, 1, s.changed)
+	})
+
+	t.Run("update takes the id from the path", func(t *testing.T) {
+		s := newTestServer(t)
+		s.tasks.On("Update", mock.Anything, domain.UpdateTaskRequest{ID: "1", Title: "Renamed", Version: 2}).Return(domain.Task{ID: "1", Version: 3}, nil)
+
+		response := s.do(http.MethodPatch, "/api/tasks/1", `{"id": "2", "title": "Renamed", "version": 2}`)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 1, s.changed)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		s := newTestServer(t)
+		s.tasks.On("Delete", mock.Anything, "1").Return(nil)
+
+		response := s.do(http.MethodDelete, "/api/tasks/1", "")
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("empty results are arrays", func(t *testing.T) {
+		s := newTestServer(t)
+		s.tasks.On("GetChildren", mock.Anything, "1").Return(nil, nil)
+
+		response := s.do(http.MethodGet, "/api/tasks/1/subtasks", "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `[]`, response.Body.String())
+	})
+}
+
+func TestServer_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not found", fmt.Errorf("service.task.get_by_id: %w", domain.ErrTaskNotFound), http.StatusNotFound},
+		{"invalid arguments", domain.NewValidationError("title", domain.ValidationTooShort, "too short"), http.StatusUnprocessableEntity},
+		{"conflict", domain.ErrConflict, http.StatusConflict},
+		{"internal", fmt.Errorf("storage.sqlite.get_task: %w", assert.AnError), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.tasks.On("GetByID", mock.Anything, "1").Return(domain.Task{}, tt.err)
+
+			response := s.do(http.MethodGet, "/api/tasks/1", "")
+
+			assert.Equal(t, tt.status, response.Code)
+			var body errorResponse
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+			assert.NotContains(t, body.Error, "storage", "internal errors are not exposed")
+		})
+	}
+
+	t.Run("validation fields", func(t *testing.T) {
+		s := newTestServer(t)
+		s.tasks.On("Create", mock.Anything, mock.Anything).Return(domain.Task{}, domain.NewValidationError("title", domain.ValidationTooShort, "too short"))
+
+		response := s.do(http.MethodPost, "/api/tasks", `{"title": "a"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		var body errorResponse
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(t, []domain.FieldError{{Field: "title", Code: domain.ValidationTooShort, Message: "too short"}}, body.Fields)
+		assert.Zero(t, s.changed)
+	})
+
+	requests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"malformed body", http.MethodPost, "/api/tasks", `{"title":`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/tasks", `{"name": "a"}`, http.StatusBadRequest},
+		{"invalid limit", http.MethodGet, "/api/tasks?limit=ten", "", http.StatusUnprocessableEntity},
+		{"invalid time", http.MethodGet, "/api/tasks?due_after=tomorrow", "", http.StatusUnprocessableEntity},
+		{"unknown route", http.MethodGet, "/api/projects", "", http.StatusNotFound},
+		{"wrong method", http.MethodPut, "/api/tasks/1", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range requests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			response := s.do(tt.method, tt.target, tt.body)
+
+			assert.Equal(t, tt.status, response.Code)
+		})
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:8976", "127.0.0.2:8976", "[::1]:8976"} {
+		assert.NoError(t, checkLoopback(address), address)
+	}
+	for _, address := range []string{"0.0.0.0:8976", ":8976", "192.168.1.2:8976", "example.com:8976", "localhost:8976"} {
+		assert.Error(t, checkLoopback(address), address)
+	}
+}
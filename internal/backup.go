@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// backupTimeout is longer than the timeout of other mutations since the whole database is copied.
+const backupTimeout = time.Minute
+
+// Backups makes copies of the database and restores them.
+type Backups struct {
+	store BackupStore
+	keep  int // how many backups are kept
+}
+
+//go:generate mockery --name BackupStore
+type BackupStore interface {
+	CreateBackup(ctx context.Context, reason domain.BackupReason, keep int) (domain.Backup, error)
+	ListBackups(ctx context.Context) ([]domain.Backup, error)
+	RestoreBackup(ctx context.Context, name string) error
+}
+
+func NewBackups(store BackupStore, keep int) Backups {
+	return Backups{
+		store: store,
+		keep:  keep,
+	}
+}
+
+// List returns the backups, newest first.
+func (b Backups) List(ctx context.Context) ([]domain.Backup, error) {
+	const op = "service.backup.list"
+
+	backups, err := b.store.ListBackups(ctx)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return backups, nil
+}
+
+// Create backs the database up, deleting the oldest backups when there are more than the number kept.
+func (b Backups) Create(ctx context.Context, reason domain.BackupReason) (domain.Backup, error) {
+	const op = "service.backup.create"
+
+	err := validation.Validate(reason, validation.Required, validation.In(
+		domain.BackupScheduled,
+		domain.BackupManual,
+		domain.BackupMigration,
+		domain.BackupRestore,
+	))
+	if err != nil {
+		return domain.Backup{}, invalidField("reason", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backupTimeout)
+	defer cancel()
+
+	backup, err := b.store.CreateBackup(ctx, reason, b.keep)
+	if err != nil {
+		return domain.Backup{}, handleError(op, err)
+	}
+
+	return backup, nil
+}
+
+// CreateIfDue makes a scheduled backup unless one was made within the interval,
+// so restarting the app does not push the older backups out.
+func (b Backups) CreateIfDue(ctx context.Context, interval time.Duration, now time.Time) (bool, error) {
+	const op = "service.backup.create_if_due"
+
+	backups, err := b.store.ListBackups(ctx)
+	if err != nil {
+		return false, handleError(op, err)
+	}
+	for _, backup := range backups {
+		if backup.Reason == domain.BackupScheduled && now.Sub(backup.CreatedAt) < interval {
+			return false, nil
+		}
+	}
+
+	if _, err := b.Create(ctx, domain.BackupScheduled); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Restore replaces the database with the backup. The current database is backed up first,
+// so a restore can itself be undone.
+func (b Backups) Restore(ctx context.Context, name string) error {
+	const op = "service.backup.restore"
+
+	if err := validation.Validate(name, validation.Required); err != nil {
+		return invalidField("name", err)
+	}
+
+	backups, err := b.store.ListBackups(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+	found := false
+	for _, backup := range backups {
+		found = found || backup.Name == name
+	}
+	if !found {
+		return domain.ErrBackupNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backupTimeout)
+	defer cancel()
+
+	// one more backup is kept this time, so the rotation cannot delete the backup being restored
+	if _, err := b.store.CreateBackup(ctx, domain.BackupRestore, b.keep+1); err != nil {
+		return handleError(op, err)
+	}
+
+	if err := b.store.RestoreBackup(ctx, name); err != nil {
+		return handleError(op, err)
+	}
+
+	return nil
+}
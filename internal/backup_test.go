@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestBackups_Create(t *testing.T) {
+	t.Run("valid reason", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		store.On("CreateBackup", mock.Anything, domain.BackupManual, 5).Return(domain.Backup{Name: "tasks-1-manual.db"}, nil)
+
+		backup, err := backups.Create(context.Background(), domain.BackupManual)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "tasks-1-manual.db", backup.Name)
+	})
+
+	t.Run("unknown reason", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		_, err := backups.Create(context.Background(), "weekly")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
+
+func TestBackups_CreateIfDue(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("recent scheduled backup", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		store.On("ListBackups", mock.Anything).Return([]domain.Backup{
+			{Name: "b", Reason: domain.BackupScheduled, CreatedAt: now.Add(-time.Hour)},
+		}, nil)
+
+		created, err := backups.CreateIfDue(context.Background(), 24*time.Hour, now)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	t.Run("only other backups are recent", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		store.On("ListBackups", mock.Anything).Return([]domain.Backup{
+			{Name: "a", Reason: domain.BackupManual, CreatedAt: now.Add(-time.Hour)},
+			{Name: "b", Reason: domain.BackupScheduled, CreatedAt: now.Add(-25 * time.Hour)},
+		}, nil)
+		store.On("CreateBackup", mock.Anything, domain.BackupScheduled, 5).Return(domain.Backup{Name: "c"}, nil)
+
+		created, err := backups.CreateIfDue(context.Background(), 24*time.Hour, now)
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+}
+
+func TestBackups_Restore(t *testing.T) {
+	t.Run("existing backup", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		store.On("ListBackups", mock.Anything).Return([]domain.Backup{{Name: "old.db"}}, nil)
+		createCall := store.On("CreateBackup", mock.Anything, domain.BackupRestore, 6).Return(domain.Backup{Name: "new.db"}, nil)
+		store.On("RestoreBackup", mock.Anything, "old.db").Return(nil).NotBefore(createCall)
+
+		err := backups.Restore(context.Background(), "old.db")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown backup", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		store.On("ListBackups", mock.Anything).Return([]domain.Backup{{Name: "old.db"}}, nil)
+
+		err := backups.Restore(context.Background(), "../tasks.db")
+
+		assert.ErrorIs(t, err, domain.ErrBackupNotFound)
+		store.AssertNotCalled(t, "CreateBackup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		store := mocks.NewBackupStore(t)
+		backups := NewBackups(store, 5)
+
+		err := backups.Restore(context.Background(), "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
@@ -0,0 +1,472 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/labstack/gommon/log"
+)
+
+// caldavSyncInterval is how often the CalDAV server is asked for changes, unlike the shared folder it is polled
+// over the network.
+const caldavSyncInterval = time.Minute
+
+// CalDAVSync keeps the tasks in step with a task list on a CalDAV server, which phones and other CalDAV clients
+// sync with too. Every task is stored as a calendar object resource holding one VTODO.
+//
+// The server is asked for the resources changed since the previous sync with the sync token it handed out then,
+// and the changed ones are fetched. Tasks changed locally are found by their version and pushed with the ETag
+// last seen, so a change made on the server in the meantime is never overwritten, it is pulled by the next sync
+// instead. When a task changed on both sides the later change wins. The fields a VTODO has no place for, such as
+// the project, position, recurrence and reminders, are kept locally.
+//
+// The password of the account is kept in a PasswordStore instead of the database, whose backups would carry it along.
+type CalDAVSync struct {
+	store     CalDAVStore
+	passwords PasswordStore
+	client    *http.Client
+	clock     Clock
+
+	mu sync.Mutex // a sync at a time
+}
+
+//go:generate mockery --name CalDAVStore
+type CalDAVStore interface {
+	ExportTasks(ctx context.Context) ([]domain.Task, error)
+	ApplySync(ctx context.Context, projects []domain.Project, tasks []domain.Task) ([]domain.Task, error)
+	DeleteTask(ctx context.Context, id string) error
+	GetCalDAVState(ctx context.Context) (domain.CalDAVState, error)
+	SaveCalDAVState(ctx context.Context, state domain.CalDAVState) error
+}
+
+//go:generate mockery --name PasswordStore
+type PasswordStore interface {
+	GetPassword() (string, error)
+	SetPassword(password string) error
+}
+
+func NewCalDAVSync(store CalDAVStore, passwords PasswordStore, client *http.Client, clock Clock) *CalDAVSync {
+	return &CalDAVSync{
+		store:     store,
+		passwords: passwords,
+		client:    client,
+		clock:     clock,
+	}
+}
+
+// Account returns the account tasks are synced with, without its password. Its URL is empty while the sync is off.
+func (s *CalDAVSync) Account(ctx context.Context) (domain.CalDAVAccount, error) {
+	const op = "service.caldav_sync.account"
+
+	state, err := s.store.GetCalDAVState(ctx)
+	if err != nil {
+		return domain.CalDAVAccount{}, handleError(op, err)
+	}
+
+	account := state.Account
+	account.Password = ""
+	return account, nil
+}
+
+// SetAccount starts syncing with the task list at the URL of the account, or stops syncing when the URL is empty.
+// An empty password keeps the one saved for the same URL and username. Syncing with another task list starts over:
+// the local tasks are pushed to it and its tasks pulled as on a first sync.
+func (s *CalDAVSync) SetAccount(ctx context.Context, account domain.CalDAVAccount) error {
+	const op = "service.caldav_sync.set_account"
+
+	account.URL = strings.TrimSpace(account.URL)
+	if account.URL == "" {
+		account = domain.CalDAVAccount{}
+	} else {
+		collection, err := url.Parse(account.URL)
+		if err != nil || (collection.Scheme != "http" && collection.Scheme != "https") || collection.Host == "" {
+			return domain.NewValidationError("url", domain.ValidationInvalid, "must be the http or https URL of a task list")
+		}
+		if !strings.HasSuffix(collection.Path, "/") {
+			collection.Path += "/"
+			collection.RawPath = ""
+		}
+		account.URL = collection.String()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	if account.URL != state.Account.URL || account.Username != state.Account.Username {
+		state.SyncToken = ""
+		state.Resources = make(map[string]domain.CalDAVResource)
+	} else if account.Password == "" {
+		account.Password = state.Account.Password
+	}
+	state.Account = account
+
+	if err := s.saveState(ctx, state); err != nil {
+		return handleError(op, err)
+	}
+
+	return nil
+}
+
+// loadState returns the state of the sync with the password of the account. A password still saved in the
+// database by an older version is moved to the password store.
+func (s *CalDAVSync) loadState(ctx context.Context) (domain.CalDAVState, error) {
+	state, err := s.store.GetCalDAVState(ctx)
+	if err != nil {
+		return domain.CalDAVState{}, err
+	}
+	if state.Account.Password != "" {
+		return state, s.saveState(ctx, state)
+	}
+
+	state.Account.Password, err = s.passwords.GetPassword()
+	if err != nil {
+		return domain.CalDAVState{}, err
+	}
+	return state, nil
+}
+
+// saveState saves the state of the sync, and the password of the account in the password store.
+func (s *CalDAVSync) saveState(ctx context.Context, state domain.CalDAVState) error {
+	if err := s.passwords.SetPassword(state.Account.Password); err != nil {
+		return err
+	}
+	state.Account.Password = ""
+	return s.store.SaveCalDAVState(ctx, state)
+}
+
+// Run syncs until the context is cancelled, calling changed whenever tasks were changed by the server.
+func (s *CalDAVSync) Run(ctx context.Context, changed func()) {
+	const op = "service.caldav_sync.run"
+
+	for {
+		report, err := s.Sync(ctx)
+		if err != nil {
+			log.Error(op, err)
+		}
+		if report.Pulled > 0 || report.Deleted > 0 {
+			changed()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(caldavSyncInterval):
+		}
+	}
+}
+
+// Sync pulls the changes made on the server since the previous sync and pushes the local ones,
+// it does nothing while syncing is off.
+func (s *CalDAVSync) Sync(ctx context.Context) (domain.CalDAVReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		report, err := s.sync(ctx)
+		// a task edited while the pulled tasks were merged is merged again when the sync starts over
+		if errors.Is(err, domain.ErrConflict) && attempt < syncConflictRetries {
+			continue
+		}
+		return report, err
+	}
+}
+
+func (s *CalDAVSync) sync(ctx context.Context) (domain.CalDAVReport, error) {
+	const op = "service.caldav_sync.sync"
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return domain.CalDAVReport{}, handleError(op, err)
+	}
+	if state.Account.URL == "" {
+		return domain.CalDAVReport{}, nil
+	}
+	if state.Resources == nil {
+		state.Resources = make(map[string]domain.CalDAVResource)
+	}
+
+	client, err := newCalDAVClient(s.client, state.Account)
+	if err != nil {
+		return domain.CalDAVReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var report domain.CalDAVReport
+	if err := s.pull(ctx, client, &state, &report); err != nil {
+		return domain.CalDAVReport{}, err
+	}
+	// the resources pushed before a failure are saved too, so they are not created again
+	pushErr := s.push(ctx, client, &state, &report)
+	if err := s.store.SaveCalDAVState(ctx, state); err != nil {
+		return domain.CalDAVReport{}, handleError(op, err)
+	}
+	if pushErr != nil {
+		return domain.CalDAVReport{}, pushErr
+	}
+
+	return report, nil
+}
+
+// pull applies the changes made on the server since the sync token of the state and moves the state to the new token.
+func (s *CalDAVSync) pull(ctx context.Context, client *caldavClient, state *domain.CalDAVState, report *domain.CalDAVReport) error {
+	const op = "service.caldav_sync.pull"
+
+	changes, token, err := client.changes(ctx, state.SyncToken)
+	if errors.Is(err, errSyncTokenInvalid) {
+		log.Warn(op, ": ", err, ", listing the whole task list")
+		state.SyncToken = ""
+		changes, token, err = client.changes(ctx, "")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tasks, err := s.store.ExportTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+	local := make(map[string]domain.Task, len(tasks))
+	for _, task := range tasks {
+		local[task.ID] = task
+	}
+	byHref := make(map[string]string, len(state.Resources))
+	for id, resource := range state.Resources {
+		byHref[resource.Href] = id
+	}
+
+	var fetch, gone []string
+	listed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		listed[change.href] = true
+		id, known := byHref[change.href]
+		switch {
+		case change.deleted:
+			if known {
+				gone = append(gone, id)
+			}
+		case !known || change.etag == "" || change.etag != state.Resources[id].ETag:
+			fetch = append(fetch, change.href)
+		}
+	}
+	if state.SyncToken == "" {
+		// the whole task list was listed, the resources left out of it were deleted
+		for href, id := range byHref {
+			if !listed[href] {
+				gone = append(gone, id)
+			}
+		}
+	}
+
+	objects, err := client.multiget(ctx, fetch)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := s.clock.Now()
+	var pulled []domain.Task
+	ids := make(map[string]bool)
+	for _, object := range objects {
+		remote, err := readVTODO(object.data)
+		if err != nil {
+			log.Warnf("%s: skipping %s: %v", op, object.href, err)
+			continue
+		}
+		if remote == nil {
+			continue // e.g. an event in a calendar that holds tasks too
+		}
+
+		id, known := byHref[object.href]
+		if !known {
+			id = remote.ID // a new resource, or one whose resource was forgotten, is matched by its UID
+		}
+		task, exists := local[id]
+		resource, synced := state.Resources[id]
+		if exists && (!synced || task.Version != resource.Version) && localModifiedAt(task).Truncate(time.Second).After(remote.ModifiedAt) {
+			// changed on both sides and the local change is later, it is pushed over the remote one
+			state.Resources[id] = domain.CalDAVResource{TaskID: id, Href: object.href, ETag: object.etag, Version: resource.Version}
+			continue
+		}
+
+		merged := mergeVTODO(task, exists, *remote, now)
+		merged.ID = id
+		if err := validateImportedTasks([]domain.Task{merged}); err != nil {
+			log.Warnf("%s: skipping %s: %v", op, object.href, err)
+			continue
+		}
+		pulled = append(pulled, merged)
+		ids[id] = true
+		state.Resources[id] = domain.CalDAVResource{TaskID: id, Href: object.href, ETag: object.etag}
+	}
+
+	// a subtask of a task the app does not have is kept at the top level
+	for i, task := range pulled {
+		if task.ParentID == nil {
+			continue
+		}
+		if _, ok := local[*task.ParentID]; !ok && !ids[*task.ParentID] {
+			pulled[i].ParentID = nil
+		}
+	}
+
+	if len(pulled) > 0 {
+		importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+		defer cancel()
+
+		// the pulled tasks must still have the versions they had when they were merged, a task changed locally
+		// in the meantime fails the write with ErrConflict and the sync starts over
+		saved, err := s.store.ApplySync(importCtx, nil, pulled)
+		if err != nil {
+			return handleError(op, err)
+		}
+		// the versions the pulled tasks were saved with are not local changes
+		for _, task := range saved {
+			resource := state.Resources[task.ID]
+			resource.Version = task.Version
+			state.Resources[task.ID] = resource
+		}
+		report.Pulled += len(pulled)
+	}
+
+	for _, id := range gone {
+		if ids[id] {
+			continue // moved to another href on the server
+		}
+		resource := state.Resources[id]
+		delete(state.Resources, id)
+
+		task, ok := local[id]
+		if !ok || task.DeletedAt != nil {
+			continue
+		}
+		if task.Version != resource.Version {
+			continue // changed locally since the previous sync, it is pushed to the server again
+		}
+		// the subtasks go to the trash too, and are deleted from the server by the push
+		if err := s.store.DeleteTask(ctx, id); err != nil && !errors.Is(err, domain.ErrTaskNotFound) {
+			return handleError(op, err)
+		}
+		report.Deleted++
+	}
+
+	state.SyncToken = token
+	return nil
+}
+
+// push creates, updates and deletes the resources of the tasks changed locally since they were last synced.
+func (s *CalDAVSync) push(ctx context.Context, client *caldavClient, state *domain.CalDAVState, report *domain.CalDAVReport) error {
+	const op = "service.caldav_sync.push"
+
+	tasks, err := s.store.ExportTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	exists := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		exists[task.ID] = true
+		resource, synced := state.Resources[task.ID]
+
+		if task.DeletedAt != nil {
+			if !synced {
+				continue
+			}
+			err := client.delete(ctx, resource.Href, resource.ETag)
+			if errors.Is(err, errPreconditionFailed) {
+				continue // changed on the server in the meantime, the next sync settles which change wins
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			delete(state.Resources, task.ID)
+			report.Deleted++
+			continue
+		}
+		if synced && task.Version == resource.Version {
+			continue
+		}
+
+		var data bytes.Buffer
+		if err := writeICalendar(&data, []domain.Task{task}); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		href := resource.Href
+		if !synced {
+			href = client.href(task.ID)
+		}
+		etag, err := client.put(ctx, href, data.Bytes(), resource.ETag, !synced)
+		if errors.Is(err, errPreconditionFailed) {
+			if !synced {
+				// a resource the state lost track of is in the way, the next sync lists the whole task list to find it
+				state.SyncToken = ""
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		state.Resources[task.ID] = domain.CalDAVResource{TaskID: task.ID, Href: href, ETag: etag, Version: task.Version}
+		report.Pushed++
+	}
+
+	// tasks purged from the trash
+	for id, resource := range state.Resources {
+		if exists[id] {
+			continue
+		}
+		err := client.delete(ctx, resource.Href, resource.ETag)
+		if errors.Is(err, errPreconditionFailed) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		delete(state.Resources, id)
+		report.Deleted++
+	}
+
+	return nil
+}
+
+// readVTODO returns the task of the first VTODO of a calendar object, or nil when it holds none. The other
+// VTODOs of an object are the overridden occurrences of a recurring task, which the app does not keep.
+func readVTODO(data string) (*domain.Task, error) {
+	tasks, err := readICalendar(strings.NewReader(data))
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// mergeVTODO returns the local task with the fields a VTODO holds taken from the remote task.
+func mergeVTODO(task domain.Task, exists bool, remote domain.Task, now time.Time) domain.Task {
+	if !exists {
+		task = domain.Task{CreatedAt: remote.CreatedAt}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+	}
+	task = withVTODOFields(task, remote)
+	task.DeletedAt = nil // a change on the server later than moving the task to the trash brings it back
+	task.ModifiedAt = now
+	return task
+}
+
+// localModifiedAt returns when the task was last changed locally, moving it to the trash does not touch its modification time.
+func localModifiedAt(task domain.Task) time.Time {
+	if task.DeletedAt != nil && task.DeletedAt.After(task.ModifiedAt) {
+		return *task.DeletedAt
+	}
+	return task.ModifiedAt
+}
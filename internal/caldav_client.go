@@ -0,0 +1,322 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// caldavMaxPages bounds the sync-collection requests of a sync to a server that keeps truncating its answers.
+const caldavMaxPages = 100
+
+// caldavMultigetSize is how many resources are fetched by a calendar-multiget request.
+const caldavMultigetSize = 100
+
+var (
+	// errSyncTokenInvalid is returned when the server no longer knows the sync token, e.g. after it was restored.
+	errSyncTokenInvalid = errors.New("the server does not accept the sync token")
+	// errPreconditionFailed is returned when a resource changed on the server since its ETag was seen.
+	errPreconditionFailed = errors.New("the resource changed on the server")
+)
+
+// caldavResourceName matches task IDs that can name their resource as they are, other IDs are hashed.
+var caldavResourceName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// caldavClient makes the WebDAV (RFC 4918), CalDAV (RFC 4791) and collection sync (RFC 6578) requests
+// to a calendar collection. Hrefs are paths on the server, kept unescaped so the same resource has the
+// same href however the server escapes it.
+type caldavClient struct {
+	http       *http.Client
+	collection *url.URL // its path ends with a slash
+	username   string
+	password   string
+}
+
+func newCalDAVClient(client *http.Client, account domain.CalDAVAccount) (*caldavClient, error) {
+	collection, err := url.Parse(account.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(collection.Path, "/") {
+		collection.Path += "/"
+		collection.RawPath = ""
+	}
+
+	return &caldavClient{
+		http:       client,
+		collection: collection,
+		username:   account.Username,
+		password:   account.Password,
+	}, nil
+}
+
+// caldavChange is a resource reported by a sync-collection request, changed or deleted since the sync token.
+type caldavChange struct {
+	href    string
+	etag    string
+	deleted bool
+}
+
+// caldavObject is a calendar object resource fetched from the server.
+type caldavObject struct {
+	href string
+	etag string
+	data string
+}
+
+// href returns the href a task is stored at when it is created on the server.
+func (c *caldavClient) href(taskID string) string {
+	name := taskID
+	if !caldavResourceName.MatchString(name) {
+		name = uuid.NewSHA1(uuid.NameSpaceURL, []byte(taskID)).String()
+	}
+	return c.collection.Path + name + ".ics"
+}
+
+// changes returns the resources changed or deleted since the sync token and the token of the collection
+// as it is now. The empty token lists every resource, deleted ones are then left out.
+func (c *caldavClient) changes(ctx context.Context, token string) ([]caldavChange, string, error) {
+	var changes []caldavChange
+	for page := 0; page < caldavMaxPages; page++ {
+		multistatus, err := c.report(ctx, "0", syncCollectionRequest(token))
+		if err != nil {
+			return nil, "", err
+		}
+
+		truncated := false
+		for _, response := range multistatus.Responses {
+			href, err := c.normalize(response.Href)
+			if err != nil {
+				return nil, "", err
+			}
+			switch {
+			case href == c.collection.Path:
+				// the collection itself reports 507 when the server sends the rest of the changes in another answer
+				truncated = truncated || davStatusCode(response.Status) == http.StatusInsufficientStorage
+			case strings.HasSuffix(href, "/"):
+				// a nested collection, tasks are not kept there
+			case davStatusCode(response.Status) == http.StatusNotFound:
+				changes = append(changes, caldavChange{href: href, deleted: true})
+			default:
+				changes = append(changes, caldavChange{href: href, etag: response.prop().ETag})
+			}
+		}
+
+		token = multistatus.SyncToken
+		if !truncated {
+			return changes, token, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("the server sent the changes in more than %d parts", caldavMaxPages)
+}
+
+// multiget fetches the calendar objects at the hrefs, the ones deleted in the meantime are left out.
+func (c *caldavClient) multiget(ctx context.Context, hrefs []string) ([]caldavObject, error) {
+	var objects []caldavObject
+	for start := 0; start < len(hrefs); start += caldavMultigetSize {
+		multistatus, err := c.report(ctx, "1", calendarMultigetRequest(hrefs[start:min(start+caldavMultigetSize, len(hrefs))]))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, response := range multistatus.Responses {
+			href, err := c.normalize(response.Href)
+			if err != nil {
+				return nil, err
+			}
+			prop := response.prop()
+			if prop.CalendarData == "" {
+				continue
+			}
+			objects = append(objects, caldavObject{href: href, etag: prop.ETag, data: prop.CalendarData})
+		}
+	}
+
+	return objects, nil
+}
+
+// put stores the calendar object at href if its ETag is still etag, or, with create set, if there is none
+// at href yet. It returns the new ETag, or an empty string when the server did not tell it.
+func (c *caldavClient) put(ctx context.Context, href string, data []byte, etag string, create bool) (string, error) {
+	header := http.Header{"Content-Type": {"text/calendar; charset=utf-8"}}
+	switch {
+	case create:
+		header.Set("If-None-Match", "*")
+	case etag != "":
+		header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, href, header, data)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", errPreconditionFailed
+	case resp.StatusCode/100 != 2:
+		return "", statusError(resp)
+	}
+
+	etag = resp.Header.Get("ETag")
+	if strings.HasPrefix(etag, "W/") {
+		return "", nil // a weak ETag means the server changed the object, it cannot be used for If-Match
+	}
+	return etag, nil
+}
+
+// delete deletes the resource at href if its ETag is still etag, a resource already gone is not an error.
+func (c *caldavClient) delete(ctx context.Context, href, etag string) error {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, href, header, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return errPreconditionFailed
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode/100 == 2:
+		return nil
+	default:
+		return statusError(resp)
+	}
+}
+
+// report makes a REPORT request to the collection and reads its multi-status answer.
+func (c *caldavClient) report(ctx context.Context, depth string, body []byte) (davMultistatus, error) {
+	header := http.Header{
+		"Content-Type": {"application/xml; charset=utf-8"},
+		"Depth":        {depth},
+	}
+	resp, err := c.do(ctx, "REPORT", c.collection.Path, header, body)
+	if err != nil {
+		return davMultistatus{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		// the DAV:valid-sync-token precondition is reported with 403 Forbidden, some servers use 409 Conflict
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusConflict) && bytes.Contains(data, []byte("valid-sync-token")) {
+			return davMultistatus{}, errSyncTokenInvalid
+		}
+		return davMultistatus{}, statusError(resp)
+	}
+
+	var multistatus davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&multistatus); err != nil {
+		return davMultistatus{}, fmt.Errorf("REPORT %s: %w", c.collection.Path, err)
+	}
+	return multistatus, nil
+}
+
+func (c *caldavClient) do(ctx context.Context, method, href string, header http.Header, body []byte) (*http.Response, error) {
+	target := c.collection.ResolveReference(&url.URL{Path: href})
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return c.http.Do(req)
+}
+
+// normalize returns the unescaped path of an href sent by the server, which may be a full URL.
+func (c *caldavClient) normalize(href string) (string, error) {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", fmt.Errorf("invalid href %q: %w", href, err)
+	}
+	return c.collection.ResolveReference(ref).Path, nil
+}
+
+// statusError describes a failed request, e.g. PUT /alice/tasks/1.ics: 401 Unauthorized.
+func statusError(resp *http.Response) error {
+	return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status)
+}
+
+type davMultistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+	SyncToken string        `xml:"DAV: sync-token"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"DAV: href"`
+	Status    string        `xml:"DAV: status"` // set instead of the propstats when the resource is gone
+	Propstats []davPropstat `xml:"DAV: propstat"`
+}
+
+type davPropstat struct {
+	Status string  `xml:"DAV: status"`
+	Prop   davProp `xml:"DAV: prop"`
+}
+
+type davProp struct {
+	ETag         string `xml:"DAV: getetag"`
+	CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+}
+
+// prop returns the properties the server found, the ones it did not are reported in another propstat.
+func (r davResponse) prop() davProp {
+	for _, propstat := range r.Propstats {
+		if davStatusCode(propstat.Status) == http.StatusOK {
+			return propstat.Prop
+		}
+	}
+	return davProp{}
+}
+
+// davStatusCode reads the code of a status line such as HTTP/1.1 404 Not Found.
+func davStatusCode(status string) int {
+	fields := strings.Fields(status)
+	if len(fields) < 2 {
+		return 0
+	}
+	code, _ := strconv.Atoi(fields[1])
+	return code
+}
+
+func syncCollectionRequest(token string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<d:sync-collection xmlns:d="DAV:"><d:sync-token>`)
+	xml.EscapeText(&buf, []byte(token))
+	buf.WriteString(`</d:sync-token><d:sync-level>1</d:sync-level><d:prop><d:getetag/></d:prop></d:sync-collection>`)
+	return buf.Bytes()
+}
+
+func calendarMultigetRequest(hrefs []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+	buf.WriteString(`<d:prop><d:getetag/><c:calendar-data/></d:prop>`)
+	for _, href := range hrefs {
+		buf.WriteString(`<d:href>`)
+		xml.EscapeText(&buf, []byte((&url.URL{Path: href}).EscapedPath()))
+		buf.WriteString(`</d:href>`)
+	}
+	buf.WriteString(`</c:calendar-multiget>`)
+	return buf.Bytes()
+}
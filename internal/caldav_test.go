@@ -0,0 +1,548 @@
+package internal
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/config"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	caldavTestUser     = "alice"
+	caldavTestPassword = "secret"
+	caldavTestList     = "/dav/alice/tasks/"
+)
+
+// caldavServer is an in-process stand-in for a CalDAV server with one task list, which a phone syncs with
+// through the put and remove methods. Every change gets the next sequence number, the sync token and the
+// ETag of a resource are made from it.
+type caldavServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	seq       int
+	minToken  int // tokens handed out before are no longer accepted
+	resources map[string]*caldavTestResource
+	requests  []string // method, href and conditions of every request, e.g. PUT /dav/alice/tasks/1.ics If-Match:"3"
+}
+
+type caldavTestResource struct {
+	data     string
+	etag     string
+	modified int // sequence number of the last change
+	deleted  bool
+}
+
+func newCalDAVServer(t *testing.T) *caldavServer {
+	s := &caldavServer{t: t, resources: make(map[string]*caldavTestResource)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *caldavServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, password, _ := r.BasicAuth()
+	if user != caldavTestUser || password != caldavTestPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	request := r.Method + " " + r.URL.Path
+	for _, header := range []string{"If-Match", "If-None-Match"} {
+		if value := r.Header.Get(header); value != "" {
+			request += " " + header + ":" + value
+		}
+	}
+	s.requests = append(s.requests, request)
+
+	switch r.Method {
+	case "REPORT":
+		s.report(w, r)
+	case http.MethodPut:
+		resource := s.resources[r.URL.Path]
+		if !s.preconditions(r, resource) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		w.Header().Set("ETag", s.store(r.URL.Path, string(data)))
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		resource := s.resources[r.URL.Path]
+		if resource == nil || resource.deleted {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !s.preconditions(r, resource) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.seq++
+		resource.deleted, resource.modified = true, s.seq
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *caldavServer) preconditions(r *http.Request, resource *caldavTestResource) bool {
+	exists := resource != nil && !resource.deleted
+	if match := r.Header.Get("If-Match"); match != "" && (!exists || resource.etag != match) {
+		return false
+	}
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		return false
+	}
+	return true
+}
+
+func (s *caldavServer) store(href, data string) string {
+	s.seq++
+	resource := &caldavTestResource{data: data, etag: `"` + strconv.Itoa(s.seq) + `"`, modified: s.seq}
+	s.resources[href] = resource
+	return resource.etag
+}
+
+func (s *caldavServer) report(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		XMLName xml.Name
+		Token   string   `xml:"DAV: sync-token"`
+		Hrefs   []string `xml:"DAV: href"`
+	}
+	require.NoError(s.t, xml.NewDecoder(r.Body).Decode(&request))
+
+	var body strings.Builder
+	switch request.XMLName.Local {
+	case "sync-collection":
+		since := 0
+		if request.Token != "" {
+			seq, err := strconv.Atoi(strings.TrimPrefix(request.Token, "http://example.com/sync/"))
+			if err != nil || seq < s.minToken || seq > s.seq {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `<?xml version="1.0"?><d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`)
+				return
+			}
+			since = seq
+		}
+		for _, href := range s.hrefs() {
+			resource := s.resources[href]
+			switch {
+			case resource.modified <= since, resource.deleted && since == 0:
+			case resource.deleted:
+				fmt.Fprintf(&body, `<d:response><d:href>%s</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`, href)
+			default:
+				fmt.Fprintf(&body, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag></d:prop>`+
+					`<d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, href, resource.etag)
+			}
+		}
+		fmt.Fprintf(&body, `<d:sync-token>http://example.com/sync/%d</d:sync-token>`, s.seq)
+	case "calendar-multiget":
+		for _, href := range request.Hrefs {
+			resource := s.resources[href]
+			if resource == nil || resource.deleted {
+				fmt.Fprintf(&body, `<d:response><d:href>%s</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`, href)
+				continue
+			}
+			var data strings.Builder
+			xml.EscapeText(&data, []byte(resource.data))
+			fmt.Fprintf(&body, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>`+
+				`<c:calendar-data>%s</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				href, resource.etag, data.String())
+		}
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	fmt.Fprintf(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">%s</d:multistatus>`, body.String())
+}
+
+func (s *caldavServer) hrefs() []string {
+	hrefs := make([]string, 0, len(s.resources))
+	for href := range s.resources {
+		hrefs = append(hrefs, href)
+	}
+	sort.Strings(hrefs)
+	return hrefs
+}
+
+// put stores a VTODO as a phone would.
+func (s *caldavServer) put(name string, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(caldavTestList+name, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//phone//EN\r\nBEGIN:VTODO\r\n"+
+		strings.Join(lines, "\r\n")+"\r\nEND:VTODO\r\nEND:VCALENDAR\r\n")
+}
+
+// remove deletes a resource as a phone would.
+func (s *caldavServer) remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	resource := s.resources[caldavTestList+name]
+	resource.deleted, resource.modified = true, s.seq
+}
+
+// tasks returns the tasks stored on the server by their titles.
+func (s *caldavServer) tasks() map[string]domain.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make(map[string]domain.Task)
+	for _, resource := range s.resources {
+		if resource.deleted {
+			continue
+		}
+		task, err := readVTODO(resource.data)
+		require.NoError(s.t, err)
+		tasks[task.Title] = *task
+	}
+	return tasks
+}
+
+// takeRequests returns the requests made since the previous call.
+func (s *caldavServer) takeRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+// invalidateTokens makes the server forget the sync tokens it handed out, as after restoring it from a backup.
+func (s *caldavServer) invalidateTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	s.minToken = s.seq
+}
+
+type caldavDevice struct {
+	t        *testing.T
+	tasks    Task
+	projects Project
+	store    *editingStore
+	sync     *CalDAVSync
+}
+
+func newCalDAVDevice(t *testing.T, server *caldavServer) *caldavDevice {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	storage, err := sqlite.NewStorage(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	d := &caldavDevice{
+		t:        t,
+		tasks:    NewTask(storage, storage, storage),
+		projects: NewProject(storage, storage),
+		store:    &editingStore{Storage: storage},
+	}
+	d.sync = NewCalDAVSync(d.store, config.CalDAVPassword(path), server.Client(), SystemClock{})
+	err = d.sync.SetAccount(context.Background(), domain.CalDAVAccount{
+		URL:      server.URL + strings.TrimSuffix(caldavTestList, "/"),
+		Username: caldavTestUser,
+		Password: caldavTestPassword,
+	})
+	require.NoError(t, err)
+	return d
+}
+
+func (d *caldavDevice) syncNow() domain.CalDAVReport {
+	report, err := d.sync.Sync(context.Background())
+	require.NoError(d.t, err)
+	return report
+}
+
+func (d *caldavDevice) create(title string) domain.Task {
+	task, err := d.tasks.Create(context.Background(), domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone})
+	require.NoError(d.t, err)
+	return task
+}
+
+func (d *caldavDevice) get(id string) domain.Task {
+	task, err := d.tasks.GetByID(context.Background(), id)
+	require.NoError(d.t, err)
+	return task
+}
+
+func (d *caldavDevice) update(request domain.UpdateTaskRequest) {
+	request.Version = d.get(request.ID).Version
+	_, err := d.tasks.Update(context.Background(), request)
+	require.NoError(d.t, err)
+}
+
+func (d *caldavDevice) trash() []string {
+	trash, err := d.tasks.GetTrash(context.Background())
+	require.NoError(d.t, err)
+	ids := make([]string, 0, len(trash))
+	for _, task := range trash {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func icalStamp(t time.Time) string {
+	return t.UTC().Format(icalUTCLayout)
+}
+
+func TestCalDAVSync_PushesTasks(t *testing.T) {
+	ctx := context.Background()
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+
+	parent := device.create("Write report")
+	child, err := device.tasks.CreateSubtask(ctx, parent.ID, domain.CreateTaskRequest{Title: "Collect numbers", Priority: domain.TaskPriorityHigh})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.CalDAVReport{Pushed: 2}, device.syncNow())
+
+	tasks := server.tasks()
+	require.Len(t, tasks, 2)
+	assert.Equal(t, parent.ID, tasks["Write report"].ID)
+	assert.Equal(t, domain.TaskPriorityHigh, tasks["Collect numbers"].Priority)
+	require.NotNil(t, tasks["Collect numbers"].ParentID)
+	assert.Equal(t, parent.ID, *tasks["Collect numbers"].ParentID)
+	assert.Contains(t, server.takeRequests(), "PUT "+caldavTestList+child.ID+".ics If-None-Match:*")
+
+	t.Run("nothing more to sync", func(t *testing.T) {
+		assert.Zero(t, device.syncNow())
+		for _, request := range server.takeRequests() {
+			assert.True(t, strings.HasPrefix(request, "REPORT"), request)
+		}
+	})
+}
+
+func TestCalDAVSync_PullsPhoneChanges(t *testing.T) {
+	ctx := context.Background()
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+
+	server.put("call-mom.ics", "UID:call-mom@phone", "SUMMARY:Call mom", "PRIORITY:1", "CATEGORIES:family",
+		"DUE:20240610T090000Z", "LAST-MODIFIED:"+icalStamp(time.Now()))
+	assert.Equal(t, domain.CalDAVReport{Pulled: 1}, device.syncNow())
+
+	task := device.get("call-mom@phone")
+	assert.Equal(t, "Call mom", task.Title)
+	assert.Equal(t, domain.TaskPriorityHigh, task.Priority)
+	assert.Equal(t, []string{"family"}, []string(task.Tags))
+	require.NotNil(t, task.DueDate)
+	assert.True(t, time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC).Equal(*task.DueDate))
+
+	t.Run("edits keep the fields a VTODO does not hold", func(t *testing.T) {
+		project, err := device.projects.Create(ctx, domain.CreateProjectRequest{Name: "Home", Color: "#3b82f6"})
+		require.NoError(t, err)
+		device.update(domain.UpdateTaskRequest{ID: task.ID, ProjectID: &project.ID})
+		device.syncNow()
+		server.takeRequests()
+
+		server.put("call-mom.ics", "UID:call-mom@phone", "SUMMARY:Call mom", "STATUS:COMPLETED",
+			"LAST-MODIFIED:"+icalStamp(time.Now().Add(time.Minute)))
+		assert.Equal(t, domain.CalDAVReport{Pulled: 1}, device.syncNow())
+
+		task := device.get(task.ID)
+		assert.Equal(t, domain.TaskStatusDone, task.Status)
+		require.NotNil(t, task.ProjectID)
+		assert.Equal(t, project.ID, *task.ProjectID)
+		for _, request := range server.takeRequests() {
+			assert.False(t, strings.HasPrefix(request, "PUT"), "a pulled change is not pushed back: %s", request)
+		}
+	})
+
+	t.Run("deletions move the task to the trash", func(t *testing.T) {
+		server.remove("call-mom.ics")
+		assert.Equal(t, domain.CalDAVReport{Deleted: 1}, device.syncNow())
+		assert.Equal(t, []string{task.ID}, device.trash())
+	})
+}
+
+func TestCalDAVSync_PushesLocalChanges(t *testing.T) {
+	ctx := context.Background()
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+	task := device.create("Write report")
+	device.syncNow()
+	etag := server.resources[caldavTestList+task.ID+".ics"].etag
+	server.takeRequests()
+
+	device.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+	assert.Equal(t, domain.CalDAVReport{Pushed: 1}, device.syncNow())
+	assert.Contains(t, server.takeRequests(), "PUT "+caldavTestList+task.ID+".ics If-Match:"+etag)
+	assert.Contains(t, server.tasks(), "Write the report")
+
+	require.NoError(t, device.tasks.Delete(ctx, task.ID))
+	assert.Equal(t, domain.CalDAVReport{Deleted: 1}, device.syncNow())
+	assert.Empty(t, server.tasks())
+}
+
+func TestCalDAVSync_Conflicts(t *testing.T) {
+	t.Run("the later change on the server wins", func(t *testing.T) {
+		server := newCalDAVServer(t)
+		device := newCalDAVDevice(t, server)
+		task := device.create("Write report")
+		device.syncNow()
+
+		device.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		server.put(task.ID+".ics", "UID:"+task.ID, "SUMMARY:Write the final report", "LAST-MODIFIED:"+icalStamp(time.Now().Add(time.Hour)))
+		device.syncNow()
+
+		assert.Equal(t, "Write the final report", device.get(task.ID).Title)
+		assert.Contains(t, server.tasks(), "Write the final report")
+		assert.Len(t, server.tasks(), 1)
+	})
+
+	t.Run("the later local change wins", func(t *testing.T) {
+		server := newCalDAVServer(t)
+		device := newCalDAVDevice(t, server)
+		task := device.create("Write report")
+		device.syncNow()
+
+		server.put(task.ID+".ics", "UID:"+task.ID, "SUMMARY:Write the final report", "LAST-MODIFIED:"+icalStamp(time.Now().Add(-time.Hour)))
+		device.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		device.syncNow()
+
+		assert.Equal(t, "Write the report", device.get(task.ID).Title)
+		assert.Contains(t, server.tasks(), "Write the report")
+		assert.Len(t, server.tasks(), 1)
+	})
+
+	t.Run("a change on the server after a push is not overwritten", func(t *testing.T) {
+		server := newCalDAVServer(t)
+		device := newCalDAVDevice(t, server)
+		task := device.create("Write report")
+		device.syncNow()
+
+		// the phone changes the task between the pull and the push of the next sync
+		device.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		state, err := device.sync.loadState(context.Background())
+		require.NoError(t, err)
+		server.put(task.ID+".ics", "UID:"+task.ID, "SUMMARY:Write the final report", "LAST-MODIFIED:"+icalStamp(time.Now().Add(time.Hour)))
+		client, err := newCalDAVClient(server.Client(), state.Account)
+		require.NoError(t, err)
+		var report domain.CalDAVReport
+		require.NoError(t, device.sync.push(context.Background(), client, &state, &report))
+
+		assert.Zero(t, report.Pushed)
+		assert.Contains(t, server.tasks(), "Write the final report")
+	})
+}
+
+func TestCalDAVSync_EditDuringPull(t *testing.T) {
+	ctx := context.Background()
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+	task := device.create("Write report")
+	device.syncNow()
+	project, err := device.projects.Create(ctx, domain.CreateProjectRequest{Name: "Work", Color: "#3b82f6"})
+	require.NoError(t, err)
+
+	server.put(task.ID+".ics", "UID:"+task.ID, "SUMMARY:Write the final report", "LAST-MODIFIED:"+icalStamp(time.Now().Add(time.Hour)))
+	device.store.edit = func() {
+		device.update(domain.UpdateTaskRequest{ID: task.ID, ProjectID: &project.ID})
+	}
+	device.syncNow()
+
+	got := device.get(task.ID)
+	assert.Equal(t, "Write the final report", got.Title)
+	require.NotNil(t, got.ProjectID, "the edit made during the pull is kept")
+	assert.Equal(t, project.ID, *got.ProjectID)
+	state, err := device.sync.loadState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, got.Version, state.Resources[task.ID].Version)
+}
+
+func TestCalDAVSync_PasswordInDatabase(t *testing.T) {
+	ctx := context.Background()
+	store, passwords := mocks.NewCalDAVStore(t), mocks.NewPasswordStore(t)
+	account := domain.CalDAVAccount{URL: "https://dav.example.com/alice/tasks/", Username: caldavTestUser}
+	saved := account
+	saved.Password = caldavTestPassword // by an older version
+	store.On("GetCalDAVState", ctx).Return(domain.CalDAVState{Account: saved}, nil)
+	passwords.On("SetPassword", caldavTestPassword).Return(nil)
+	store.On("SaveCalDAVState", ctx, domain.CalDAVState{Account: account}).Return(nil)
+
+	state, err := NewCalDAVSync(store, passwords, http.DefaultClient, SystemClock{}).loadState(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, caldavTestPassword, state.Account.Password)
+}
+
+func TestCalDAVSync_InvalidSyncToken(t *testing.T) {
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+	kept := device.create("Write report")
+	removed := device.create("Buy milk")
+	device.syncNow()
+
+	server.invalidateTokens()
+	server.remove(removed.ID + ".ics")
+	server.put("call-mom.ics", "UID:call-mom@phone", "SUMMARY:Call mom")
+
+	assert.Equal(t, domain.CalDAVReport{Pulled: 1, Deleted: 1}, device.syncNow())
+	assert.Equal(t, "Write report", device.get(kept.ID).Title)
+	assert.Equal(t, "Call mom", device.get("call-mom@phone").Title)
+	assert.Equal(t, []string{removed.ID}, device.trash())
+	assert.Zero(t, device.syncNow())
+}
+
+func TestCalDAVSync_Account(t *testing.T) {
+	ctx := context.Background()
+	server := newCalDAVServer(t)
+	device := newCalDAVDevice(t, server)
+
+	account, err := device.sync.Account(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CalDAVAccount{URL: server.URL + caldavTestList, Username: caldavTestUser}, account)
+
+	t.Run("the password is kept out of the database", func(t *testing.T) {
+		state, err := device.store.GetCalDAVState(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, state.Account.Password)
+		password, err := device.sync.passwords.GetPassword()
+		require.NoError(t, err)
+		assert.Equal(t, caldavTestPassword, password)
+	})
+
+	t.Run("an empty password keeps the saved one", func(t *testing.T) {
+		require.NoError(t, device.sync.SetAccount(ctx, account))
+		device.create("Write report")
+		assert.Equal(t, domain.CalDAVReport{Pushed: 1}, device.syncNow())
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		account := account
+		account.Password = "wrong"
+		require.NoError(t, device.sync.SetAccount(ctx, account))
+		_, err := device.sync.Sync(ctx)
+		assert.ErrorContains(t, err, "401 Unauthorized")
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		err := device.sync.SetAccount(ctx, domain.CalDAVAccount{URL: "ftp://example.com/tasks/"})
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		require.NoError(t, device.sync.SetAccount(ctx, domain.CalDAVAccount{}))
+		device.create("Buy milk")
+		assert.Zero(t, device.syncNow())
+	})
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultAPIAddress is used when the API is enabled without an address.
+	DefaultAPIAddress = "127.0.0.1:8976"
+
+	apiTokenFile = "api-token"
+)
+
+// API is the config of the REST API, see package api.
+type API struct {
+	// Address is the host and port to listen on, the host must be a loopback IP address like 127.0.0.1.
+	Address string `json:"address,omitempty"`
+}
+
+// APIAddress returns the address of the API, and false when the API is not enabled.
+func (c Config) APIAddress() (string, bool) {
+	if c.API == nil {
+		return "", false
+	}
+	return firstNonEmpty(c.API.Address, DefaultAPIAddress), true
+}
+
+// APIToken returns the token the API clients authenticate with. It is kept in the api-token file
+// of the data directory, which is created with a random token on first use and readable only by the user.
+func (c Config) APIToken() (string, error) {
+	path := filepath.Join(c.DataDir, apiTokenFile)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read api token: %w", err)
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+	token := hex.EncodeToString(random)
+
+	if err := os.MkdirAll(c.DataDir, os.FileMode(0755)); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), os.FileMode(0600)); err != nil {
+		return "", fmt.Errorf("failed to save api token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,198 @@
+// Package config finds the database of each profile from the config file, the environment and the command line.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+const (
+	// EnvConfig is the environment variable with the path of the config file.
+	EnvConfig = "TODO_APP_CONFIG"
+	// EnvDatabase is the environment variable with the path of the database of the active profile.
+	EnvDatabase = "TODO_APP_DB"
+	// EnvProfile is the environment variable with the name of the active profile.
+	EnvProfile = "TODO_APP_PROFILE"
+
+	// DefaultProfile is used when no profile is chosen, its database is the one used before profiles existed.
+	DefaultProfile = "default"
+
+	appDir       = "todo-app"
+	configFile   = "config.json"
+	databaseFile = "tasks.db"
+	profilesDir  = "profiles"
+)
+
+var profileName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Flags are the command-line flags that override the config file and the environment.
+type Flags struct {
+	Config   string // path of the config file
+	Database string // path of the database of the active profile
+	Profile  string // name of the active profile
+}
+
+// Config is read from a JSON file in the user config directory, e.g.
+//
+//	{"data_dir": "/home/me/Sync/todo-app", "profile": "work", "profiles": {"personal": "/home/me/personal.db"}}
+type Config struct {
+	// DataDir holds the databases of the profiles, the default profile in tasks.db and the others
+	// in profiles/<name>/tasks.db. It defaults to the todo-app directory in the user config directory.
+	DataDir string `json:"data_dir,omitempty"`
+	// Profile is the active profile, it is saved when the profile is switched.
+	Profile string `json:"profile,omitempty"`
+	// Profiles sets the database paths of profiles kept outside of the data directory.
+	Profiles map[string]string `json:"profiles,omitempty"`
+	// API enables the REST API when set, e.g. {"api": {"address": "127.0.0.1:8976"}}.
+	API *API `json:"api,omitempty"`
+
+	path            string // of the config file
+	database        string // overrides the database of the profile active at startup, it is never saved
+	databaseProfile string
+}
+
+// DefaultDataDir returns the todo-app directory in the user config directory,
+// which unlike the cache directory is not cleaned up by the system.
+func DefaultDataDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, appDir), nil
+}
+
+// Load reads the config file and applies the environment and the flags on top of it, a missing file is not an error.
+func Load(flags Flags) (Config, error) {
+	dataDir, err := DefaultDataDir()
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+
+	path := firstNonEmpty(flags.Config, os.Getenv(EnvConfig), filepath.Join(dataDir, configFile))
+
+	var config Config
+	file, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return Config{}, fmt.Errorf("failed to open config file: %w", err)
+	default:
+		err = json.NewDecoder(file).Decode(&config)
+		file.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	config.path = path
+	if config.DataDir == "" {
+		config.DataDir = dataDir
+	}
+	config.Profile = firstNonEmpty(flags.Profile, os.Getenv(EnvProfile), config.Profile, DefaultProfile)
+	config.database = firstNonEmpty(flags.Database, os.Getenv(EnvDatabase))
+	config.databaseProfile = config.Profile
+
+	if err := ValidateProfile(config.Profile); err != nil {
+		return Config{}, err
+	}
+	for name := range config.Profiles {
+		if err := ValidateProfile(name); err != nil {
+			return Config{}, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	return config, nil
+}
+
+// Save writes the config file, the database given by the environment or a flag is not saved.
+func (c Config) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), os.FileMode(0755)); err != nil {
+		return err
+	}
+
+	if dataDir, err := DefaultDataDir(); err == nil && dataDir == c.DataDir {
+		c.DataDir = "" // keep following the default
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), os.FileMode(0644))
+}
+
+// ValidateProfile checks that the name can be used as a directory name on every system.
+func ValidateProfile(name string) error {
+	if !profileName.MatchString(name) {
+		return domain.NewValidationError("profile", domain.ValidationInvalid, fmt.Sprintf("%q must be 1 to 32 lowercase letters, digits, - or _", name))
+	}
+	return nil
+}
+
+// DatabasePath returns the path of the database of the profile.
+func (c Config) DatabasePath(profile string) string {
+	if c.database != "" && profile == c.databaseProfile {
+		return c.database
+	}
+	if path, ok := c.Profiles[profile]; ok {
+		return path
+	}
+	if profile == DefaultProfile {
+		return filepath.Join(c.DataDir, databaseFile)
+	}
+	return filepath.Join(c.DataDir, profilesDir, profile, databaseFile)
+}
+
+// ListProfiles returns the default profile, the profiles set in the config file and the ones
+// with a directory in the data directory, sorted by name.
+func (c Config) ListProfiles() ([]domain.Profile, error) {
+	names := map[string]bool{DefaultProfile: true, c.Profile: true}
+	for name := range c.Profiles {
+		names[name] = true
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.DataDir, profilesDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfile(entry.Name()) == nil {
+			names[entry.Name()] = true
+		}
+	}
+
+	profiles := make([]domain.Profile, 0, len(names))
+	for name := range names {
+		profiles = append(profiles, c.ProfileInfo(name))
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+// ProfileInfo describes the profile with the name.
+func (c Config) ProfileInfo(name string) domain.Profile {
+	return domain.Profile{
+		Name:         name,
+		DatabasePath: c.DatabasePath(name),
+		Active:       name == c.Profile,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
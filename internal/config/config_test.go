@@ -0,0 +1,241 @@
+package config
+
+import (
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// setupDirs points the user config and cache directories into a temporary directory.
+func setupDirs(t *testing.T) (configDir, cacheDir string) {
+	dir := t.TempDir()
+	configDir, cacheDir = filepath.Join(dir, "config"), filepath.Join(dir, "cache")
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv(EnvConfig, "")
+	t.Setenv(EnvDatabase, "")
+	t.Setenv(EnvProfile, "")
+	return configDir, cacheDir
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	configDir, _ := setupDirs(t)
+	dataDir := filepath.Join(configDir, "todo-app")
+
+	config, err := Load(Flags{})
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfile, config.Profile)
+	assert.Equal(t, filepath.Join(dataDir, "tasks.db"), config.DatabasePath(DefaultProfile))
+	assert.Equal(t, filepath.Join(dataDir, "profiles", "work", "tasks.db"), config.DatabasePath("work"))
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	configDir, _ := setupDirs(t)
+	path := filepath.Join(configDir, "todo-app", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"data_dir": "/data",
+		"profile": "work",
+		"profiles": {"personal": "/elsewhere/personal.db"}
+	}`), 0o644))
+
+	t.Run("config file", func(t *testing.T) {
+		config, err := Load(Flags{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "work", config.Profile)
+		assert.Equal(t, filepath.Join("/data", "profiles", "work", "tasks.db"), config.DatabasePath("work"))
+		assert.Equal(t, "/elsewhere/personal.db", config.DatabasePath("personal"))
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(EnvProfile, "personal")
+		t.Setenv(EnvDatabase, "/env.db")
+
+		config, err := Load(Flags{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "personal", config.Profile)
+		assert.Equal(t, "/env.db", config.DatabasePath("personal"))
+		assert.Equal(t, filepath.Join("/data", "profiles", "work", "tasks.db"), config.DatabasePath("work"))
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		t.Setenv(EnvProfile, "personal")
+		t.Setenv(EnvDatabase, "/env.db")
+
+		config, err := Load(Flags{Profile: "side", Database: "/flag.db"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "side", config.Profile)
+		assert.Equal(t, "/flag.db", config.DatabasePath("side"))
+		assert.Equal(t, "/elsewhere/personal.db", config.DatabasePath("personal"))
+	})
+
+	t.Run("config flag", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other.json")
+		require.NoError(t, os.WriteFile(other, []byte(`{"profile": "other"}`), 0o644))
+
+		config, err := Load(Flags{Config: other})
+
+		require.NoError(t, err)
+		assert.Equal(t, "other", config.Profile)
+	})
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	setupDirs(t)
+
+	t.Run("profile name", func(t *testing.T) {
+		_, err := Load(Flags{Profile: "../work"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "broken.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"profile": `), 0o644))
+
+		_, err := Load(Flags{Config: path})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestConfig_SaveAndListProfiles(t *testing.T) {
+	configDir, _ := setupDirs(t)
+
+	config, err := Load(Flags{Database: "/flag.db"})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "todo-app", "profiles", "work"), 0o755))
+
+	config.Profile = "personal"
+	require.NoError(t, config.Save())
+
+	data, err := os.ReadFile(filepath.Join(configDir, "todo-app", "config.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"profile": "personal"}`, string(data), "overrides and the default data dir are not saved")
+
+	profiles, err := config.ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Profile{
+		{Name: "default", DatabasePath: "/flag.db"},
+		{Name: "personal", DatabasePath: filepath.Join(configDir, "todo-app", "profiles", "personal", "tasks.db"), Active: true},
+		{Name: "work", DatabasePath: filepath.Join(configDir, "todo-app", "profiles", "work", "tasks.db")},
+	}, profiles)
+}
+
+func TestConfig_MigrateLegacyData(t *testing.T) {
+	configDir, cacheDir := setupDirs(t)
+	legacyDir := filepath.Join(cacheDir, "todo-app")
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyDir, "backups"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "tasks.db"), []byte("db"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "tasks.db-wal"), []byte("wal"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "backups", "tasks-1-manual.db"), []byte("backup"), 0o644))
+
+	config, err := Load(Flags{})
+	require.NoError(t, err)
+
+	moved, err := config.MigrateLegacyData()
+
+	require.NoError(t, err)
+	assert.True(t, moved)
+	dataDir := filepath.Join(configDir, "todo-app")
+	for file, content := range map[string]string{
+		"tasks.db":                  "db",
+		"tasks.db-wal":              "wal",
+		"backups/tasks-1-manual.db": "backup",
+	} {
+		data, err := os.ReadFile(filepath.Join(dataDir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	assert.NoFileExists(t, filepath.Join(legacyDir, "tasks.db"))
+
+	t.Run("new database exists", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "tasks.db"), []byte("stale"), 0o644))
+
+		moved, err := config.MigrateLegacyData()
+
+		assert.NoError(t, err)
+		assert.False(t, moved)
+		data, _ := os.ReadFile(filepath.Join(dataDir, "tasks.db"))
+		assert.Equal(t, "db", string(data))
+	})
+}
+
+func TestConfig_MigrateLegacyData_Failure(t *testing.T) {
+	configDir, cacheDir := setupDirs(t)
+	legacyDir := filepath.Join(cacheDir, "todo-app")
+	// a database that cannot be read fails the copy after its write-ahead log was copied
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyDir, "tasks.db"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "tasks.db-wal"), []byte("wal"), 0o644))
+
+	config, err := Load(Flags{})
+	require.NoError(t, err)
+
+	moved, err := config.MigrateLegacyData()
+
+	assert.Error(t, err)
+	assert.False(t, moved)
+	assert.FileExists(t, filepath.Join(legacyDir, "tasks.db-wal"), "the database is left where it was found")
+	assert.NoFileExists(t, filepath.Join(configDir, "todo-app", "tasks.db-wal"))
+	assert.NoFileExists(t, filepath.Join(configDir, "todo-app", "tasks.db"))
+}
+
+func TestConfig_API(t *testing.T) {
+	setupDirs(t)
+
+	config, err := Load(Flags{})
+	require.NoError(t, err)
+
+	_, enabled := config.APIAddress()
+	assert.False(t, enabled)
+
+	config.API = &API{}
+	address, enabled := config.APIAddress()
+	assert.True(t, enabled)
+	assert.Equal(t, DefaultAPIAddress, address)
+
+	token, err := config.APIToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	info, err := os.Stat(filepath.Join(config.DataDir, "api-token"))
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	again, err := config.APIToken()
+	require.NoError(t, err)
+	assert.Equal(t, token, again, "the token is kept between runs")
+}
+
+func TestCalDAVPassword(t *testing.T) {
+	file := CalDAVPassword(filepath.Join(t.TempDir(), "tasks.db"))
+
+	password, err := file.GetPassword()
+	require.NoError(t, err)
+	assert.Empty(t, password)
+
+	require.NoError(t, file.SetPassword("secret"))
+	password, err = file.GetPassword()
+	require.NoError(t, err)
+	assert.Equal(t, "secret", password)
+
+	info, err := os.Stat(string(file))
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	require.NoError(t, file.SetPassword(""))
+	_, err = os.Stat(string(file))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// legacyDataDir is where the database was kept before it became configurable. The system may clean up
+// the cache directory at any time, so the database is moved out of it.
+func legacyDataDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, appDir), nil
+}
+
+// MigrateLegacyData moves the database and its backups from the cache directory to the location of the
+// default profile. It returns whether the database was moved, it is not when the new database already exists.
+// An error after the database was moved means that files were left behind in the cache directory.
+func (c Config) MigrateLegacyData() (bool, error) {
+	legacyDir, err := legacyDataDir()
+	if err != nil {
+		return false, nil // without a cache directory there is nothing to migrate
+	}
+	legacyDatabase := filepath.Join(legacyDir, databaseFile)
+	database := c.DatabasePath(DefaultProfile)
+	if legacyDatabase == database {
+		return false, nil
+	}
+
+	if _, err := os.Stat(legacyDatabase); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if _, err := os.Stat(database); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	dir := filepath.Dir(database)
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return false, err
+	}
+
+	// the database is copied together with its write-ahead log before anything is removed, so a failure
+	// leaves it where it was found, the database itself goes last as its presence marks the migration done
+	var copied []string
+	for _, suffix := range []string{"-wal", "-shm", ""} {
+		err := copyFile(legacyDatabase+suffix, database+suffix)
+		if errors.Is(err, os.ErrNotExist) && suffix != "" {
+			continue
+		}
+		if err != nil {
+			for _, path := range copied {
+				os.Remove(path)
+			}
+			return false, err
+		}
+		copied = append(copied, database+suffix)
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(legacyDatabase + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return true, err
+		}
+	}
+
+	if err := moveBackups(filepath.Join(legacyDir, "backups"), filepath.Join(dir, "backups")); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func moveBackups(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(to, os.FileMode(0755)); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := moveFile(filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(from)
+}
+
+// moveFile renames the file, copying it when the directories are on different file systems.
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	} else if _, statErr := os.Stat(from); statErr != nil {
+		return statErr
+	}
+
+	if err := copyFile(from, to); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+// copyFile copies the file to a new one, nothing is left at the destination when it fails.
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.FileMode(0644))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(to)
+		return err
+	}
+
+	return nil
+}
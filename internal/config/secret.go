@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const caldavPasswordSuffix = "-caldav-password"
+
+// PasswordFile keeps a password outside of the database, in a file readable only by the user,
+// so it is not copied into the backups of the database and not brought back by restoring one.
+type PasswordFile string
+
+// CalDAVPassword returns the file holding the password of the CalDAV account of the database at the path.
+func CalDAVPassword(databasePath string) PasswordFile {
+	return PasswordFile(databasePath + caldavPasswordSuffix)
+}
+
+// GetPassword returns the saved password, it is empty when none was saved.
+func (f PasswordFile) GetPassword() (string, error) {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// SetPassword saves the password, an empty password removes the file.
+func (f PasswordFile) SetPassword(password string) error {
+	if password == "" {
+		if err := os.Remove(string(f)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove password: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(f)), os.FileMode(0755)); err != nil {
+		return err
+	}
+	// written to a new file first, so an existing file readable by others does not keep its permissions
+	tmp := string(f) + ".tmp"
+	os.Remove(tmp)
+	if err := os.WriteFile(tmp, []byte(password+"\n"), os.FileMode(0600)); err != nil {
+		return fmt.Errorf("failed to save password: %w", err)
+	}
+	if err := os.Rename(tmp, string(f)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save password: %w", err)
+	}
+	return nil
+}
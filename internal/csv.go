@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// csvTimeLayouts are the date formats accepted in date columns, exports use the first one.
+var csvTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+// ExportCSV writes the tasks outside the trash as RFC 4180 CSV with the given columns,
+// DefaultCSVColumns are used when none are given.
+func (t Transfer) ExportCSV(ctx context.Context, w io.Writer, columns []domain.CSVColumn) error {
+	const op = "service.transfer.export_csv"
+
+	if len(columns) == 0 {
+		columns = domain.DefaultCSVColumns
+	}
+	if err := validation.Validate(columns, validation.Each(validation.By(validateCSVColumn))); err != nil {
+		return invalidField("columns", err)
+	}
+
+	projects, err := t.provider.GetAllProjects(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	tasks, err := t.provider.ExportTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = string(column)
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, task := range tasks {
+		if task.DeletedAt != nil {
+			continue
+		}
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = csvValue(task, column, projectNames)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func csvValue(task domain.Task, column domain.CSVColumn, projectNames map[string]string) string {
+	switch column {
+	case domain.CSVColumnID:
+		return task.ID
+	case domain.CSVColumnTitle:
+		return task.Title
+	case domain.CSVColumnDescription:
+		return task.Description
+	case domain.CSVColumnStatus:
+		return string(task.Status)
+	case domain.CSVColumnPriority:
+		return string(task.Priority)
+	case domain.CSVColumnDueDate:
+		if task.DueDate == nil {
+			return ""
+		}
+		return task.DueDate.Format(csvTimeLayouts[0])
+	case domain.CSVColumnTags:
+		return strings.Join(task.Tags, ", ")
+	case domain.CSVColumnProject:
+		if task.ProjectID == nil {
+			return ""
+		}
+		return projectNames[*task.ProjectID]
+	case domain.CSVColumnCreatedAt:
+		return task.CreatedAt.Format(csvTimeLayouts[0])
+	case domain.CSVColumnModifiedAt:
+		return task.ModifiedAt.Format(csvTimeLayouts[0])
+	default:
+		return ""
+	}
+}
+
+// ImportCSV creates a task for every valid row. A row whose ID column names an existing task updates the mapped
+// fields of that task, the fields without a column are kept. Invalid rows are skipped and reported, with DryRun
+// nothing is written.
+func (t Transfer) ImportCSV(ctx context.Context, r io.Reader, options domain.CSVImportOptions) (domain.CSVImportResult, error) {
+	const op = "service.transfer.import_csv"
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // short rows leave the remaining fields empty
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return domain.CSVImportResult{}, domain.NewValidationError("file", domain.ValidationRequired, "is empty")
+		}
+		return domain.CSVImportResult{}, domain.NewValidationError("file", domain.ValidationInvalid, err.Error())
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff") // byte order mark written by spreadsheet apps
+
+	columns, err := mapCSVHeader(header, options.Mapping)
+	if err != nil {
+		return domain.CSVImportResult{}, domain.NewValidationError("mapping", domain.ValidationInvalid, err.Error())
+	}
+
+	projects, err := t.provider.GetAllProjects(ctx)
+	if err != nil {
+		return domain.CSVImportResult{}, handleError(op, err)
+	}
+	projectIDs := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectIDs[strings.ToLower(project.Name)] = project.ID
+	}
+
+	existing := make(map[string]domain.Task)
+	if slices.Contains(columns, domain.CSVColumnID) {
+		tasks, err := t.provider.ExportTasks(ctx)
+		if err != nil {
+			return domain.CSVImportResult{}, handleError(op, err)
+		}
+		for _, task := range tasks {
+			existing[task.ID] = task
+		}
+	}
+
+	result := domain.CSVImportResult{Headers: header}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return domain.CSVImportResult{}, domain.NewValidationError("file", domain.ValidationInvalid, err.Error())
+		}
+		row, _ := reader.FieldPos(0)
+
+		task, rowErrors := parseCSVRow(record, columns, projectIDs, existing)
+		if len(rowErrors) > 0 {
+			for _, rowError := range rowErrors {
+				rowError.Row = row
+				result.Errors = append(result.Errors, rowError)
+			}
+			continue
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	if options.DryRun || len(result.Tasks) == 0 {
+		return result, nil
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	if err := t.modifier.ImportData(importCtx, nil, result.Tasks, false); err != nil {
+		return domain.CSVImportResult{}, handleError(op, err)
+	}
+
+	return result, nil
+}
+
+// mapCSVHeader returns the column each field of a row maps to, fields of ignored headers map to "".
+func mapCSVHeader(header []string, mapping map[string]domain.CSVColumn) ([]domain.CSVColumn, error) {
+	columns := make([]domain.CSVColumn, len(header))
+	mapped := make(map[domain.CSVColumn]bool)
+	for i, name := range header {
+		column := domain.CSVColumn(strings.ToLower(strings.TrimSpace(name)))
+		if len(mapping) > 0 {
+			column = mapping[name]
+		}
+		if column == "" || validateCSVColumn(column) != nil {
+			if len(mapping) > 0 && column != "" {
+				return nil, fmt.Errorf("header %q: unknown field %q", name, column)
+			}
+			continue
+		}
+		if mapped[column] {
+			return nil, fmt.Errorf("header %q: field %q is mapped more than once", name, column)
+		}
+		mapped[column] = true
+		columns[i] = column
+	}
+
+	if !mapped[domain.CSVColumnTitle] {
+		return nil, fmt.Errorf("no header is mapped to the title")
+	}
+
+	return columns, nil
+}
+
+// parseCSVRow builds a task from a row, reporting every field that fails validation. A row with the ID
+// of an existing task starts from that task, so the fields the row has no column for keep their values.
+func parseCSVRow(record []string, columns []domain.CSVColumn, projectIDs map[string]string, existing map[string]domain.Task) (domain.Task, []domain.CSVRowError) {
+	now := time.Now()
+	task := domain.Task{
+		ID:         uuid.NewString(),
+		Status:     domain.TaskStatusTodo,
+		Priority:   domain.TaskPriorityNone,
+		CreatedAt:  now,
+		ModifiedAt: now,
+	}
+	value := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	for i, column := range columns {
+		if stored, ok := existing[value(i)]; ok && column == domain.CSVColumnID {
+			task = stored
+			task.ModifiedAt = now
+		}
+	}
+
+	var rowErrors []domain.CSVRowError
+	for i, column := range columns {
+		if column == "" {
+			continue
+		}
+		value := value(i)
+
+		if err := setCSVValue(&task, column, value, projectIDs); err != nil {
+			rowErrors = append(rowErrors, domain.CSVRowError{Field: column, Message: err.Error()})
+		}
+	}
+
+	return task, rowErrors
+}
+
+func setCSVValue(task *domain.Task, column domain.CSVColumn, value string, projectIDs map[string]string) error {
+	switch column {
+	case domain.CSVColumnID:
+		if value != "" {
+			task.ID = value
+		}
+	case domain.CSVColumnTitle:
+		task.Title = value
+		return validation.Validate(value, validation.Required, validation.By(validateTitle))
+	case domain.CSVColumnDescription:
+		task.Description = value
+		return validateDescription(&value)
+	case domain.CSVColumnStatus:
+		if value != "" {
+			task.Status = domain.TaskStatus(strings.ToLower(value))
+		}
+		return validation.Validate(task.Status, validation.In(domain.TaskStatusTodo, domain.TaskStatusDone).Error("must be todo or done"))
+	case domain.CSVColumnPriority:
+		if value != "" {
+			task.Priority = domain.TaskPriority(strings.ToLower(value))
+		}
+		return validation.Validate(task.Priority, validation.In(
+			domain.TaskPriorityNone,
+			domain.TaskPriorityLow,
+			domain.TaskPriorityMedium,
+			domain.TaskPriorityHigh,
+		).Error("must be none, low, medium or high"))
+	case domain.CSVColumnDueDate:
+		if value == "" {
+			return nil
+		}
+		dueDate, err := parseCSVTime(value)
+		if err != nil {
+			return err
+		}
+		task.DueDate = &dueDate
+		return validateDueDate(task.DueDate)
+	case domain.CSVColumnTags:
+		var tags []string
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		task.Tags = tags
+		return validateTags(tags)
+	case domain.CSVColumnProject:
+		if value == "" {
+			return nil
+		}
+		projectID, ok := projectIDs[strings.ToLower(value)]
+		if !ok {
+			return fmt.Errorf("unknown project %q", value)
+		}
+		task.ProjectID = &projectID
+	case domain.CSVColumnCreatedAt, domain.CSVColumnModifiedAt:
+		if value == "" {
+			return nil
+		}
+		at, err := parseCSVTime(value)
+		if err != nil {
+			return err
+		}
+		if column == domain.CSVColumnCreatedAt {
+			task.CreatedAt = at
+		} else {
+			task.ModifiedAt = at
+		}
+	}
+
+	return nil
+}
+
+func parseCSVTime(value string) (time.Time, error) {
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("must be a date like 2006-01-02 or 2006-01-02T15:04:05Z07:00")
+}
+
+func validateCSVColumn(value any) error {
+	column, ok := value.(domain.CSVColumn)
+	if !ok {
+		return fmt.Errorf("must be a domain.CSVColumn")
+	}
+
+	for _, c := range domain.AllCSVColumn {
+		if c.Value == column {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown column %q", column)
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransfer_ExportCSV(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	projectID := "p1"
+	deletedAt := time.Now()
+
+	suite.mockTransferProvider.On("GetAllProjects", ctx).Return([]domain.Project{{ID: "p1", Name: "Work"}}, nil)
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return([]domain.Task{
+		{ID: "1", ProjectID: &projectID, Title: `Say "hi", then leave`, Tags: []string{"home", "errands"}, Status: domain.TaskStatusTodo},
+		{ID: "2", Title: "Deleted", DeletedAt: &deletedAt},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := suite.transferService.ExportCSV(ctx, &buf, []domain.CSVColumn{domain.CSVColumnTitle, domain.CSVColumnTags, domain.CSVColumnProject})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "title,tags,project\r\n\"Say \"\"hi\"\", then leave\",\"home, errands\",Work\r\n", buf.String())
+}
+
+func TestTransfer_ExportCSV_UnknownColumn(t *testing.T) {
+	suite := newTransferSuite(t)
+
+	err := suite.transferService.ExportCSV(context.Background(), &bytes.Buffer{}, []domain.CSVColumn{"owner"})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+}
+
+func TestTransfer_ImportCSV(t *testing.T) {
+	dueDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	file := "Task,Notes,Labels,Due,Project\n" +
+		"Buy groceries,\"milk, eggs\",\"home, errands\"," + dueDate + ",work\n" +
+		"ok,,,,\n" +
+		"Plan trip,,,not a date,Unknown\n"
+	mapping := map[string]domain.CSVColumn{
+		"Task":    domain.CSVColumnTitle,
+		"Notes":   domain.CSVColumnDescription,
+		"Labels":  domain.CSVColumnTags,
+		"Due":     domain.CSVColumnDueDate,
+		"Project": domain.CSVColumnProject,
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+
+		suite.mockTransferProvider.On("GetAllProjects", ctx).Return([]domain.Project{{ID: "p1", Name: "Work"}}, nil)
+
+		result, err := suite.transferService.ImportCSV(ctx, strings.NewReader(file), domain.CSVImportOptions{Mapping: mapping, DryRun: true})
+
+		assert.NoError(t, err)
+		if assert.Len(t, result.Tasks, 1) {
+			assert.Equal(t, "Buy groceries", result.Tasks[0].Title)
+			assert.Equal(t, "milk, eggs", result.Tasks[0].Description)
+			assert.Equal(t, domain.StringArray{"home", "errands"}, result.Tasks[0].Tags)
+			assert.Equal(t, "p1", *result.Tasks[0].ProjectID)
+		}
+		assert.Equal(t, []domain.CSVRowError{
+			{Row: 3, Field: domain.CSVColumnTitle, Message: "must be between 3 and 250 characters"},
+			{Row: 4, Field: domain.CSVColumnDueDate, Message: "must be a date like 2006-01-02 or 2006-01-02T15:04:05Z07:00"},
+			{Row: 4, Field: domain.CSVColumnProject, Message: `unknown project "Unknown"`},
+		}, result.Errors)
+		suite.mockTransferModifier.AssertNotCalled(t, "ImportData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+
+		suite.mockTransferProvider.On("GetAllProjects", ctx).Return([]domain.Project{{ID: "p1", Name: "Work"}}, nil)
+		suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 1 && tasks[0].Title == "Buy groceries"
+		}), false).Return(nil)
+
+		result, err := suite.transferService.ImportCSV(ctx, strings.NewReader(file), domain.CSVImportOptions{Mapping: mapping})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Errors, 3)
+	})
+}
+
+func TestTransfer_ImportCSV_ExistingID(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	parentID := "1"
+	dueDate := time.Now().AddDate(0, 0, 1)
+	stored := domain.Task{
+		ID:         "2",
+		ParentID:   &parentID,
+		Title:      "Buy milk",
+		Status:     domain.TaskStatusTodo,
+		Priority:   domain.TaskPriorityHigh,
+		DueDate:    &dueDate,
+		Recurrence: &domain.Recurrence{Frequency: domain.RecurrenceDaily, Interval: 1},
+		Reminders:  []domain.Reminder{{ID: "r1", BeforeMinutes: 30}},
+		Version:    3,
+	}
+
+	suite.mockTransferProvider.On("GetAllProjects", ctx).Return(nil, nil)
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return([]domain.Task{{ID: "1", Title: "Groceries"}, stored}, nil)
+
+	result, err := suite.transferService.ImportCSV(ctx, strings.NewReader("id,title\n2,Buy oat milk\n3,Buy bread\n"), domain.CSVImportOptions{DryRun: true})
+
+	assert.NoError(t, err)
+	if assert.Len(t, result.Tasks, 2) {
+		updated := stored
+		updated.Title = "Buy oat milk"
+		updated.ModifiedAt = result.Tasks[0].ModifiedAt
+		assert.Equal(t, updated, result.Tasks[0], "the fields without a column are kept")
+		assert.Equal(t, "3", result.Tasks[1].ID)
+		assert.Nil(t, result.Tasks[1].ParentID)
+	}
+}
+
+func TestTransfer_ImportCSV_Header(t *testing.T) {
+	t.Run("headers named like fields", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+
+		suite.mockTransferProvider.On("GetAllProjects", ctx).Return(nil, nil)
+
+		result, err := suite.transferService.ImportCSV(ctx, strings.NewReader("\ufeffTitle,Priority,Owner\nCall back,HIGH,Bob\n"), domain.CSVImportOptions{DryRun: true})
+
+		assert.NoError(t, err)
+		if assert.Len(t, result.Tasks, 1) {
+			assert.Equal(t, domain.TaskPriorityHigh, result.Tasks[0].Priority)
+		}
+	})
+
+	t.Run("title not mapped", func(t *testing.T) {
+		suite := newTransferSuite(t)
+
+		_, err := suite.transferService.ImportCSV(context.Background(), strings.NewReader("Name\nCall back\n"), domain.CSVImportOptions{DryRun: true})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
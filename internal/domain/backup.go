@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultBackupInterval is how often the database is backed up while the app runs.
+	DefaultBackupInterval = 24 * time.Hour
+	// DefaultBackupsKept is how many backups are kept, older ones are deleted when a new one is made.
+	DefaultBackupsKept = 10
+)
+
+// BackupReason tells why a backup was made.
+type BackupReason string
+
+const (
+	BackupScheduled BackupReason = "scheduled"
+	BackupManual    BackupReason = "manual"
+	// BackupMigration is made before the database schema is migrated.
+	BackupMigration BackupReason = "migration"
+	// BackupRestore is made before another backup is restored, so the restore can be undone.
+	BackupRestore BackupReason = "restore"
+)
+
+var AllBackupReason = []struct {
+	Value  BackupReason
+	TSName string
+}{
+	{BackupScheduled, "SCHEDULED"},
+	{BackupManual, "MANUAL"},
+	{BackupMigration, "MIGRATION"},
+	{BackupRestore, "RESTORE"},
+}
+
+// Backup is a copy of the whole database, identified by its file name in the backups directory.
+type Backup struct {
+	Name      string       `json:"name"`
+	Reason    BackupReason `json:"reason"`
+	Size      int64        `json:"size"` // in bytes
+	CreatedAt time.Time    `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+// CalDAVAccount is the task list on a CalDAV server, e.g. Radicale or Nextcloud, the tasks are synced with.
+type CalDAVAccount struct {
+	// URL is the calendar collection holding the VTODO resources, e.g. https://dav.example.com/alice/tasks/.
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+// CalDAVResource ties a task to the VTODO resource it is stored as on the server.
+type CalDAVResource struct {
+	TaskID string
+	Href   string // path of the resource on the server
+	ETag   string // of the resource when it was last pushed or pulled, empty when the server did not tell
+	// Version is the version of the task when it was last pushed or pulled, a later version is a local change.
+	Version int
+}
+
+// CalDAVState is what the app remembers between syncs with the CalDAV server.
+type CalDAVState struct {
+	Account CalDAVAccount // its URL is empty while the CalDAV sync is off
+	// SyncToken names the state of the collection at the previous sync, the server reports the changes since then.
+	SyncToken string
+	Resources map[string]CalDAVResource // by task ID
+}
+
+// CalDAVReport counts the changes made by a sync with the CalDAV server.
+type CalDAVReport struct {
+	Pulled  int `json:"pulled"`  // tasks created or updated from the server
+	Pushed  int `json:"pushed"`  // tasks created or updated on the server
+	Deleted int `json:"deleted"` // tasks deleted on either side
+}
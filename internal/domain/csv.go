@@ -0,0 +1,65 @@
+package domain
+
+// CSVColumn is a task field that can be written to or read from a CSV column.
+type CSVColumn string
+
+const (
+	CSVColumnID          CSVColumn = "id"
+	CSVColumnTitle       CSVColumn = "title"
+	CSVColumnDescription CSVColumn = "description"
+	CSVColumnStatus      CSVColumn = "status"
+	CSVColumnPriority    CSVColumn = "priority"
+	CSVColumnDueDate     CSVColumn = "due_date"
+	CSVColumnTags        CSVColumn = "tags"    // comma separated tag names
+	CSVColumnProject     CSVColumn = "project" // project name, empty for the inbox
+	CSVColumnCreatedAt   CSVColumn = "created_at"
+	CSVColumnModifiedAt  CSVColumn = "modified_at"
+)
+
+var AllCSVColumn = []struct {
+	Value  CSVColumn
+	TSName string
+}{
+	{CSVColumnID, "ID"},
+	{CSVColumnTitle, "TITLE"},
+	{CSVColumnDescription, "DESCRIPTION"},
+	{CSVColumnStatus, "STATUS"},
+	{CSVColumnPriority, "PRIORITY"},
+	{CSVColumnDueDate, "DUE_DATE"},
+	{CSVColumnTags, "TAGS"},
+	{CSVColumnProject, "PROJECT"},
+	{CSVColumnCreatedAt, "CREATED_AT"},
+	{CSVColumnModifiedAt, "MODIFIED_AT"},
+}
+
+// DefaultCSVColumns are exported when no columns are chosen.
+var DefaultCSVColumns = []CSVColumn{
+	CSVColumnTitle,
+	CSVColumnDescription,
+	CSVColumnStatus,
+	CSVColumnPriority,
+	CSVColumnDueDate,
+	CSVColumnTags,
+	CSVColumnProject,
+}
+
+type CSVImportOptions struct {
+	// Mapping maps CSV headers to task fields, headers left out are ignored.
+	// When empty, headers named like a CSVColumn are mapped to it ignoring case.
+	Mapping map[string]CSVColumn `json:"mapping"`
+	// DryRun only reports what would be imported.
+	DryRun bool `json:"dry_run"`
+}
+
+// CSVRowError is a row that cannot be imported, Row is the line number with the header on line 1.
+type CSVRowError struct {
+	Row     int       `json:"row"`
+	Field   CSVColumn `json:"field"`
+	Message string    `json:"message"`
+}
+
+type CSVImportResult struct {
+	Headers []string      `json:"headers"`
+	Tasks   []Task        `json:"tasks"`  // valid rows, imported unless it was a dry run
+	Errors  []CSVRowError `json:"errors"` // rows that were skipped
+}
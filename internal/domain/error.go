@@ -3,8 +3,17 @@ package domain
 import "errors"
 
 var (
-	ErrTaskNotFound     = errors.New("task not found")
-	ErrInvalidArguments = errors.New("invalid arguments")
-	ErrInternal         = errors.New("internal error")
-	ErrCancelled        = errors.New("cancelled")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrTagNotFound        = errors.New("tag not found")
+	ErrTagAlreadyExists   = errors.New("tag already exists")
+	ErrInvalidArguments   = errors.New("invalid arguments")
+	ErrInternal           = errors.New("internal error")
+	ErrCancelled          = errors.New("cancelled")
+	ErrIncompleteSubtasks = errors.New("task has incomplete subtasks")
+	ErrNothingToUndo      = errors.New("nothing to undo")
+	ErrNothingToRedo      = errors.New("nothing to redo")
+	ErrReminderNotFound   = errors.New("reminder not found")
+	ErrBackupNotFound     = errors.New("backup not found")
+	ErrConflict           = errors.New("task was changed in the meantime")
 )
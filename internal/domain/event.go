@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type TaskEventAction string
+
+const (
+	TaskEventCreated  TaskEventAction = "created"
+	TaskEventUpdated  TaskEventAction = "updated"
+	TaskEventDeleted  TaskEventAction = "deleted"
+	TaskEventRestored TaskEventAction = "restored"
+)
+
+var AllTaskEventAction = []struct {
+	Value  TaskEventAction
+	TSName string
+}{
+	{TaskEventCreated, "CREATED"},
+	{TaskEventUpdated, "UPDATED"},
+	{TaskEventDeleted, "DELETED"},
+	{TaskEventRestored, "RESTORED"},
+}
+
+// TaskFieldChange is the old and new value of a task field, formatted as text.
+// Dates use RFC 3339 in UTC, recurrence rules their RRULE form and tags are comma separated.
+type TaskFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TaskEvent is an entry of the audit log of a task.
+type TaskEvent struct {
+	ID        int64             `json:"id"`
+	TaskID    string            `json:"task_id"`
+	Action    TaskEventAction   `json:"action"`
+	Changes   []TaskFieldChange `json:"changes"` // empty for deleted and restored
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+type HistoryAction string
+
+const (
+	HistoryActionCreate HistoryAction = "create"
+	HistoryActionUpdate HistoryAction = "update"
+	HistoryActionDelete HistoryAction = "delete"
+)
+
+var AllHistoryAction = []struct {
+	Value  HistoryAction
+	TSName string
+}{
+	{HistoryActionCreate, "CREATE"},
+	{HistoryActionUpdate, "UPDATE"},
+	{HistoryActionDelete, "DELETE"},
+}
+
+// MaxHistoryEntries bounds the undo history, the oldest entries are dropped first.
+const MaxHistoryEntries = 100
+
+// HistoryEntry is a task mutation that can be undone and redone.
+type HistoryEntry struct {
+	ID        int64         `json:"id"`
+	Action    HistoryAction `json:"action"`
+	Before    *Task         `json:"before,omitempty"` // nil for create
+	After     *Task         `json:"after,omitempty"`  // nil for delete
+	Undone    bool          `json:"undone"`
+	CreatedAt time.Time     `json:"created_at"`
+	// Effects are the mutations made along with this one, e.g. the subtasks completed with the task and the next
+	// occurrence of a completed recurring task. They are undone before it and redone after it.
+	Effects []HistoryEntry `json:"effects,omitempty"`
+}
+
+// TaskID returns the ID of the task the entry applies to.
+func (e HistoryEntry) TaskID() string {
+	if e.After != nil {
+		return e.After.ID
+	}
+	if e.Before != nil {
+		return e.Before.ID
+	}
+	return ""
+}
@@ -0,0 +1,22 @@
+package domain
+
+// MarkdownGrouping decides the sections tasks are exported in as a Markdown checklist.
+type MarkdownGrouping string
+
+const (
+	// MarkdownGroupNone writes a single checklist with subtasks nested under their parent.
+	MarkdownGroupNone MarkdownGrouping = "none"
+	// MarkdownGroupTag writes a section for each tag, a task with several tags is listed in each of them.
+	MarkdownGroupTag MarkdownGrouping = "tag"
+	// MarkdownGroupStatus writes a section for the tasks to do and one for the done tasks.
+	MarkdownGroupStatus MarkdownGrouping = "status"
+)
+
+var AllMarkdownGrouping = []struct {
+	Value  MarkdownGrouping
+	TSName string
+}{
+	{MarkdownGroupNone, "NONE"},
+	{MarkdownGroupTag, "TAG"},
+	{MarkdownGroupStatus, "STATUS"},
+}
@@ -0,0 +1,13 @@
+package domain
+
+// MigrationStatus is the schema version of a database compared with the migrations the app comes with.
+type MigrationStatus struct {
+	// Version is the last migration applied, 0 when the database has no schema yet.
+	Version uint `json:"version"`
+	// Dirty is set when the migration to Version failed halfway, the database has to be repaired
+	// and the version forced before it can be migrated again.
+	Dirty   bool `json:"dirty"`
+	Latest  uint `json:"latest"`  // the last migration the app comes with
+	Applied int  `json:"applied"` // migrations up to Version
+	Pending int  `json:"pending"` // migrations after Version
+}
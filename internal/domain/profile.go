@@ -0,0 +1,8 @@
+package domain
+
+// Profile is a separate set of tasks and projects with its own database, e.g. "work" and "personal".
+type Profile struct {
+	Name         string `json:"name"`
+	DatabasePath string `json:"database_path"`
+	Active       bool   `json:"active"`
+}
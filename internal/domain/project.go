@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type Project struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Color      string    `json:"color"` // hex color, e.g. #3b82f6
+	Archived   bool      `json:"archived"`
+	SortOrder  int       `json:"sort_order"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// ProjectDeleteMode decides what happens to the tasks of a deleted project.
+type ProjectDeleteMode string
+
+const (
+	ProjectDeleteMoveToInbox ProjectDeleteMode = "move_to_inbox"
+	ProjectDeleteTasks       ProjectDeleteMode = "delete_tasks"
+)
+
+var AllProjectDeleteMode = []struct {
+	Value  ProjectDeleteMode
+	TSName string
+}{
+	{ProjectDeleteMoveToInbox, "MOVE_TO_INBOX"},
+	{ProjectDeleteTasks, "DELETE_TASKS"},
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+type TaskSortKey string
+type SortDirection string
+
+const (
+	TaskSortCreatedAt  TaskSortKey = "created_at"
+	TaskSortModifiedAt TaskSortKey = "modified_at"
+	TaskSortDueDate    TaskSortKey = "due_date"
+	TaskSortPriority   TaskSortKey = "priority"
+	TaskSortTitle      TaskSortKey = "title"
+	TaskSortPosition   TaskSortKey = "position"
+)
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+var AllTaskSortKey = []struct {
+	Value  TaskSortKey
+	TSName string
+}{
+	{TaskSortCreatedAt, "CREATED_AT"},
+	{TaskSortModifiedAt, "MODIFIED_AT"},
+	{TaskSortDueDate, "DUE_DATE"},
+	{TaskSortPriority, "PRIORITY"},
+	{TaskSortTitle, "TITLE"},
+	{TaskSortPosition, "POSITION"},
+}
+
+var AllSortDirection = []struct {
+	Value  SortDirection
+	TSName string
+}{
+	{SortAsc, "ASC"},
+	{SortDesc, "DESC"},
+}
+
+const (
+	DefaultTaskQueryLimit = 50
+	MaxTaskQueryLimit     = 500
+)
+
+// TaskQuery filters, sorts and pages tasks. Zero values leave the corresponding filter out,
+// every time range is inclusive.
+type TaskQuery struct {
+	ProjectID       *string        `json:"project_id"`       // an empty string selects the inbox
+	ParentID        *string        `json:"parent_id"`        // selects the subtasks of the task
+	IncludeSubtasks bool           `json:"include_subtasks"` // by default only top-level tasks are listed
+	Statuses        []TaskStatus   `json:"statuses"`
+	Priorities      []TaskPriority `json:"priorities"`
+	Tags            []string       `json:"tags"` // tasks must have every listed tag
+	DueAfter        *time.Time     `json:"due_after"`
+	DueBefore       *time.Time     `json:"due_before"`
+	CreatedAfter    *time.Time     `json:"created_after"`
+	CreatedBefore   *time.Time     `json:"created_before"`
+	ModifiedAfter   *time.Time     `json:"modified_after"`
+	ModifiedBefore  *time.Time     `json:"modified_before"`
+	SortBy          TaskSortKey    `json:"sort_by"`        // defaults to created_at
+	SortDirection   SortDirection  `json:"sort_direction"` // defaults to asc
+	Cursor          string         `json:"cursor"`         // NextCursor of the previous page
+	Limit           int            `json:"limit"`          // defaults to DefaultTaskQueryLimit
+}
+
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"next_cursor"` // empty on the last page
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+	// RecurrenceAfterCompletion repeats the task every Interval days counted from the day it was completed.
+	RecurrenceAfterCompletion RecurrenceFrequency = "after_completion"
+)
+
+var AllRecurrenceFrequency = []struct {
+	Value  RecurrenceFrequency
+	TSName string
+}{
+	{RecurrenceDaily, "DAILY"},
+	{RecurrenceWeekly, "WEEKLY"},
+	{RecurrenceMonthly, "MONTHLY"},
+	{RecurrenceAfterCompletion, "AFTER_COMPLETION"},
+}
+
+// Recurrence describes how a task repeats. It is stored as an RRULE-like string,
+// e.g. "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,TH".
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval"`            // every N days/weeks/months, 0 is treated as 1
+	Weekdays  []time.Weekday      `json:"weekdays,omitempty"`  // weekly only, defaults to the weekday of the due date
+	MonthDay  int                 `json:"month_day,omitempty"` // monthly only, defaults to the day of the due date
+}
+
+var rruleWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Every returns the interval of the rule, treating an unset interval as 1.
+func (r Recurrence) Every() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + strings.ToUpper(string(r.Frequency)), "INTERVAL=" + strconv.Itoa(r.Every())}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			days[i] = rruleWeekdays[day%7]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.MonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRecurrence parses a rule previously produced by Recurrence.String.
+func ParseRecurrence(s string) (Recurrence, error) {
+	var r Recurrence
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Recurrence{}, fmt.Errorf("invalid recurrence part %q", part)
+		}
+		switch key {
+		case "FREQ":
+			r.Frequency = RecurrenceFrequency(strings.ToLower(value))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil {
+				return Recurrence{}, fmt.Errorf("invalid recurrence interval %q: %w", value, err)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday := -1
+				for i, name := range rruleWeekdays {
+					if name == day {
+						weekday = i
+					}
+				}
+				if weekday < 0 {
+					return Recurrence{}, fmt.Errorf("invalid recurrence weekday %q", day)
+				}
+				r.Weekdays = append(r.Weekdays, time.Weekday(weekday))
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil {
+				return Recurrence{}, fmt.Errorf("invalid recurrence month day %q: %w", value, err)
+			}
+			r.MonthDay = day
+		default:
+			return Recurrence{}, fmt.Errorf("unknown recurrence part %q", key)
+		}
+	}
+	if r.Frequency == "" {
+		return Recurrence{}, fmt.Errorf("recurrence frequency is missing")
+	}
+
+	return r, nil
+}
+
+func (r *Recurrence) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("failed to cast value to string: %v", value)
+	}
+	parsed, err := ParseRecurrence(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r Recurrence) Value() (driver.Value, error) {
+	return r.String(), nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reminder goes off either at an absolute time or a number of minutes before the due date of its task.
+type Reminder struct {
+	ID            string     `json:"id"`
+	At            *time.Time `json:"at,omitempty"`   // absolute time, nil for reminders relative to the due date
+	BeforeMinutes int        `json:"before_minutes"` // used when At is nil, 0 reminds at the due date
+	FiredAt       *time.Time `json:"fired_at,omitempty"`
+}
+
+// FireAt returns when the reminder goes off, a relative reminder of a task without a due date never does.
+func (r Reminder) FireAt(dueDate *time.Time) (time.Time, bool) {
+	if r.At != nil {
+		return *r.At, true
+	}
+	if dueDate == nil {
+		return time.Time{}, false
+	}
+	return dueDate.Add(-time.Duration(r.BeforeMinutes) * time.Minute), true
+}
+
+// Reminders reads a JSON array of reminders, as produced by json_group_array.
+type Reminders []Reminder
+
+func (r *Reminders) Scan(value interface{}) error {
+	if value == nil {
+		return nil // case when value from the db was NULL
+	}
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("failed to cast value to string: %v", value)
+	}
+	var reminders []Reminder
+	if err := json.Unmarshal([]byte(s), &reminders); err != nil {
+		return fmt.Errorf("failed to decode reminders: %w", err)
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+	*r = reminders
+	return nil
+}
+
+// ReminderNotification is sent when a reminder goes off.
+type ReminderNotification struct {
+	ReminderID string     `json:"reminder_id"`
+	TaskID     string     `json:"task_id"`
+	Title      string     `json:"title"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	FireAt     time.Time  `json:"fire_at"`
+	Missed     bool       `json:"missed"` // the reminder went off while the app was closed
+}
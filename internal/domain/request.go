@@ -3,17 +3,65 @@ package domain
 import "time"
 
 type CreateTaskRequest struct {
-	Title    string       `json:"title"`
-	Priority TaskPriority `json:"priority"`
-	DueDate  *time.Time   `json:"due_date"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Tags        []string     `json:"tags"`
+	Priority    TaskPriority `json:"priority"`
+	DueDate     *time.Time   `json:"due_date"`
+	Recurrence  *Recurrence  `json:"recurrence"`
+	ProjectID   *string      `json:"project_id"` // nil to create the task in the inbox
+	Reminders   []Reminder   `json:"reminders"`
 }
 
 type UpdateTaskRequest struct {
-	ID          string       `json:"id"`
+	ID string `json:"id"`
+	// Version is the version of the task the changes are based on, the update is rejected with ErrConflict
+	// when the task has been changed since.
+	Version     int          `json:"version"`
 	Title       string       `json:"title"`
 	Description *string      `json:"description"` // pointer to make it optional
 	Tags        []string     `json:"tags"`
 	Status      TaskStatus   `json:"status"`
 	Priority    TaskPriority `json:"priority"`
 	DueDate     *time.Time   `json:"due_date"` // pointer to make it optional
+	// Recurrence replaces the recurrence rule of the task, a rule with an empty frequency removes it.
+	Recurrence *Recurrence `json:"recurrence"`
+	// ProjectID moves the task to another project, an empty string moves it to the inbox.
+	ProjectID *string `json:"project_id"`
+	// Reminders replaces the reminders of the task, an empty list removes them and nil keeps them.
+	Reminders []Reminder `json:"reminders"`
+	// CompleteChildren marks every unfinished subtask as done when the task is completed,
+	// otherwise completing a task with unfinished subtasks is rejected.
+	CompleteChildren bool `json:"complete_children"`
+}
+
+type MoveTaskRequest struct {
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"` // nil to move the task to the top level
+	Position int     `json:"position"`
+}
+
+type CreateProjectRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type UpdateProjectRequest struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Archived  *bool  `json:"archived"`   // pointer to make it optional
+	SortOrder *int   `json:"sort_order"` // pointer to make it optional
+}
+
+type UpdateTagRequest struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// MergeTagsRequest moves every task tagged with one of the source tags to the target tag and deletes the source tags.
+type MergeTagsRequest struct {
+	SourceIDs []string `json:"source_ids"`
+	TargetID  string   `json:"target_id"`
 }
@@ -0,0 +1,39 @@
+package domain
+
+// SearchMatchStart and SearchMatchEnd surround the matched words in search highlights and snippets.
+// They are control characters rather than markup so the UI can split on them without interpreting task text as HTML.
+const (
+	SearchMatchStart = "\x02"
+	SearchMatchEnd   = "\x03"
+)
+
+const DefaultSearchLimit = 50
+
+type SearchField string
+
+const (
+	SearchFieldAny         SearchField = ""
+	SearchFieldTitle       SearchField = "title"
+	SearchFieldDescription SearchField = "description"
+	SearchFieldTags        SearchField = "tags"
+)
+
+// SearchTerm is a single word or phrase that must be present in the searched field.
+type SearchTerm struct {
+	Field  SearchField `json:"field"`
+	Text   string      `json:"text"`   // may contain several words, which then must appear as a phrase
+	Prefix bool        `json:"prefix"` // matches words starting with the last word of Text
+}
+
+// SearchQuery matches tasks containing every one of its terms.
+type SearchQuery struct {
+	Terms []SearchTerm `json:"terms"`
+	Limit int          `json:"limit"`
+}
+
+type SearchResult struct {
+	Task    Task    `json:"task"`
+	Title   string  `json:"title"`   // title with matches highlighted
+	Snippet string  `json:"snippet"` // part of the description around the matches, empty when it does not match
+	Rank    float64 `json:"rank"`    // lower is more relevant
+}
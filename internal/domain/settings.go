@@ -0,0 +1,103 @@
+package domain
+
+import "time"
+
+// Settings are the preferences of a profile, they are kept in its database.
+type Settings struct {
+	// DefaultPriority is given to new tasks created without a priority.
+	DefaultPriority TaskPriority `json:"default_priority"`
+	// ConfirmDeletion asks before a task is moved to the trash, permanent deletions are always confirmed.
+	ConfirmDeletion bool `json:"confirm_deletion"`
+	// WeekStart is the first day of the week in calendars.
+	WeekStart  time.Weekday `json:"week_start"`
+	DateFormat DateFormat   `json:"date_format"`
+	Theme      Theme        `json:"theme"`
+	// TrashRetentionDays is how long tasks stay in the trash before they are deleted permanently.
+	TrashRetentionDays int `json:"trash_retention_days"`
+	// WindowWidth and WindowHeight are the size of the window when the app starts.
+	WindowWidth  int `json:"window_width"`
+	WindowHeight int `json:"window_height"`
+}
+
+// DefaultSettings are used for the settings that were never changed.
+func DefaultSettings() Settings {
+	return Settings{
+		DefaultPriority:    TaskPriorityNone,
+		ConfirmDeletion:    true,
+		WeekStart:          time.Monday,
+		DateFormat:         DateFormatISO,
+		Theme:              ThemeSystem,
+		TrashRetentionDays: int(DefaultTrashRetention / (24 * time.Hour)),
+		WindowWidth:        1024,
+		WindowHeight:       768,
+	}
+}
+
+// TrashRetention returns TrashRetentionDays as a duration.
+func (s Settings) TrashRetention() time.Duration {
+	return time.Duration(s.TrashRetentionDays) * 24 * time.Hour
+}
+
+// UpdateSettingsRequest changes the given settings, empty strings and nil pointers keep the current value.
+type UpdateSettingsRequest struct {
+	DefaultPriority    TaskPriority  `json:"default_priority"`
+	ConfirmDeletion    *bool         `json:"confirm_deletion"`
+	WeekStart          *time.Weekday `json:"week_start"`
+	DateFormat         DateFormat    `json:"date_format"`
+	Theme              Theme         `json:"theme"`
+	TrashRetentionDays *int          `json:"trash_retention_days"`
+	WindowWidth        *int          `json:"window_width"`
+	WindowHeight       *int          `json:"window_height"`
+}
+
+// DateFormat is how dates are shown, the value is the pattern with YYYY, MM and DD for the year, month and day.
+type DateFormat string
+
+const (
+	DateFormatISO      DateFormat = "YYYY-MM-DD"
+	DateFormatEuropean DateFormat = "DD.MM.YYYY"
+	DateFormatBritish  DateFormat = "DD/MM/YYYY"
+	DateFormatAmerican DateFormat = "MM/DD/YYYY"
+)
+
+var AllDateFormat = []struct {
+	Value  DateFormat
+	TSName string
+}{
+	{DateFormatISO, "ISO"},
+	{DateFormatEuropean, "EUROPEAN"},
+	{DateFormatBritish, "BRITISH"},
+	{DateFormatAmerican, "AMERICAN"},
+}
+
+// Layout returns the time layout of the format, e.g. 2006-01-02.
+func (f DateFormat) Layout() string {
+	switch f {
+	case DateFormatEuropean:
+		return "02.01.2006"
+	case DateFormatBritish:
+		return "02/01/2006"
+	case DateFormatAmerican:
+		return "01/02/2006"
+	default:
+		return "2006-01-02"
+	}
+}
+
+type Theme string
+
+const (
+	// ThemeSystem follows the light or dark mode of the operating system.
+	ThemeSystem Theme = "system"
+	ThemeLight  Theme = "light"
+	ThemeDark   Theme = "dark"
+)
+
+var AllTheme = []struct {
+	Value  Theme
+	TSName string
+}{
+	{ThemeSystem, "SYSTEM"},
+	{ThemeLight, "LIGHT"},
+	{ThemeDark, "DARK"},
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HLC is a hybrid logical clock timestamp. It follows the wall clock of the device that made the change,
+// but never goes backwards and always lands after every timestamp the device has seen from the others,
+// so the order of two changes to the same field is the same on every device even when their clocks disagree.
+type HLC struct {
+	Wall    int64  // unix milliseconds
+	Logical uint32 // orders the changes made within the same millisecond or while the wall clock lags behind
+	Device  string // breaks ties between devices
+}
+
+// Compare returns -1, 0 or +1 when t is before, the same as or after other.
+func (t HLC) Compare(other HLC) int {
+	if c := cmp.Compare(t.Wall, other.Wall); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(t.Logical, other.Logical); c != 0 {
+		return c
+	}
+	return cmp.Compare(t.Device, other.Device)
+}
+
+// String reads like 1718000000000.3@4f1c2a9e.
+func (t HLC) String() string {
+	return fmt.Sprintf("%d.%d@%s", t.Wall, t.Logical, t.Device)
+}
+
+// ParseHLC reads a timestamp written by HLC.String.
+func ParseHLC(value string) (HLC, error) {
+	clock, device, ok := strings.Cut(value, "@")
+	if !ok {
+		return HLC{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	wall, logical, ok := strings.Cut(clock, ".")
+	if !ok {
+		return HLC{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	w, err := strconv.ParseInt(wall, 10, 64)
+	if err != nil {
+		return HLC{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+	l, err := strconv.ParseUint(logical, 10, 32)
+	if err != nil {
+		return HLC{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+	return HLC{Wall: w, Logical: uint32(l), Device: device}, nil
+}
+
+func (t HLC) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *HLC) UnmarshalText(text []byte) error {
+	parsed, err := ParseHLC(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// SyncEntity is the kind of record a synced field belongs to.
+type SyncEntity string
+
+const (
+	SyncEntityTask    SyncEntity = "task"
+	SyncEntityProject SyncEntity = "project"
+)
+
+// SyncKey names a field of a task or project, fields are named like in their JSON.
+type SyncKey struct {
+	Entity SyncEntity
+	ID     string
+	Field  string
+}
+
+// SyncField is the value of a field and the timestamp of the change that set it.
+type SyncField struct {
+	Value     json.RawMessage
+	Timestamp HLC
+}
+
+// SyncChange is a line of the change log of a device, it sets a field of a task or project.
+type SyncChange struct {
+	Timestamp HLC             `json:"ts"`
+	Entity    SyncEntity      `json:"entity"`
+	ID        string          `json:"id"`
+	Field     string          `json:"field"`
+	Value     json.RawMessage `json:"value"`
+}
+
+func (c SyncChange) Key() SyncKey {
+	return SyncKey{Entity: c.Entity, ID: c.ID, Field: c.Field}
+}
+
+// SyncState is what a device remembers between syncs with the shared folder.
+type SyncState struct {
+	Directory string // the shared folder, empty while syncing is off
+	Device    string // names the change log of this device in the folder
+	Clock     HLC    // the latest timestamp made or seen
+	// Offsets are how many bytes of the change log of each device have been merged.
+	Offsets map[string]int64
+	// Fields are the last known values of every synced field, local changes are found by comparing with them.
+	Fields map[SyncKey]SyncField
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type Tag struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Color      string    `json:"color"`       // hex color, e.g. #3b82f6, empty for the default color
+	UsageCount int       `json:"usage_count"` // number of tasks with the tag
+	CreatedAt  time.Time `json:"created_at"`
+}
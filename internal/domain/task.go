@@ -1,12 +1,12 @@
 package domain
 
 import (
-	"database/sql/driver"
+	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 )
 
+// StringArray reads a JSON array of strings, as produced by json_group_array.
 type StringArray []string
 
 func (a *StringArray) Scan(value interface{}) error {
@@ -17,30 +17,47 @@ func (a *StringArray) Scan(value interface{}) error {
 	if !ok {
 		return fmt.Errorf("failed to cast value to string: %v", value)
 	}
-	tags := strings.Split(s, ",")
-	if len(tags) == 0 {
+	var values []string
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return fmt.Errorf("failed to decode string array: %w", err)
+	}
+	if len(values) == 0 {
 		return nil
 	}
-	*a = tags
+	*a = values
 	return nil
 }
 
-func (a StringArray) Value() driver.Value {
-	return strings.Join(a, ",")
-}
-
 type Task struct {
 	ID          string       `json:"id"`
+	ProjectID   *string      `json:"project_id,omitempty"` // nil for tasks in the inbox
+	ParentID    *string      `json:"parent_id,omitempty"`  // nil for top-level tasks
+	Position    int          `json:"position"`             // order among siblings
 	Title       string       `json:"title"`
 	Description string       `json:"description"`
 	Tags        StringArray  `json:"tags"`
 	Status      TaskStatus   `json:"status"`
 	Priority    TaskPriority `json:"priority"`
 	DueDate     *time.Time   `json:"due_date,omitempty"`
+	Recurrence  *Recurrence  `json:"recurrence,omitempty"` // nil for one-off tasks
+	Reminders   Reminders    `json:"reminders"`
 	CreatedAt   time.Time    `json:"created_at"`
 	ModifiedAt  time.Time    `json:"modified_at"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty"` // set while the task is in the trash
+	// Version is incremented on every change, an update must carry the version it was based on.
+	Version int `json:"version"`
 }
 
+// TaskCompletion is a task being completed together with the subtasks completed along with it.
+type TaskCompletion struct {
+	Task     Task
+	Children []Task
+	Next     *Task // the next occurrence of a recurring task, nil for one-off tasks
+}
+
+// DefaultTrashRetention is how long tasks stay in the trash before they are deleted permanently.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
 type TaskStatus string
 type TaskPriority string
 
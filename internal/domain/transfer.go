@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ExportVersion is the version of the export document written by this build,
+// documents of newer versions are rejected on import.
+const ExportVersion = 1
+
+// ExportDocument holds the whole task database, including subtasks and the tasks in the trash.
+type ExportDocument struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Projects   []Project `json:"projects"`
+	Tasks      []Task    `json:"tasks"`
+}
+
+// ImportMode decides how imported tasks are combined with the existing ones.
+type ImportMode string
+
+const (
+	// ImportMerge adds new tasks and updates existing ones with the same ID when the imported copy is newer.
+	ImportMerge ImportMode = "merge"
+	// ImportReplace deletes every existing task and project before importing.
+	ImportReplace ImportMode = "replace"
+)
+
+var AllImportMode = []struct {
+	Value  ImportMode
+	TSName string
+}{
+	{ImportMerge, "MERGE"},
+	{ImportReplace, "REPLACE"},
+}
+
+type ImportReport struct {
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Conflicts []ImportConflict `json:"conflicts"` // tasks that were not imported
+}
+
+// ImportConflict is an imported task that was skipped because the existing copy was modified after it.
+type ImportConflict struct {
+	TaskID             string    `json:"task_id"`
+	Title              string    `json:"title"`
+	ImportedModifiedAt time.Time `json:"imported_modified_at"`
+	ExistingModifiedAt time.Time `json:"existing_modified_at"`
+}
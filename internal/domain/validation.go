@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationCode tells what is wrong with a field, so the frontend can show its own message.
+type ValidationCode string
+
+const (
+	ValidationRequired ValidationCode = "required"
+	ValidationTooShort ValidationCode = "too_short"
+	ValidationTooLong  ValidationCode = "too_long"
+	// ValidationTooSmall is for numbers and dates below the minimum.
+	ValidationTooSmall ValidationCode = "too_small"
+	// ValidationTooLarge is for numbers and dates above the maximum.
+	ValidationTooLarge ValidationCode = "too_large"
+	// ValidationNotAllowed is for a field that must be empty given the other fields.
+	ValidationNotAllowed ValidationCode = "not_allowed"
+	// ValidationInvalid is for a value that is not one of the allowed values or is malformed.
+	ValidationInvalid ValidationCode = "invalid"
+)
+
+var AllValidationCode = []struct {
+	Value  ValidationCode
+	TSName string
+}{
+	{ValidationRequired, "REQUIRED"},
+	{ValidationTooShort, "TOO_SHORT"},
+	{ValidationTooLong, "TOO_LONG"},
+	{ValidationTooSmall, "TOO_SMALL"},
+	{ValidationTooLarge, "TOO_LARGE"},
+	{ValidationNotAllowed, "NOT_ALLOWED"},
+	{ValidationInvalid, "INVALID"},
+}
+
+// FieldError is what is wrong with a single field of a request.
+type FieldError struct {
+	// Field is the JSON name of the field, nested fields are joined with dots, e.g. recurrence.interval or tags.0.
+	Field   string         `json:"field"`
+	Code    ValidationCode `json:"code"`
+	Message string         `json:"message"`
+}
+
+// ValidationError is returned for invalid arguments instead of ErrInvalidArguments, which it matches with errors.Is.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// NewValidationError returns a ValidationError for a single field.
+func NewValidationError(field string, code ValidationCode, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+// Sort orders the fields by name, so the error reads the same every time.
+func (e *ValidationError) Sort() {
+	sort.SliceStable(e.Fields, func(i, j int) bool {
+		return e.Fields[i].Field < e.Fields[j].Field
+	})
+}
+
+// Error reads like "invalid arguments: title: must be between 3 and 250 characters; due_date: must be at least tomorrow".
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			messages = append(messages, field.Message)
+			continue
+		}
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return ErrInvalidArguments.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidArguments
+}
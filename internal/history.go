@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/labstack/gommon/log"
+)
+
+//go:generate mockery --name TaskHistory
+type TaskHistory interface {
+	PushHistory(ctx context.Context, entry domain.HistoryEntry, limit int) error
+	GetUndoEntry(ctx context.Context) (domain.HistoryEntry, error)
+	GetRedoEntry(ctx context.Context) (domain.HistoryEntry, error)
+	ApplyHistory(ctx context.Context, entry domain.HistoryEntry, undo bool) error
+	DeleteHistoryEntry(ctx context.Context, id int64) error
+}
+
+// record adds a mutation to the undo history, together with the mutations made along with it.
+// The mutation has already been applied, so a failure to record it is only logged.
+func (t Task) record(ctx context.Context, action domain.HistoryAction, before, after *domain.Task, effects ...domain.HistoryEntry) {
+	const op = "service.task.record"
+
+	entry := domain.HistoryEntry{
+		Action:    action,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now(),
+		Effects:   effects,
+	}
+	if err := t.history.PushHistory(ctx, entry, domain.MaxHistoryEntries); err != nil {
+		log.Error(op, err)
+	}
+}
+
+// Undo reverts the most recent task mutation and returns the entry that was undone.
+func (t Task) Undo(ctx context.Context) (domain.HistoryEntry, error) {
+	const op = "service.task.undo"
+
+	undoCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return t.applyHistory(undoCtx, op, true)
+}
+
+// Redo applies the most recently undone task mutation again and returns the entry that was redone.
+func (t Task) Redo(ctx context.Context) (domain.HistoryEntry, error) {
+	const op = "service.task.redo"
+
+	redoCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return t.applyHistory(redoCtx, op, false)
+}
+
+// applyHistory reverts the next entry to undo when undo is set and replays the next entry to redo otherwise.
+// Entries whose task no longer exists are dropped on the way, so they do not have to be skipped one by one.
+func (t Task) applyHistory(ctx context.Context, op string, undo bool) (domain.HistoryEntry, error) {
+	next := t.history.GetRedoEntry
+	if undo {
+		next = t.history.GetUndoEntry
+	}
+
+	for {
+		entry, err := next(ctx)
+		if err != nil {
+			return domain.HistoryEntry{}, handleError(op, err)
+		}
+
+		err = t.history.ApplyHistory(ctx, entry, undo)
+		if err == nil {
+			entry.Undone = undo
+			return entry, nil
+		}
+		if !t.dropHistory(ctx, op, entry, err) {
+			return domain.HistoryEntry{}, handleError(op, err)
+		}
+	}
+}
+
+// dropHistory removes an entry whose task no longer exists, e.g. after the trash was emptied, and reports
+// whether it did. An entry of a task that is only in the trash is kept, it can be applied again once the task
+// is restored.
+func (t Task) dropHistory(ctx context.Context, op string, entry domain.HistoryEntry, err error) bool {
+	if !errors.Is(err, domain.ErrTaskNotFound) {
+		return false
+	}
+
+	inTrash, err := t.provider.IsInTrash(ctx, entry.TaskID())
+	if err != nil {
+		log.Error(op, err)
+		return false
+	}
+	if inTrash {
+		return false
+	}
+
+	if err := t.history.DeleteHistoryEntry(ctx, entry.ID); err != nil {
+		log.Error(op, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newStorageTasks returns a task service backed by a database of its own.
+func newStorageTasks(t *testing.T) Task {
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	return NewTask(storage, storage, storage)
+}
+
+func TestTask_Update_RecordsHistory(t *testing.T) {
+	suite := newSuite(t)
+	ctx := context.Background()
+	before := domain.Task{ID: "123", Version: 1, Title: "Old title", Status: domain.TaskStatusTodo}
+
+	suite.mockTaskProvider.On("GetTaskByID", ctx, "123").Return(before, nil)
+	suite.mockTaskModifier.On("UpdateTask", mock.Anything, mock.AnythingOfType("domain.Task")).Return(
+		func(_ context.Context, task domain.Task) (domain.Task, error) { return task, nil },
+	)
+
+	_, err := suite.taskService.Update(ctx, domain.UpdateTaskRequest{ID: "123", Version: 1, Title: "New title"})
+
+	assert.NoError(t, err)
+	suite.mockTaskHistory.AssertCalled(t, "PushHistory", mock.Anything, mock.MatchedBy(func(entry domain.HistoryEntry) bool {
+		return entry.Action == domain.HistoryActionUpdate && entry.Before.Title == "Old title" && entry.After.Title == "New title"
+	}), domain.MaxHistoryEntries)
+}
+
+func TestTask_Undo(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		entry := domain.HistoryEntry{
+			ID:     1,
+			Action: domain.HistoryActionUpdate,
+			Before: &domain.Task{ID: "123", Status: domain.TaskStatusTodo},
+			After:  &domain.Task{ID: "123", Status: domain.TaskStatusDone},
+		}
+
+		suite.mockTaskHistory.On("GetUndoEntry", mock.Anything).Return(entry, nil)
+		suite.mockTaskHistory.On("ApplyHistory", mock.Anything, entry, true).Return(nil)
+
+		undone, err := suite.taskService.Undo(ctx)
+
+		assert.NoError(t, err)
+		assert.True(t, undone.Undone)
+	})
+
+	t.Run("task no longer exists", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		purged := domain.HistoryEntry{ID: 2, Action: domain.HistoryActionDelete, Before: &domain.Task{ID: "123"}}
+		entry := domain.HistoryEntry{ID: 1, Action: domain.HistoryActionCreate, After: &domain.Task{ID: "456"}}
+
+		suite.mockTaskHistory.On("GetUndoEntry", mock.Anything).Return(purged, nil).Once()
+		suite.mockTaskHistory.On("ApplyHistory", mock.Anything, purged, true).Return(domain.ErrTaskNotFound)
+		suite.mockTaskProvider.On("IsInTrash", mock.Anything, "123").Return(false, nil)
+		suite.mockTaskHistory.On("DeleteHistoryEntry", mock.Anything, int64(2)).Return(nil)
+		suite.mockTaskHistory.On("GetUndoEntry", mock.Anything).Return(entry, nil).Once()
+		suite.mockTaskHistory.On("ApplyHistory", mock.Anything, entry, true).Return(nil)
+
+		undone, err := suite.taskService.Undo(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), undone.ID, "the entry of the purged task is skipped")
+	})
+
+	t.Run("task in the trash", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		entry := domain.HistoryEntry{
+			ID:     1,
+			Action: domain.HistoryActionUpdate,
+			Before: &domain.Task{ID: "123", Title: "Old title"},
+			After:  &domain.Task{ID: "123", Title: "New title"},
+		}
+
+		suite.mockTaskHistory.On("GetUndoEntry", mock.Anything).Return(entry, nil)
+		suite.mockTaskHistory.On("ApplyHistory", mock.Anything, entry, true).Return(domain.ErrTaskNotFound)
+		suite.mockTaskProvider.On("IsInTrash", mock.Anything, "123").Return(true, nil)
+
+		_, err := suite.taskService.Undo(ctx)
+
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		suite.mockTaskHistory.AssertNotCalled(t, "DeleteHistoryEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty history", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		suite.mockTaskHistory.On("GetUndoEntry", mock.Anything).Return(domain.HistoryEntry{}, domain.ErrNothingToUndo)
+
+		_, err := suite.taskService.Undo(ctx)
+
+		assert.ErrorIs(t, err, domain.ErrNothingToUndo)
+	})
+}
+
+func TestTask_Redo(t *testing.T) {
+	suite := newSuite(t)
+	ctx := context.Background()
+	entry := domain.HistoryEntry{ID: 1, Action: domain.HistoryActionDelete, Before: &domain.Task{ID: "123"}, Undone: true}
+
+	suite.mockTaskHistory.On("GetRedoEntry", mock.Anything).Return(entry, nil)
+	suite.mockTaskHistory.On("ApplyHistory", mock.Anything, entry, false).Return(nil)
+
+	redone, err := suite.taskService.Redo(ctx)
+
+	assert.NoError(t, err)
+	assert.False(t, redone.Undone)
+}
+
+func TestTask_Undo_SkipsPurgedTasks(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+
+	kept, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Write report", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+	for _, title := range []string{"Buy milk", "Call mom"} {
+		task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone})
+		require.NoError(t, err)
+		require.NoError(t, tasks.Delete(ctx, task.ID))
+	}
+	_, err = tasks.EmptyTrash(ctx)
+	require.NoError(t, err)
+
+	undone, err := tasks.Undo(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, kept.ID, undone.TaskID(), "the entries of the purged tasks are skipped")
+	_, err = tasks.GetByID(ctx, kept.ID)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound, "the creation is undone")
+	_, err = tasks.Undo(ctx)
+	assert.ErrorIs(t, err, domain.ErrNothingToUndo)
+}
+
+func TestTask_Undo_FailureChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	tasks := NewTask(storage, storage, storage)
+
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Plan trip", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+	subtask, err := tasks.CreateSubtask(ctx, task.ID, domain.CreateTaskRequest{Title: "Book flights", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+	done := func(task domain.Task) *domain.Task {
+		task.Status = domain.TaskStatusDone
+		return &task
+	}
+	// the subtask effect is reverted first, the broken effect fails the undo halfway
+	err = storage.PushHistory(ctx, domain.HistoryEntry{
+		Action:    domain.HistoryActionUpdate,
+		Before:    &task,
+		After:     done(task),
+		CreatedAt: time.Now(),
+		Effects: []domain.HistoryEntry{
+			{Action: "broken", Before: &task, After: &task},
+			{Action: domain.HistoryActionUpdate, Before: &subtask, After: done(subtask)},
+		},
+	}, domain.MaxHistoryEntries)
+	require.NoError(t, err)
+	_, err = storage.UpdateTask(ctx, *done(task))
+	require.NoError(t, err)
+	_, err = storage.UpdateTask(ctx, *done(subtask))
+	require.NoError(t, err)
+
+	_, err = tasks.Undo(ctx)
+	require.Error(t, err)
+
+	for _, id := range []string{task.ID, subtask.ID} {
+		got, err := tasks.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TaskStatusDone, got.Status, "%s is left as it was", got.Title)
+	}
+	entry, err := storage.GetUndoEntry(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, entry.TaskID(), "the entry can still be undone")
+}
+
+func TestTask_Undo_CompletedRecurringTask(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+
+	dueDate := time.Now().Add(24 * time.Hour)
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{
+		Title:      "Water plants",
+		Priority:   domain.TaskPriorityNone,
+		DueDate:    &dueDate,
+		Recurrence: &domain.Recurrence{Frequency: domain.RecurrenceDaily},
+	})
+	require.NoError(t, err)
+	subtask, err := tasks.CreateSubtask(ctx, task.ID, domain.CreateTaskRequest{Title: "Fill the can", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+
+	complete := func() {
+		current, err := tasks.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		_, err = tasks.Update(ctx, domain.UpdateTaskRequest{ID: task.ID, Version: current.Version, Status: domain.TaskStatusDone, CompleteChildren: true})
+		require.NoError(t, err)
+	}
+	open := func() []string {
+		page, err := tasks.List(ctx, domain.TaskQuery{Statuses: []domain.TaskStatus{domain.TaskStatusTodo}})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(page.Tasks))
+		for _, task := range page.Tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	complete()
+	occurrence := open()
+	require.Len(t, occurrence, 1)
+	assert.NotEqual(t, task.ID, occurrence[0], "the next occurrence")
+
+	_, err = tasks.Undo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{task.ID}, open(), "the occurrence goes to the trash")
+	restored, err := tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusTodo, restored.Status)
+	assert.NotNil(t, restored.Recurrence)
+	child, err := tasks.GetByID(ctx, subtask.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusTodo, child.Status)
+
+	t.Run("completing again creates a single occurrence", func(t *testing.T) {
+		complete()
+		assert.Len(t, open(), 1)
+	})
+
+	t.Run("redo brings the occurrence back", func(t *testing.T) {
+		_, err := tasks.Undo(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{task.ID}, open())
+		_, err = tasks.Redo(ctx)
+		require.NoError(t, err)
+		assert.Len(t, open(), 1)
+		assert.NotContains(t, open(), task.ID)
+	})
+}
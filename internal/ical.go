@@ -0,0 +1,399 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	icalProductID = "-//ARUMANDESU//todo-app//EN"
+	// icalUTCLayout is the UTC form of the DATE-TIME value type.
+	icalUTCLayout   = "20060102T150405Z"
+	icalLocalLayout = "20060102T150405"
+	icalDateLayout  = "20060102"
+	// icalLineLength is the length in octets content lines are folded at.
+	icalLineLength = 75
+)
+
+// icalPriorities are the PRIORITY values tasks are written with, each one is read back as the same priority.
+var icalPriorities = map[domain.TaskPriority]int{
+	domain.TaskPriorityNone:   0,
+	domain.TaskPriorityHigh:   1,
+	domain.TaskPriorityMedium: 5,
+	domain.TaskPriorityLow:    9,
+}
+
+// icalPriority reads a PRIORITY value using the ranges of RFC 5545, 1-4 is high, 5 medium and 6-9 low.
+func icalPriority(value int) domain.TaskPriority {
+	switch {
+	case value >= 1 && value <= 4:
+		return domain.TaskPriorityHigh
+	case value == 5:
+		return domain.TaskPriorityMedium
+	case value >= 6 && value <= 9:
+		return domain.TaskPriorityLow
+	default:
+		return domain.TaskPriorityNone
+	}
+}
+
+// writeICalendar writes the tasks as the VTODO components of a VCALENDAR object.
+func writeICalendar(w io.Writer, tasks []domain.Task) error {
+	writer := bufio.NewWriter(w)
+	now := time.Now()
+
+	writeICalLine(writer, "BEGIN", "VCALENDAR")
+	writeICalLine(writer, "VERSION", "2.0")
+	writeICalLine(writer, "PRODID", icalProductID)
+	for _, task := range tasks {
+		writeVTODO(writer, task, now)
+	}
+	writeICalLine(writer, "END", "VCALENDAR")
+
+	return writer.Flush()
+}
+
+func writeVTODO(w *bufio.Writer, task domain.Task, stamp time.Time) {
+	writeICalLine(w, "BEGIN", "VTODO")
+	writeICalLine(w, "UID", escapeICalText(task.ID))
+	writeICalLine(w, "DTSTAMP", stamp.UTC().Format(icalUTCLayout))
+	writeICalLine(w, "SUMMARY", escapeICalText(task.Title))
+	if task.Description != "" {
+		writeICalLine(w, "DESCRIPTION", escapeICalText(task.Description))
+	}
+	if task.DueDate != nil {
+		writeICalLine(w, "DUE", task.DueDate.UTC().Format(icalUTCLayout))
+	}
+	writeICalLine(w, "PRIORITY", strconv.Itoa(icalPriorities[task.Priority]))
+	if task.Status == domain.TaskStatusDone {
+		writeICalLine(w, "STATUS", "COMPLETED")
+		writeICalLine(w, "COMPLETED", task.ModifiedAt.UTC().Format(icalUTCLayout))
+	} else {
+		writeICalLine(w, "STATUS", "NEEDS-ACTION")
+	}
+	if len(task.Tags) > 0 {
+		categories := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			categories[i] = escapeICalText(tag)
+		}
+		writeICalLine(w, "CATEGORIES", strings.Join(categories, ","))
+	}
+	if task.ParentID != nil {
+		writeICalLine(w, "RELATED-TO;RELTYPE=PARENT", escapeICalText(*task.ParentID))
+	}
+	writeICalLine(w, "CREATED", task.CreatedAt.UTC().Format(icalUTCLayout))
+	writeICalLine(w, "LAST-MODIFIED", task.ModifiedAt.UTC().Format(icalUTCLayout))
+	writeICalLine(w, "END", "VTODO")
+}
+
+// writeICalLine writes a content line folded into lines of at most 75 octets, without splitting a character.
+func writeICalLine(w *bufio.Writer, name, value string) {
+	line := name + ":" + value
+	limit := icalLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLength - 1 // the leading space counts towards the length
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(value string) string {
+	return icalTextEscaper.Replace(value)
+}
+
+func unescapeICalText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// splitICalList splits a TEXT list on the commas that are not escaped.
+func splitICalList(value string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, value[start:])
+}
+
+// icalProperty is a content line split into its parts, parameter names are upper case.
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICalProperty splits a content line at the first colon outside of a quoted parameter value.
+func parseICalProperty(line string) (icalProperty, error) {
+	quoted := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	property := icalProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		property.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return property, nil
+}
+
+// parseICalTime reads a DATE or DATE-TIME value. Floating times and unknown time zones are read as local time.
+func parseICalTime(property icalProperty) (time.Time, error) {
+	value := property.value
+	if property.params["VALUE"] == "DATE" || len(value) == len(icalDateLayout) {
+		return time.ParseInLocation(icalDateLayout, value, time.Local)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalUTCLayout, value)
+	}
+
+	location := time.Local
+	if tzid := property.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	return time.ParseInLocation(icalLocalLayout, value, location)
+}
+
+// readICalendar returns the tasks of every VTODO in the stream, components nested in them such as VALARM are skipped.
+func readICalendar(r io.Reader) ([]domain.Task, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:] // unfold continuation lines
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var tasks []domain.Task
+	var task *domain.Task
+	var stamp time.Time
+	depth := 0 // components opened inside the current VTODO
+	for _, line := range lines {
+		property, err := parseICalProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case property.name == "BEGIN" && task == nil && strings.EqualFold(property.value, "VTODO"):
+			task = &domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone}
+			stamp = time.Time{}
+			continue
+		case property.name == "BEGIN" && task != nil:
+			depth++
+			continue
+		case property.name == "END" && task != nil && depth > 0:
+			depth--
+			continue
+		case property.name == "END" && task != nil:
+			if task.ID == "" {
+				task.ID = uuid.NewString()
+			}
+			if task.ModifiedAt.IsZero() {
+				task.ModifiedAt = stamp
+			}
+			if task.CreatedAt.IsZero() {
+				task.CreatedAt = task.ModifiedAt
+			}
+			tasks = append(tasks, *task)
+			task = nil
+			continue
+		case task == nil || depth > 0:
+			continue
+		}
+
+		if err := setICalProperty(task, property, &stamp); err != nil {
+			return nil, fmt.Errorf("VTODO %d: %s: %w", len(tasks)+1, property.name, err)
+		}
+	}
+	if task != nil {
+		return nil, fmt.Errorf("VTODO %d is not closed", len(tasks)+1)
+	}
+
+	return tasks, nil
+}
+
+func setICalProperty(task *domain.Task, property icalProperty, stamp *time.Time) error {
+	switch property.name {
+	case "UID":
+		task.ID = unescapeICalText(property.value)
+	case "SUMMARY":
+		task.Title = unescapeICalText(property.value)
+	case "DESCRIPTION":
+		task.Description = unescapeICalText(property.value)
+	case "PRIORITY":
+		priority, err := strconv.Atoi(property.value)
+		if err != nil {
+			return fmt.Errorf("must be a number from 0 to 9")
+		}
+		task.Priority = icalPriority(priority)
+	case "STATUS":
+		if strings.EqualFold(property.value, "COMPLETED") {
+			task.Status = domain.TaskStatusDone
+		} else {
+			task.Status = domain.TaskStatusTodo
+		}
+	case "CATEGORIES":
+		for _, category := range splitICalList(property.value) {
+			if category = strings.TrimSpace(unescapeICalText(category)); category != "" {
+				task.Tags = append(task.Tags, category)
+			}
+		}
+	case "RELATED-TO":
+		if reltype := property.params["RELTYPE"]; reltype == "" || strings.EqualFold(reltype, "PARENT") {
+			parentID := unescapeICalText(property.value)
+			task.ParentID = &parentID
+		}
+	case "DUE", "CREATED", "LAST-MODIFIED", "DTSTAMP":
+		at, err := parseICalTime(property)
+		if err != nil {
+			return fmt.Errorf("must be a date or a date-time")
+		}
+		switch property.name {
+		case "DUE":
+			task.DueDate = &at
+		case "CREATED":
+			task.CreatedAt = at
+		case "LAST-MODIFIED":
+			task.ModifiedAt = at
+		case "DTSTAMP":
+			*stamp = at
+		}
+	}
+
+	return nil
+}
+
+// withVTODOFields returns the task with the fields a VTODO holds taken from the other task, the fields
+// iCalendar has no property for, e.g. the project, the recurrence and the reminders, are kept.
+func withVTODOFields(task, vtodo domain.Task) domain.Task {
+	task.Title = vtodo.Title
+	task.Description = vtodo.Description
+	task.Status = vtodo.Status
+	task.Priority = vtodo.Priority
+	task.DueDate = vtodo.DueDate
+	task.Tags = vtodo.Tags
+	task.ParentID = vtodo.ParentID
+	return task
+}
+
+// ExportICal writes the tasks outside the trash as an iCalendar file of VTODO components.
+func (t Transfer) ExportICal(ctx context.Context, w io.Writer) error {
+	const op = "service.transfer.export_ical"
+
+	tasks, err := t.liveTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	if err := writeICalendar(w, tasks); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ImportICal reads the VTODO components of an iCalendar file and merges them with the tasks by UID,
+// the same way ImportJSON does in merge mode. A VTODO of an existing task only updates the fields it holds,
+// a task in the trash stays there.
+func (t Transfer) ImportICal(ctx context.Context, r io.Reader) (domain.ImportReport, error) {
+	const op = "service.transfer.import_ical"
+
+	tasks, err := readICalendar(r)
+	if err != nil {
+		return domain.ImportReport{}, domain.NewValidationError("file", domain.ValidationInvalid, err.Error())
+	}
+
+	current, err := t.provider.ExportTasks(ctx)
+	if err != nil {
+		return domain.ImportReport{}, handleError(op, err)
+	}
+	existing := make(map[string]domain.Task, len(current))
+	for _, task := range current {
+		existing[task.ID] = task
+	}
+
+	now := time.Now()
+	for i, task := range tasks {
+		if task.ModifiedAt.IsZero() {
+			task.ModifiedAt = now
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		if stored, ok := existing[task.ID]; ok {
+			modifiedAt := task.ModifiedAt
+			task = withVTODOFields(stored, task)
+			task.ModifiedAt = modifiedAt
+		}
+		tasks[i] = task
+	}
+
+	return t.importData(ctx, op, nil, tasks, domain.ImportMerge)
+}
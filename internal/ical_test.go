@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalendar_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 2, 3, 18, 30, 0, 0, time.UTC)
+	dueDate := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	parentID := "parent"
+	tasks := []domain.Task{
+		{
+			ID:          "1",
+			Title:       "Write the report; then, send it",
+			Description: "Line one\nLine two with a backslash \\ and a very long text that has to be folded over several lines ✓✓✓",
+			Tags:        domain.StringArray{"work", "a,b"},
+			Status:      domain.TaskStatusTodo,
+			Priority:    domain.TaskPriorityHigh,
+			DueDate:     &dueDate,
+			CreatedAt:   created,
+			ModifiedAt:  modified,
+		},
+		{ID: "2", ParentID: &parentID, Title: "Done task", Status: domain.TaskStatusDone, Priority: domain.TaskPriorityMedium, CreatedAt: created, ModifiedAt: modified},
+		{ID: "3", Title: "Low task", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, CreatedAt: created, ModifiedAt: modified},
+		{ID: "4", Title: "No priority", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone, CreatedAt: created, ModifiedAt: modified},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeICalendar(&buf, tasks))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), icalLineLength, line)
+	}
+
+	parsed, err := readICalendar(&buf)
+
+	require.NoError(t, err)
+	if assert.Len(t, parsed, len(tasks)) {
+		for i := range tasks {
+			assert.Equal(t, tasks[i].ID, parsed[i].ID)
+			assert.Equal(t, tasks[i].ParentID, parsed[i].ParentID)
+			assert.Equal(t, tasks[i].Title, parsed[i].Title)
+			assert.Equal(t, tasks[i].Description, parsed[i].Description)
+			assert.Equal(t, tasks[i].Tags, parsed[i].Tags)
+			assert.Equal(t, tasks[i].Status, parsed[i].Status)
+			assert.Equal(t, tasks[i].Priority, parsed[i].Priority)
+			assert.True(t, tasks[i].CreatedAt.Equal(parsed[i].CreatedAt))
+			assert.True(t, tasks[i].ModifiedAt.Equal(parsed[i].ModifiedAt))
+		}
+		assert.True(t, dueDate.Equal(*parsed[0].DueDate))
+	}
+}
+
+func TestICalPriority(t *testing.T) {
+	for priority, value := range icalPriorities {
+		assert.Equal(t, priority, icalPriority(value))
+	}
+	assert.Equal(t, domain.TaskPriorityHigh, icalPriority(3))
+	assert.Equal(t, domain.TaskPriorityLow, icalPriority(7))
+	assert.Equal(t, domain.TaskPriorityNone, icalPriority(12))
+}
+
+func TestReadICalendar_OtherClients(t *testing.T) {
+	file := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example Corp.//CalDAV Client//EN",
+		"BEGIN:VEVENT",
+		"UID:event",
+		"SUMMARY:Not a task",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:20070313T123432Z-456553@example.com",
+		"DTSTAMP:20070313T123432Z",
+		"DUE;VALUE=DATE:20070501",
+		"SUMMARY:Submit Quebec Income Tax Return for 2006",
+		"CATEGORIES:FAMILY,FINANCE",
+		"STATUS:NEEDS-ACTION",
+		"BEGIN:VALARM",
+		"ACTION:AUDIO",
+		"TRIGGER;RELATED=END:-P1D",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"DTSTAMP:20070313T123432Z",
+		`DUE;TZID="America/New_York":20070514T110000`,
+		"SUMMARY:Call the accountant about the",
+		"  return",
+		"PRIORITY:2",
+		"STATUS:COMPLETED",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	tasks, err := readICalendar(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	stamp := time.Date(2007, 3, 13, 12, 34, 32, 0, time.UTC)
+	assert.Equal(t, "20070313T123432Z-456553@example.com", tasks[0].ID)
+	assert.Equal(t, "Submit Quebec Income Tax Return for 2006", tasks[0].Title)
+	assert.Equal(t, domain.StringArray{"FAMILY", "FINANCE"}, tasks[0].Tags)
+	assert.Equal(t, time.Date(2007, 5, 1, 0, 0, 0, 0, time.Local), *tasks[0].DueDate)
+	assert.True(t, stamp.Equal(tasks[0].ModifiedAt))
+	assert.True(t, stamp.Equal(tasks[0].CreatedAt))
+
+	assert.NotEmpty(t, tasks[1].ID)
+	assert.Equal(t, "Call the accountant about the return", tasks[1].Title)
+	assert.Equal(t, domain.TaskPriorityHigh, tasks[1].Priority)
+	assert.Equal(t, domain.TaskStatusDone, tasks[1].Status)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err == nil {
+		assert.True(t, time.Date(2007, 5, 14, 11, 0, 0, 0, newYork).Equal(*tasks[1].DueDate))
+	}
+}
+
+func TestReadICalendar_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not closed":   "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Task\r\n",
+		"bad priority": "BEGIN:VTODO\r\nPRIORITY:high\r\nEND:VTODO\r\n",
+		"bad due date": "BEGIN:VTODO\r\nDUE:tomorrow\r\nEND:VTODO\r\n",
+		"no colon":     "BEGIN:VTODO\r\nSUMMARY\r\nEND:VTODO\r\n",
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := readICalendar(strings.NewReader(file))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTransfer_ImportICal(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	modified := time.Date(2024, 2, 3, 18, 30, 0, 0, time.UTC)
+	existing := []domain.Task{
+		{ID: "1", Title: "Write the report", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone, ModifiedAt: modified.Add(300 * time.Millisecond)},
+	}
+	file := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:1\r\nSUMMARY:Write the report\r\nLAST-MODIFIED:20240203T183000Z\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:2\r\nSUMMARY:Pay the rent\r\nPRIORITY:5\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	suite.mockTransferProvider.On("GetAllProjects", mock.Anything).Return(nil, nil)
+	suite.mockTransferProvider.On("ExportTasks", mock.Anything).Return(existing, nil)
+	suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.MatchedBy(func(tasks []domain.Task) bool {
+		return len(tasks) == 1 && tasks[0].ID == "2" && tasks[0].Priority == domain.TaskPriorityMedium && !tasks[0].CreatedAt.IsZero()
+	}), false).Return(nil)
+
+	report, err := suite.transferService.ImportICal(ctx, strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ImportReport{Created: 1, Unchanged: 1}, report, "times written in whole seconds match")
+}
+
+func TestTransfer_ImportICal_KeepsOtherFields(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	transfer := NewTransfer(storage, storage)
+
+	created := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 2, 3, 18, 30, 0, 0, time.UTC)
+	dueDate := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	deletedAt := modified.Add(time.Hour)
+	projectID := "p1"
+	recurrence := &domain.Recurrence{Frequency: domain.RecurrenceWeekly, Interval: 2}
+	reminders := domain.Reminders{{ID: "r1", BeforeMinutes: 30}}
+	require.NoError(t, storage.ImportData(ctx,
+		[]domain.Project{{ID: projectID, Name: "Garden", CreatedAt: created, ModifiedAt: created}},
+		[]domain.Task{
+			{
+				ID: "1", ProjectID: &projectID, Title: "Water the plants", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow,
+				DueDate: &dueDate, Recurrence: recurrence, Reminders: reminders, CreatedAt: created, ModifiedAt: modified,
+			},
+			{ID: "2", Title: "Old task", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone, CreatedAt: created, ModifiedAt: modified, DeletedAt: &deletedAt},
+		},
+		false,
+	))
+
+	var buf bytes.Buffer
+	require.NoError(t, transfer.ExportICal(ctx, &buf))
+	file := strings.NewReplacer(
+		"SUMMARY:Water the plants", "SUMMARY:Water the garden",
+		"LAST-MODIFIED:20240203T183000Z", "LAST-MODIFIED:20240204T090000Z",
+		"END:VCALENDAR", "BEGIN:VTODO\r\nUID:2\r\nSUMMARY:Old task, edited\r\nLAST-MODIFIED:20240204T090000Z\r\nEND:VTODO\r\nEND:VCALENDAR",
+	).Replace(buf.String())
+
+	report, err := transfer.ImportICal(ctx, strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Updated)
+
+	tasks, err := storage.ExportTasks(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Water the garden", tasks[0].Title)
+	assert.Equal(t, &projectID, tasks[0].ProjectID)
+	assert.Equal(t, recurrence, tasks[0].Recurrence)
+	if assert.Len(t, tasks[0].Reminders, 1) {
+		assert.Equal(t, 30, tasks[0].Reminders[0].BeforeMinutes)
+	}
+	assert.True(t, dueDate.Equal(*tasks[0].DueDate))
+	assert.Equal(t, "Old task, edited", tasks[1].Title)
+	assert.NotNil(t, tasks[1].DeletedAt, "a task in the trash stays there")
+}
+
+func TestTransfer_ImportICal_Invalid(t *testing.T) {
+	suite := newTransferSuite(t)
+
+	suite.mockTransferProvider.On("ExportTasks", mock.Anything).Return(nil, nil)
+
+	_, err := suite.transferService.ImportICal(context.Background(), strings.NewReader("BEGIN:VTODO\r\nSUMMARY:ok\r\nEND:VTODO\r\n"))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+}
@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+const markdownDateLayout = "2006-01-02"
+
+var (
+	// markdownItem matches a checklist item, capturing its indentation, its box and its text.
+	markdownItem = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	// markdownAttribute matches the due date and the priority written after the title, e.g. (due: 2024-03-01).
+	markdownAttribute = regexp.MustCompile(`\((due|priority):\s*([^)]*)\)`)
+	// markdownTag matches the tags written as #tag, references such as #123 are left in the title.
+	markdownTag = regexp.MustCompile(`(^|\s)#(\pL[^\s#]*)`)
+)
+
+// formatMarkdownItem writes the task as a checklist item, the description is left out.
+func formatMarkdownItem(task domain.Task, indent int) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", indent))
+	if task.Status == domain.TaskStatusDone {
+		b.WriteString("- [x] ")
+	} else {
+		b.WriteString("- [ ] ")
+	}
+	b.WriteString(strings.Join(strings.Fields(task.Title), " "))
+	if task.DueDate != nil {
+		b.WriteString(" (due: " + task.DueDate.Local().Format(markdownDateLayout) + ")")
+	}
+	if task.Priority != "" && task.Priority != domain.TaskPriorityNone {
+		b.WriteString(" (priority: " + string(task.Priority) + ")")
+	}
+	for _, tag := range task.Tags {
+		b.WriteString(" #" + strings.Join(strings.Fields(tag), "-"))
+	}
+	return b.String()
+}
+
+// parseMarkdownItem reads the task of a checklist item text, the box is read by the caller.
+func parseMarkdownItem(text string) (domain.Task, error) {
+	task := domain.Task{
+		ID:       uuid.NewString(),
+		Status:   domain.TaskStatusTodo,
+		Priority: domain.TaskPriorityNone,
+	}
+
+	for _, match := range markdownAttribute.FindAllStringSubmatch(text, -1) {
+		value := strings.TrimSpace(match[2])
+		switch match[1] {
+		case "due":
+			dueDate, err := time.ParseInLocation(markdownDateLayout, value, time.Local)
+			if err != nil {
+				return domain.Task{}, fmt.Errorf("due: must be a date like %s", markdownDateLayout)
+			}
+			task.DueDate = &dueDate
+		case "priority":
+			task.Priority = domain.TaskPriority(strings.ToLower(value))
+		}
+	}
+	text = markdownAttribute.ReplaceAllString(text, "")
+
+	for _, match := range markdownTag.FindAllStringSubmatch(text, -1) {
+		task.Tags = append(task.Tags, match[2])
+	}
+	text = markdownTag.ReplaceAllString(text, "$1")
+	task.Title = strings.Join(strings.Fields(text), " ")
+
+	err := validation.Validate(task.Priority, validation.In(
+		domain.TaskPriorityNone,
+		domain.TaskPriorityLow,
+		domain.TaskPriorityMedium,
+		domain.TaskPriorityHigh,
+	).Error("must be none, low, medium or high"))
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("priority: %w", err)
+	}
+	if err := validation.Validate(task.Title, validation.Required, validation.By(validateTitle)); err != nil {
+		return domain.Task{}, fmt.Errorf("title: %w", err)
+	}
+	if err := validateTags([]string(task.Tags)); err != nil {
+		return domain.Task{}, fmt.Errorf("tags: %w", err)
+	}
+
+	return task, nil
+}
+
+// ExportMarkdown writes the tasks outside the trash as a Markdown checklist with a section for each group.
+func (t Transfer) ExportMarkdown(ctx context.Context, w io.Writer, grouping domain.MarkdownGrouping) error {
+	const op = "service.transfer.export_markdown"
+
+	if grouping == "" {
+		grouping = domain.MarkdownGroupNone
+	}
+	err := validation.Validate(grouping, validation.In(domain.MarkdownGroupNone, domain.MarkdownGroupTag, domain.MarkdownGroupStatus))
+	if err != nil {
+		return invalidField("grouping", err)
+	}
+
+	tasks, err := t.liveTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	var lines []string
+	switch grouping {
+	case domain.MarkdownGroupNone:
+		lines = markdownTree(tasks)
+	case domain.MarkdownGroupStatus:
+		var todo, done []string
+		for _, task := range tasks {
+			if task.Status == domain.TaskStatusDone {
+				done = append(done, formatMarkdownItem(task, 0))
+			} else {
+				todo = append(todo, formatMarkdownItem(task, 0))
+			}
+		}
+		lines = appendMarkdownSection(lines, "To do", todo)
+		lines = appendMarkdownSection(lines, "Done", done)
+	case domain.MarkdownGroupTag:
+		byTag := make(map[string][]string)
+		var untagged []string
+		for _, task := range tasks {
+			if len(task.Tags) == 0 {
+				untagged = append(untagged, formatMarkdownItem(task, 0))
+			}
+			for _, tag := range task.Tags {
+				byTag[tag] = append(byTag[tag], formatMarkdownItem(task, 0))
+			}
+		}
+		tags := make([]string, 0, len(byTag))
+		for tag := range byTag {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			lines = appendMarkdownSection(lines, tag, byTag[tag])
+		}
+		lines = appendMarkdownSection(lines, "Untagged", untagged)
+	}
+
+	writer := bufio.NewWriter(w)
+	for _, line := range lines {
+		writer.WriteString(line + "\n")
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// markdownTree lists the tasks with the subtasks indented under their parent in their order.
+func markdownTree(tasks []domain.Task) []string {
+	live := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		live[task.ID] = true
+	}
+
+	var roots []domain.Task
+	children := make(map[string][]domain.Task)
+	for _, task := range tasks {
+		if task.ParentID != nil && live[*task.ParentID] {
+			children[*task.ParentID] = append(children[*task.ParentID], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	var lines []string
+	var walk func(tasks []domain.Task, depth int)
+	walk = func(tasks []domain.Task, depth int) {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Position < tasks[j].Position })
+		for _, task := range tasks {
+			lines = append(lines, formatMarkdownItem(task, depth))
+			walk(children[task.ID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	return lines
+}
+
+func appendMarkdownSection(lines []string, heading string, items []string) []string {
+	if len(items) == 0 {
+		return lines
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "## "+heading, "")
+	return append(lines, items...)
+}
+
+// ImportMarkdown creates a task for every checklist item in the document, other lines are ignored.
+// Items indented under another item become its subtasks.
+func (t Transfer) ImportMarkdown(ctx context.Context, r io.Reader) (domain.ImportReport, error) {
+	const op = "service.transfer.import_markdown"
+
+	type parent struct {
+		indent   int
+		id       string
+		children int
+	}
+
+	now := time.Now()
+	var tasks []domain.Task
+	var roots int
+	var parents []parent // the chain of items the next item may be nested in
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		match := markdownItem.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		task, err := parseMarkdownItem(match[3])
+		if err != nil {
+			return domain.ImportReport{}, domain.NewValidationError("document", domain.ValidationInvalid, fmt.Sprintf("line %d: %s", number, err))
+		}
+		if match[2] != " " {
+			task.Status = domain.TaskStatusDone
+		}
+		task.CreatedAt = now
+		task.ModifiedAt = now
+
+		indent := len(strings.ReplaceAll(match[1], "\t", "    "))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) > 0 {
+			p := &parents[len(parents)-1]
+			parentID := p.id
+			task.ParentID = &parentID
+			task.Position = p.children
+			p.children++
+		} else {
+			task.Position = roots
+			roots++
+		}
+		parents = append(parents, parent{indent: indent, id: task.ID})
+
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return domain.ImportReport{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(tasks) == 0 {
+		return domain.ImportReport{}, nil
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	if err := t.modifier.ImportData(importCtx, nil, tasks, false); err != nil {
+		return domain.ImportReport{}, handleError(op, err)
+	}
+
+	return domain.ImportReport{Created: len(tasks)}, nil
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransfer_ExportMarkdown(t *testing.T) {
+	dueDate := time.Date(2024, 3, 1, 15, 0, 0, 0, time.Local)
+	deletedAt := time.Now()
+	parentID := "1"
+	tasks := []domain.Task{
+		{ID: "1", Title: "Write the report", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityHigh, DueDate: &dueDate, Tags: domain.StringArray{"work"}},
+		{ID: "2", ParentID: &parentID, Position: 1, Title: "Send it", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+		{ID: "3", ParentID: &parentID, Position: 0, Title: "Draft it", Status: domain.TaskStatusDone, Priority: domain.TaskPriorityNone},
+		{ID: "4", Title: "Pay the rent", Status: domain.TaskStatusDone, Priority: domain.TaskPriorityLow, Tags: domain.StringArray{"home", "work"}},
+		{ID: "5", Title: "Deleted", Status: domain.TaskStatusTodo, DeletedAt: &deletedAt},
+	}
+
+	tests := []struct {
+		name     string
+		grouping domain.MarkdownGrouping
+		want     string
+	}{
+		{
+			name:     "nested",
+			grouping: domain.MarkdownGroupNone,
+			want: "- [ ] Write the report (due: 2024-03-01) (priority: high) #work\n" +
+				"  - [x] Draft it\n" +
+				"  - [ ] Send it\n" +
+				"- [x] Pay the rent (priority: low) #home #work\n",
+		},
+		{
+			name:     "by status",
+			grouping: domain.MarkdownGroupStatus,
+			want: "## To do\n\n" +
+				"- [ ] Write the report (due: 2024-03-01) (priority: high) #work\n" +
+				"- [ ] Send it\n\n" +
+				"## Done\n\n" +
+				"- [x] Draft it\n" +
+				"- [x] Pay the rent (priority: low) #home #work\n",
+		},
+		{
+			name:     "by tag",
+			grouping: domain.MarkdownGroupTag,
+			want: "## home\n\n" +
+				"- [x] Pay the rent (priority: low) #home #work\n\n" +
+				"## work\n\n" +
+				"- [ ] Write the report (due: 2024-03-01) (priority: high) #work\n" +
+				"- [x] Pay the rent (priority: low) #home #work\n\n" +
+				"## Untagged\n\n" +
+				"- [ ] Send it\n" +
+				"- [x] Draft it\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := newTransferSuite(t)
+			ctx := context.Background()
+
+			exported := make([]domain.Task, len(tasks))
+			copy(exported, tasks)
+			suite.mockTransferProvider.On("ExportTasks", ctx).Return(exported, nil)
+
+			var buf bytes.Buffer
+			err := suite.transferService.ExportMarkdown(ctx, &buf, tt.grouping)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	t.Run("unknown grouping", func(t *testing.T) {
+		suite := newTransferSuite(t)
+
+		err := suite.transferService.ExportMarkdown(context.Background(), &bytes.Buffer{}, "project")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
+
+func TestTransfer_ImportMarkdown(t *testing.T) {
+	t.Run("checklist items", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+		document := "## Meeting notes\n\n" +
+			"Some text that is not a task.\n" +
+			"- [ ] Write the report (due: 2030-03-01) (priority: high) #work\n" +
+			"  - [x] Draft it\n" +
+			"  - [ ] Send it\n" +
+			"* [X] Fix the login bug from #123\n" +
+			"- a plain list item\n"
+
+		var imported []domain.Task
+		suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.Anything, false).
+			Run(func(args mock.Arguments) { imported = args.Get(2).([]domain.Task) }).
+			Return(nil)
+
+		report, err := suite.transferService.ImportMarkdown(ctx, strings.NewReader(document))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ImportReport{Created: 4}, report)
+		if assert.Len(t, imported, 4) {
+			assert.Equal(t, "Write the report", imported[0].Title)
+			assert.Equal(t, domain.TaskPriorityHigh, imported[0].Priority)
+			assert.Equal(t, time.Date(2030, 3, 1, 0, 0, 0, 0, time.Local), *imported[0].DueDate)
+			assert.Equal(t, domain.StringArray{"work"}, imported[0].Tags)
+			assert.Nil(t, imported[0].ParentID)
+
+			assert.Equal(t, domain.TaskStatusDone, imported[1].Status)
+			assert.Equal(t, imported[0].ID, *imported[1].ParentID)
+			assert.Equal(t, 0, imported[1].Position)
+			assert.Equal(t, imported[0].ID, *imported[2].ParentID)
+			assert.Equal(t, 1, imported[2].Position)
+
+			assert.Equal(t, "Fix the login bug from #123", imported[3].Title)
+			assert.Equal(t, domain.TaskStatusDone, imported[3].Status)
+			assert.Nil(t, imported[3].ParentID)
+			assert.Equal(t, 1, imported[3].Position)
+		}
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		suite := newTransferSuite(t)
+
+		_, err := suite.transferService.ImportMarkdown(context.Background(), strings.NewReader("- [ ] Write the report\n- [ ] Plan (priority: urgent)\n"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		suite := newTransferSuite(t)
+
+		report, err := suite.transferService.ImportMarkdown(context.Background(), strings.NewReader("# Notes\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ImportReport{}, report)
+	})
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestTask_MoveTask_Positions(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+
+	create := func(parentID, title string) domain.Task {
+		request := domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone}
+		if parentID == "" {
+			task, err := tasks.Create(ctx, request)
+			require.NoError(t, err)
+			return task
+		}
+		task, err := tasks.CreateSubtask(ctx, parentID, request)
+		require.NoError(t, err)
+		return task
+	}
+	children := func(parentID string) map[string]int {
+		tasks, err := tasks.GetChildren(ctx, parentID)
+		require.NoError(t, err)
+		positions := make(map[string]int)
+		for _, task := range tasks {
+			positions[task.Title] = task.Position
+		}
+		return positions
+	}
+
+	list, other := create("", "Groceries"), create("", "Errands")
+	milk := create(list.ID, "Milk")
+	create(list.ID, "Bread")
+	create(list.ID, "Eggs")
+	trashed := create(list.ID, "Butter")
+	require.NoError(t, tasks.Delete(ctx, trashed.ID))
+
+	_, err := tasks.MoveTask(ctx, domain.MoveTaskRequest{ID: milk.ID, ParentID: &other.ID, Position: 0})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Bread": 0, "Eggs": 1}, children(list.ID), "the gap is closed")
+	assert.Equal(t, map[string]int{"Milk": 0}, children(other.ID))
+
+	_, err = tasks.MoveTask(ctx, domain.MoveTaskRequest{ID: milk.ID, ParentID: &list.ID, Position: 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"Bread": 0, "Milk": 1, "Eggs": 2}, children(list.ID))
+
+	moved, err := tasks.MoveTask(ctx, domain.MoveTaskRequest{ID: milk.ID, ParentID: &list.ID, Position: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position, "moving to the same place changes nothing")
+
+	trash, err := tasks.GetTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.Equal(t, 3, trash[0].Position, "tasks in the trash are not shifted")
+
+	history, err := tasks.GetTaskHistory(ctx, milk.ID)
+	require.NoError(t, err)
+	last := history[len(history)-1]
+	assert.Equal(t, domain.TaskEventUpdated, last.Action)
+	assert.Equal(t, []domain.TaskFieldChange{
+		{Field: "parent_id", Old: other.ID, New: list.ID},
+		{Field: "position", Old: "0", New: "1"},
+	}, last.Changes)
+}
+
+func TestTask_MoveTask_PastLastSibling(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+
+	list, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Groceries"})
+	require.NoError(t, err)
+	milk, err := tasks.CreateSubtask(ctx, list.ID, domain.CreateTaskRequest{Title: "Milk"})
+	require.NoError(t, err)
+	_, err = tasks.CreateSubtask(ctx, list.ID, domain.CreateTaskRequest{Title: "Bread"})
+	require.NoError(t, err)
+
+	moved, err := tasks.MoveTask(ctx, domain.MoveTaskRequest{ID: milk.ID, ParentID: &list.ID, Position: 99})
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+
+	eggs, err := tasks.CreateSubtask(ctx, list.ID, domain.CreateTaskRequest{Title: "Eggs"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, eggs.Position, "no gap is left after the last sibling")
+}
+
+func TestTask_MoveTask_TakesProjectOfParent(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	tasks := NewTask(storage, storage, storage)
+
+	project, err := NewProject(storage, storage).Create(ctx, domain.CreateProjectRequest{Name: "Home"})
+	require.NoError(t, err)
+	parent, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Clean up", ProjectID: &project.ID})
+	require.NoError(t, err)
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Kitchen"})
+	require.NoError(t, err)
+	subtask, err := tasks.CreateSubtask(ctx, task.ID, domain.CreateTaskRequest{Title: "Dishes"})
+	require.NoError(t, err)
+
+	moved, err := tasks.MoveTask(ctx, domain.MoveTaskRequest{ID: task.ID, ParentID: &parent.ID})
+	require.NoError(t, err)
+	if assert.NotNil(t, moved.ProjectID) {
+		assert.Equal(t, project.ID, *moved.ProjectID)
+	}
+
+	subtask, err = tasks.GetByID(ctx, subtask.ID)
+	require.NoError(t, err)
+	if assert.NotNil(t, subtask.ProjectID, "subtasks move along") {
+		assert.Equal(t, project.ID, *subtask.ProjectID)
+	}
+	assert.Equal(t, 2, subtask.Version)
+}
+
+func TestTask_CreateSubtask_AfterTrashedSiblings(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+	create := func(parentID, title string) domain.Task {
+		task, err := tasks.CreateSubtask(ctx, parentID, domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone})
+		require.NoError(t, err)
+		return task
+	}
+
+	list, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Groceries", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+	assert.Equal(t, 0, create(list.ID, "Milk").Position)
+	bread := create(list.ID, "Bread")
+	require.NoError(t, tasks.Delete(ctx, bread.ID))
+
+	eggs := create(list.ID, "Eggs")
+	assert.Equal(t, 2, eggs.Position, "the position of the subtask in the trash is kept free")
+
+	_, err = tasks.Restore(ctx, bread.ID)
+	require.NoError(t, err)
+	children, err := tasks.GetChildren(ctx, list.ID)
+	require.NoError(t, err)
+	positions := make(map[string]int)
+	for _, child := range children {
+		positions[child.Title] = child.Position
+	}
+	assert.Equal(t, map[string]int{"Milk": 0, "Bread": 1, "Eggs": 2}, positions)
+}
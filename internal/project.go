@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+type Project struct {
+	provider ProjectProvider
+	modifier ProjectModifier
+}
+
+//go:generate mockery --name ProjectProvider
+type ProjectProvider interface {
+	GetAllProjects(ctx context.Context) ([]domain.Project, error)
+	GetProjectByID(ctx context.Context, id string) (domain.Project, error)
+}
+
+//go:generate mockery --name ProjectModifier
+type ProjectModifier interface {
+	CreateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error)
+	DeleteProject(ctx context.Context, id string, deleteTasks bool) error
+}
+
+func NewProject(provider ProjectProvider, modifier ProjectModifier) Project {
+	return Project{
+		provider: provider,
+		modifier: modifier,
+	}
+}
+
+func (p Project) GetAll(ctx context.Context) ([]domain.Project, error) {
+	const op = "service.project.get_all"
+
+	projects, err := p.provider.GetAllProjects(ctx)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return projects, nil
+}
+
+func (p Project) GetByID(ctx context.Context, id string) (domain.Project, error) {
+	const op = "service.project.get_by_id"
+
+	project, err := p.provider.GetProjectByID(ctx, id)
+	if err != nil {
+		return domain.Project{}, handleError(op, err)
+	}
+
+	return project, nil
+}
+
+func (p Project) Create(ctx context.Context, request domain.CreateProjectRequest) (domain.Project, error) {
+	const op = "service.project.create"
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Name, validation.Required, validation.By(validateProjectName)),
+		validation.Field(&request.Color, validation.By(validateColor)),
+	)
+	if err != nil {
+		return domain.Project{}, invalidArguments(err)
+	}
+
+	projects, err := p.provider.GetAllProjects(ctx)
+	if err != nil {
+		return domain.Project{}, handleError(op, err)
+	}
+
+	project := domain.Project{
+		ID:         uuid.NewString(),
+		Name:       strings.Trim(request.Name, " "),
+		Color:      request.Color,
+		SortOrder:  len(projects),
+		CreatedAt:  time.Now(),
+		ModifiedAt: time.Now(),
+	}
+
+	project, err = p.modifier.CreateProject(ctx, project)
+	if err != nil {
+		return domain.Project{}, handleError(op, err)
+	}
+
+	return project, nil
+}
+
+func (p Project) Update(ctx context.Context, request domain.UpdateProjectRequest) (domain.Project, error) {
+	const op = "service.project.update"
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.ID, validation.Required),
+		validation.Field(&request.Name, validation.By(validateProjectName)),
+		validation.Field(&request.Color, validation.By(validateColor)),
+		validation.Field(&request.SortOrder, validation.Min(0)),
+	)
+	if err != nil {
+		return domain.Project{}, invalidArguments(err)
+	}
+
+	project, err := p.provider.GetProjectByID(ctx, request.ID)
+	if err != nil {
+		return domain.Project{}, handleError(op, err)
+	}
+
+	if request.Name != "" {
+		project.Name = strings.Trim(request.Name, " ")
+	}
+	if request.Color != "" {
+		project.Color = request.Color
+	}
+	if request.Archived != nil {
+		project.Archived = *request.Archived
+	}
+	if request.SortOrder != nil {
+		project.SortOrder = *request.SortOrder
+	}
+
+	project.ModifiedAt = time.Now()
+
+	project, err = p.modifier.UpdateProject(ctx, project)
+	if err != nil {
+		return domain.Project{}, handleError(op, err)
+	}
+
+	return project, nil
+}
+
+// Delete deletes the project, its tasks are either moved to the inbox or deleted according to mode.
+func (p Project) Delete(ctx context.Context, id string, mode domain.ProjectDeleteMode) error {
+	const op = "service.project.delete"
+	err := validation.Validate(mode,
+		validation.Required,
+		validation.In(domain.ProjectDeleteMoveToInbox, domain.ProjectDeleteTasks).Error("must be move_to_inbox or delete_tasks"),
+	)
+	if err != nil {
+		return invalidField("mode", err)
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = p.modifier.DeleteProject(deleteCtx, id, mode == domain.ProjectDeleteTasks)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	return nil
+}
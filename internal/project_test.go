@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+type ProjectSuite struct {
+	mockProjectProvider *mocks.ProjectProvider
+	mockProjectModifier *mocks.ProjectModifier
+	projectService      Project
+}
+
+func newProjectSuite(t *testing.T) *ProjectSuite {
+	mockProjectProvider := mocks.NewProjectProvider(t)
+	mockProjectModifier := mocks.NewProjectModifier(t)
+	return &ProjectSuite{
+		mockProjectProvider: mockProjectProvider,
+		mockProjectModifier: mockProjectModifier,
+		projectService:      NewProject(mockProjectProvider, mockProjectModifier),
+	}
+}
+
+func TestProject_Create(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		suite := newProjectSuite(t)
+		ctx := context.Background()
+
+		suite.mockProjectProvider.On("GetAllProjects", ctx).Return([]domain.Project{{ID: "work"}}, nil)
+		suite.mockProjectModifier.On("CreateProject", ctx, mock.AnythingOfType("domain.Project")).
+			Return(func(_ context.Context, project domain.Project) (domain.Project, error) { return project, nil })
+
+		project, err := suite.projectService.Create(ctx, domain.CreateProjectRequest{Name: " Personal ", Color: "#22c55e"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, project.ID)
+		assert.Equal(t, "Personal", project.Name)
+		assert.Equal(t, 1, project.SortOrder)
+	})
+
+	t.Run("invalid color", func(t *testing.T) {
+		suite := newProjectSuite(t)
+
+		_, err := suite.projectService.Create(context.Background(), domain.CreateProjectRequest{Name: "Personal", Color: "green"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockProjectModifier.AssertNotCalled(t, "CreateProject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		suite := newProjectSuite(t)
+
+		_, err := suite.projectService.Create(context.Background(), domain.CreateProjectRequest{Name: ""})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
+
+func TestProject_Update(t *testing.T) {
+	suite := newProjectSuite(t)
+	ctx := context.Background()
+	archived := true
+
+	suite.mockProjectProvider.On("GetProjectByID", ctx, "work").Return(domain.Project{ID: "work", Name: "Work", Color: "#ef4444"}, nil)
+	suite.mockProjectModifier.On("UpdateProject", ctx, mock.AnythingOfType("domain.Project")).
+		Return(func(_ context.Context, project domain.Project) (domain.Project, error) { return project, nil })
+
+	project, err := suite.projectService.Update(ctx, domain.UpdateProjectRequest{ID: "work", Archived: &archived})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Work", project.Name)
+	assert.Equal(t, "#ef4444", project.Color)
+	assert.True(t, project.Archived)
+}
+
+func TestProject_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        domain.ProjectDeleteMode
+		deleteTasks bool
+	}{
+		{"move tasks to inbox", domain.ProjectDeleteMoveToInbox, false},
+		{"delete tasks", domain.ProjectDeleteTasks, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := newProjectSuite(t)
+
+			suite.mockProjectModifier.On("DeleteProject", mock.Anything, "work", tt.deleteTasks).Return(nil)
+
+			err := suite.projectService.Delete(context.Background(), "work", tt.mode)
+
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("invalid mode", func(t *testing.T) {
+		suite := newProjectSuite(t)
+
+		err := suite.projectService.Delete(context.Background(), "work", "archive")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockProjectModifier.AssertNotCalled(t, "DeleteProject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		suite := newProjectSuite(t)
+
+		suite.mockProjectModifier.On("DeleteProject", mock.Anything, "work", false).Return(domain.ErrProjectNotFound)
+
+		err := suite.projectService.Delete(context.Background(), "work", domain.ProjectDeleteMoveToInbox)
+
+		assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+	})
+}
+
+func TestProject_Delete_ChangesTaskVersions(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	tasks, projects := NewTask(storage, storage, storage), NewProject(storage, storage)
+
+	project, err := projects.Create(ctx, domain.CreateProjectRequest{Name: "Work"})
+	require.NoError(t, err)
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Write report", ProjectID: &project.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, projects.Delete(ctx, project.ID, domain.ProjectDeleteMoveToInbox))
+
+	_, err = tasks.Update(ctx, domain.UpdateTaskRequest{ID: task.ID, Version: task.Version, ProjectID: &project.ID})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	history, err := tasks.GetTaskHistory(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, []domain.TaskFieldChange{{Field: "project_id", Old: project.ID, New: ""}}, history[1].Changes)
+}
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTask_List_Pages(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+
+	day := time.Now().AddDate(0, 0, 7).Truncate(time.Hour)
+	due := func(days int) *time.Time {
+		date := day.AddDate(0, 0, days)
+		return &date
+	}
+	requests := []domain.CreateTaskRequest{
+		{Title: "Buy milk", Priority: domain.TaskPriorityHigh, DueDate: due(2)},
+		{Title: "buy milk", Priority: domain.TaskPriorityHigh},
+		{Title: "Call mom", Priority: domain.TaskPriorityLow, DueDate: due(1)},
+		{Title: "Write report", Priority: domain.TaskPriorityMedium, DueDate: due(2)},
+		{Title: "Pay rent", Priority: domain.TaskPriorityNone},
+		{Title: "Water plants", Priority: domain.TaskPriorityMedium, DueDate: due(-1)},
+		{Title: "Book flights", Priority: domain.TaskPriorityNone},
+	}
+	parent, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Plan trip", Priority: domain.TaskPriorityLow, DueDate: due(2)})
+	require.NoError(t, err)
+	for i, request := range requests {
+		if i%2 == 0 {
+			_, err = tasks.CreateSubtask(ctx, parent.ID, request)
+		} else {
+			_, err = tasks.Create(ctx, request)
+		}
+		require.NoError(t, err)
+	}
+	// the modification times differ from the creation times
+	_, err = tasks.Update(ctx, domain.UpdateTaskRequest{ID: parent.ID, Version: parent.Version, Title: "Plan the trip"})
+	require.NoError(t, err)
+
+	// sortValues formats the sort value of a task so the strings order like the values
+	sortValues := map[domain.TaskSortKey]func(domain.Task) string{
+		domain.TaskSortCreatedAt:  func(task domain.Task) string { return fmt.Sprintf("%020d", task.CreatedAt.UnixMicro()) },
+		domain.TaskSortModifiedAt: func(task domain.Task) string { return fmt.Sprintf("%020d", task.ModifiedAt.UnixMicro()) },
+		domain.TaskSortDueDate: func(task domain.Task) string {
+			if task.DueDate == nil {
+				return "~" // after every date
+			}
+			return fmt.Sprintf("%020d", task.DueDate.UnixMicro())
+		},
+		domain.TaskSortPriority: func(task domain.Task) string {
+			return map[domain.TaskPriority]string{"none": "0", "low": "1", "medium": "2", "high": "3"}[task.Priority]
+		},
+		domain.TaskSortTitle:    func(task domain.Task) string { return strings.ToLower(task.Title) },
+		domain.TaskSortPosition: func(task domain.Task) string { return fmt.Sprintf("%05d", task.Position) },
+	}
+
+	for sortBy, sortValue := range sortValues {
+		for _, direction := range []domain.SortDirection{domain.SortAsc, domain.SortDesc} {
+			t.Run(fmt.Sprintf("%s %s", sortBy, direction), func(t *testing.T) {
+				query := domain.TaskQuery{IncludeSubtasks: true, SortBy: sortBy, SortDirection: direction, Limit: 3}
+
+				var listed []domain.Task
+				for pages := 0; ; pages++ {
+					require.Less(t, pages, 10, "the pages do not end")
+					page, err := tasks.List(ctx, query)
+					require.NoError(t, err)
+					listed = append(listed, page.Tasks...)
+					if page.NextCursor == "" {
+						break
+					}
+					require.Len(t, page.Tasks, query.Limit)
+					query.Cursor = page.NextCursor
+				}
+
+				seen := make(map[string]bool)
+				for i, task := range listed {
+					assert.False(t, seen[task.ID], "%s is listed twice", task.Title)
+					seen[task.ID] = true
+					if i == 0 {
+						continue
+					}
+					previous, current := sortValue(listed[i-1]), sortValue(task)
+					if direction == domain.SortAsc {
+						assert.LessOrEqual(t, previous, current, "%s before %s", listed[i-1].Title, task.Title)
+					} else {
+						assert.GreaterOrEqual(t, previous, current, "%s before %s", listed[i-1].Title, task.Title)
+					}
+				}
+				assert.Len(t, seen, len(requests)+1, "every task is listed")
+			})
+		}
+	}
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24"} {
+			_, err := tasks.List(ctx, domain.TaskQuery{Cursor: cursor})
+			assert.ErrorIs(t, err, domain.ErrInvalidArguments, cursor)
+		}
+	})
+}
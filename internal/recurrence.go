@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// nextOccurrence returns the due date of the occurrence that follows a recurring task completed at completedAt.
+// Calendar based rules are anchored to the due date of the task (or the completion time when it has none)
+// and skip occurrences that are already in the past, so completing an overdue task does not create another overdue one.
+func nextOccurrence(rule domain.Recurrence, dueDate *time.Time, completedAt time.Time) time.Time {
+	if rule.Frequency == domain.RecurrenceAfterCompletion {
+		next := completedAt
+		if dueDate != nil {
+			next = time.Date(completedAt.Year(), completedAt.Month(), completedAt.Day(),
+				dueDate.Hour(), dueDate.Minute(), dueDate.Second(), 0, dueDate.Location())
+		}
+		return next.AddDate(0, 0, rule.Every())
+	}
+
+	anchor := completedAt
+	if dueDate != nil {
+		anchor = *dueDate
+	}
+
+	next := anchor
+	for {
+		next = stepOccurrence(rule, anchor, next)
+		if next.After(completedAt) {
+			return next
+		}
+	}
+}
+
+// stepOccurrence returns the first occurrence of rule after current.
+func stepOccurrence(rule domain.Recurrence, anchor, current time.Time) time.Time {
+	switch rule.Frequency {
+	case domain.RecurrenceWeekly:
+		weekdays := rule.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{anchor.Weekday()}
+		}
+		anchorWeek := startOfWeek(anchor)
+		for day := current.AddDate(0, 0, 1); ; day = day.AddDate(0, 0, 1) {
+			weeks := int(startOfWeek(day).Sub(anchorWeek).Hours()/24+0.5) / 7
+			if weeks%rule.Every() == 0 && containsWeekday(weekdays, day.Weekday()) {
+				return day
+			}
+		}
+	case domain.RecurrenceMonthly:
+		monthDay := rule.MonthDay
+		if monthDay == 0 {
+			monthDay = anchor.Day()
+		}
+		// the first day of the target month avoids time.Date normalizing e.g. January 31st + 1 month into March
+		month := time.Date(current.Year(), current.Month()+time.Month(rule.Every()), 1,
+			anchor.Hour(), anchor.Minute(), anchor.Second(), 0, anchor.Location())
+		return month.AddDate(0, 0, min(monthDay, daysInMonth(month))-1)
+	default:
+		return current.AddDate(0, 0, rule.Every())
+	}
+}
+
+// startOfWeek returns midnight of the Monday starting the week of t.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name        string
+		rule        domain.Recurrence
+		dueDate     *time.Time
+		completedAt time.Time
+		expected    time.Time
+	}{
+		{
+			name:        "daily",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceDaily},
+			dueDate:     ptr(date(2024, time.May, 10)),
+			completedAt: date(2024, time.May, 9),
+			expected:    date(2024, time.May, 11),
+		},
+		{
+			name:        "daily skips past occurrences",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceDaily, Interval: 2},
+			dueDate:     ptr(date(2024, time.May, 10)),
+			completedAt: date(2024, time.May, 15),
+			expected:    date(2024, time.May, 16),
+		},
+		{
+			name:        "weekly on due date weekday",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceWeekly},
+			dueDate:     ptr(date(2024, time.May, 10)), // Friday
+			completedAt: date(2024, time.May, 10),
+			expected:    date(2024, time.May, 17),
+		},
+		{
+			name:        "weekly on given weekdays",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceWeekly, Weekdays: []time.Weekday{time.Monday, time.Thursday}},
+			dueDate:     ptr(date(2024, time.May, 13)), // Monday
+			completedAt: date(2024, time.May, 13),
+			expected:    date(2024, time.May, 16),
+		},
+		{
+			name:        "every other week",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceWeekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Thursday}},
+			dueDate:     ptr(date(2024, time.May, 16)), // Thursday
+			completedAt: date(2024, time.May, 16),
+			expected:    date(2024, time.May, 27),
+		},
+		{
+			name:        "monthly by day clamps to month length",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceMonthly, MonthDay: 31},
+			dueDate:     ptr(date(2024, time.January, 31)),
+			completedAt: date(2024, time.January, 31),
+			expected:    date(2024, time.February, 29),
+		},
+		{
+			name:        "monthly keeps the requested day",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceMonthly, MonthDay: 31},
+			dueDate:     ptr(date(2024, time.February, 29)),
+			completedAt: date(2024, time.February, 29),
+			expected:    date(2024, time.March, 31),
+		},
+		{
+			name:        "after completion",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceAfterCompletion, Interval: 3},
+			dueDate:     ptr(date(2024, time.May, 1)),
+			completedAt: time.Date(2024, time.May, 10, 18, 30, 0, 0, time.UTC),
+			expected:    date(2024, time.May, 13),
+		},
+		{
+			name:        "without due date",
+			rule:        domain.Recurrence{Frequency: domain.RecurrenceDaily},
+			completedAt: date(2024, time.May, 10),
+			expected:    date(2024, time.May, 11),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextOccurrence(tt.rule, tt.dueDate, tt.completedAt))
+		})
+	}
+}
+
+func TestRecurrenceString(t *testing.T) {
+	rule := domain.Recurrence{Frequency: domain.RecurrenceWeekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Sunday}}
+
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", rule.String())
+
+	parsed, err := domain.ParseRecurrence(rule.String())
+	assert.NoError(t, err)
+	assert.Equal(t, rule, parsed)
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// maxReminderWait bounds how long the scheduler sleeps, so it notices wall clock jumps, e.g. after the system resumes.
+	maxReminderWait = time.Minute
+	// missedReminderThreshold marks reminders that went off longer ago than this as missed.
+	missedReminderThreshold = time.Minute
+)
+
+//go:generate mockery --name ReminderStore
+type ReminderStore interface {
+	GetDueReminders(ctx context.Context, until time.Time) ([]domain.ReminderNotification, error)
+	GetNextReminderTime(ctx context.Context) (*time.Time, error)
+	MarkReminderFired(ctx context.Context, id string, firedAt time.Time) error
+}
+
+// Notifier delivers reminder notifications to the user.
+type Notifier interface {
+	Notify(ctx context.Context, notification domain.ReminderNotification) error
+}
+
+// Clock tells the time to the scheduler, so tests can control it.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Scheduler fires reminders as they become due. Reminders are kept in the store, so the ones missed
+// while the app was closed fire as soon as the scheduler starts.
+type Scheduler struct {
+	store    ReminderStore
+	notifier Notifier
+	clock    Clock
+	wake     chan struct{}
+}
+
+func NewScheduler(store ReminderStore, notifier Notifier, clock Clock) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		notifier: notifier,
+		clock:    clock,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Run fires due reminders until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	const op = "service.scheduler.run"
+
+	for {
+		wait := maxReminderWait
+		if err := s.FireDue(ctx); err != nil {
+			log.Error(op, err)
+		} else if next, err := s.store.GetNextReminderTime(ctx); err != nil {
+			log.Error(op, err)
+		} else if next != nil {
+			wait = min(wait, max(next.Sub(s.clock.Now()), 0))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-s.clock.After(wait):
+		}
+	}
+}
+
+// Reschedule makes the scheduler look up the next reminder again, it is called after tasks change.
+func (s *Scheduler) Reschedule() {
+	select {
+	case s.wake <- struct{}{}:
+	default: // a wake up is already pending
+	}
+}
+
+// FireDue notifies about every reminder that is due and marks it as fired.
+func (s *Scheduler) FireDue(ctx context.Context) error {
+	const op = "service.scheduler.fire_due"
+
+	now := s.clock.Now()
+	notifications, err := s.store.GetDueReminders(ctx, now)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	for _, notification := range notifications {
+		notification.Missed = now.Sub(notification.FireAt) > missedReminderThreshold
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			return handleError(op, err)
+		}
+		if err := s.store.MarkReminderFired(ctx, notification.ReminderID, now); err != nil {
+			return handleError(op, err)
+		}
+	}
+
+	return nil
+}
+
+// rescheduleReminders assigns IDs to new reminders and keeps a reminder marked as fired
+// only while it goes off at the same time as before, so moving the due date or the reminder rearms it.
+func rescheduleReminders(previous []domain.Reminder, previousDueDate *time.Time, reminders []domain.Reminder, dueDate *time.Time) []domain.Reminder {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	fired := make(map[string]domain.Reminder, len(previous))
+	for _, reminder := range previous {
+		if reminder.FiredAt != nil {
+			fired[reminder.ID] = reminder
+		}
+	}
+
+	result := make([]domain.Reminder, len(reminders))
+	for i, reminder := range reminders {
+		if reminder.ID == "" {
+			reminder.ID = uuid.NewString()
+		}
+		reminder.FiredAt = nil
+
+		if old, ok := fired[reminder.ID]; ok {
+			oldFireAt, oldOk := old.FireAt(previousDueDate)
+			fireAt, ok := reminder.FireAt(dueDate)
+			if oldOk && ok && oldFireAt.Sub(fireAt).Abs() < time.Second {
+				reminder.FiredAt = old.FiredAt
+			}
+		}
+
+		result[i] = reminder
+	}
+
+	return result
+}
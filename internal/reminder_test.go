@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when Advance is called.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			waiters = append(waiters, waiter)
+			continue
+		}
+		waiter.ch <- c.now
+	}
+	c.waiters = waiters
+}
+
+// memoryNotifier keeps the notifications it receives.
+type memoryNotifier struct {
+	mu            sync.Mutex
+	notifications []domain.ReminderNotification
+}
+
+func (n *memoryNotifier) Notify(_ context.Context, notification domain.ReminderNotification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func (n *memoryNotifier) Notifications() []domain.ReminderNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]domain.ReminderNotification(nil), n.notifications...)
+}
+
+func TestScheduler_FireDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(now)
+	notifier := &memoryNotifier{}
+	store := mocks.NewReminderStore(t)
+	scheduler := NewScheduler(store, notifier, clock)
+
+	store.On("GetDueReminders", ctx, now).Return([]domain.ReminderNotification{
+		{ReminderID: "missed", TaskID: "1", Title: "Pay rent", FireAt: now.Add(-12 * time.Hour)},
+		{ReminderID: "due", TaskID: "2", Title: "Call mom", FireAt: now},
+	}, nil)
+	store.On("MarkReminderFired", ctx, "missed", now).Return(nil)
+	store.On("MarkReminderFired", ctx, "due", now).Return(nil)
+
+	err := scheduler.FireDue(ctx)
+
+	assert.NoError(t, err)
+	notifications := notifier.Notifications()
+	if assert.Len(t, notifications, 2) {
+		assert.True(t, notifications[0].Missed)
+		assert.False(t, notifications[1].Missed)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	fireAt := now.Add(10 * time.Minute)
+	clock := newFakeClock(now)
+	notifier := &memoryNotifier{}
+	store := mocks.NewReminderStore(t)
+	scheduler := NewScheduler(store, notifier, clock)
+
+	var mu sync.Mutex
+	fired := false
+	store.On("GetDueReminders", mock.Anything, mock.AnythingOfType("time.Time")).Return(
+		func(_ context.Context, until time.Time) ([]domain.ReminderNotification, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fired || until.Before(fireAt) {
+				return nil, nil
+			}
+			return []domain.ReminderNotification{{ReminderID: "1", TaskID: "1", FireAt: fireAt}}, nil
+		},
+	)
+	store.On("GetNextReminderTime", mock.Anything).Return(
+		func(_ context.Context) (*time.Time, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fired {
+				return nil, nil
+			}
+			return &fireAt, nil
+		},
+	)
+	store.On("MarkReminderFired", mock.Anything, "1", fireAt).Return(
+		func(_ context.Context, _ string, _ time.Time) error {
+			mu.Lock()
+			defer mu.Unlock()
+			fired = true
+			return nil
+		},
+	)
+
+	go scheduler.Run(ctx)
+
+	waitForWaiter(t, clock)
+	assert.Empty(t, notifier.Notifications())
+
+	clock.Advance(10 * time.Minute)
+
+	assert.Eventually(t, func() bool { return len(notifier.Notifications()) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestScheduler_Reschedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(now)
+	store := mocks.NewReminderStore(t)
+	scheduler := NewScheduler(store, &memoryNotifier{}, clock)
+
+	lookups := make(chan struct{}, 10)
+	store.On("GetDueReminders", mock.Anything, now).Return(nil, nil)
+	store.On("GetNextReminderTime", mock.Anything).Return(
+		func(_ context.Context) (*time.Time, error) {
+			lookups <- struct{}{}
+			return nil, nil
+		},
+	)
+
+	go scheduler.Run(ctx)
+	<-lookups
+
+	scheduler.Reschedule()
+
+	select {
+	case <-lookups:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not look up the next reminder after Reschedule")
+	}
+}
+
+// waitForWaiter blocks until the scheduler sleeps on the clock.
+func waitForWaiter(t *testing.T, clock *fakeClock) {
+	assert.Eventually(t, func() bool {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		return len(clock.waiters) > 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestRescheduleReminders(t *testing.T) {
+	dueDate := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	firedAt := dueDate.Add(-time.Hour)
+	at := dueDate.Add(-2 * time.Hour)
+	previous := []domain.Reminder{
+		{ID: "relative", BeforeMinutes: 60, FiredAt: &firedAt},
+		{ID: "absolute", At: &at, FiredAt: &firedAt},
+	}
+
+	t.Run("same due date keeps fired reminders", func(t *testing.T) {
+		reminders := rescheduleReminders(previous, &dueDate, previous, &dueDate)
+
+		assert.NotNil(t, reminders[0].FiredAt)
+		assert.NotNil(t, reminders[1].FiredAt)
+	})
+
+	t.Run("moved due date rearms relative reminders", func(t *testing.T) {
+		later := dueDate.Add(24 * time.Hour)
+
+		reminders := rescheduleReminders(previous, &dueDate, previous, &later)
+
+		assert.Nil(t, reminders[0].FiredAt)
+		assert.NotNil(t, reminders[1].FiredAt)
+	})
+
+	t.Run("new reminders get an id", func(t *testing.T) {
+		reminders := rescheduleReminders(nil, nil, []domain.Reminder{{BeforeMinutes: 15}}, &dueDate)
+
+		assert.NotEmpty(t, reminders[0].ID)
+		assert.Nil(t, reminders[0].FiredAt)
+	})
+}
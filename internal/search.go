@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+var searchFields = map[string]domain.SearchField{
+	"title":       domain.SearchFieldTitle,
+	"description": domain.SearchFieldDescription,
+	"desc":        domain.SearchFieldDescription,
+	"tag":         domain.SearchFieldTags,
+	"tags":        domain.SearchFieldTags,
+}
+
+// parseSearchQuery splits the text typed by the user into search terms. It understands
+//
+//	word      tasks containing the word
+//	word*     tasks containing a word starting with "word"
+//	"a b"     tasks containing the exact phrase
+//	tag:word  the term must match the given field (title, description/desc or tag/tags)
+//
+// Anything else is searched for literally, so the parser never fails on user input.
+func parseSearchQuery(input string) []domain.SearchTerm {
+	var terms []domain.SearchTerm
+	rest := []rune(input)
+
+	for {
+		rest = trimLeftSpace(rest)
+		if len(rest) == 0 {
+			return terms
+		}
+
+		var term domain.SearchTerm
+		if colon := indexInWord(rest, ':'); colon > 0 {
+			if field, ok := searchFields[strings.ToLower(string(rest[:colon]))]; ok {
+				term.Field = field
+				rest = rest[colon+1:]
+			}
+		}
+
+		var text []rune
+		if len(rest) > 0 && rest[0] == '"' {
+			end := indexOf(rest[1:], '"')
+			if end < 0 {
+				text, rest = rest[1:], nil
+			} else {
+				text, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := 0
+			for end < len(rest) && !unicode.IsSpace(rest[end]) {
+				end++
+			}
+			text, rest = rest[:end], rest[end:]
+		}
+
+		if len(rest) > 0 && rest[0] == '*' {
+			term.Prefix = true
+			rest = rest[1:]
+		}
+		if len(text) > 0 && text[len(text)-1] == '*' {
+			term.Prefix = true
+			text = text[:len(text)-1]
+		}
+
+		term.Text = strings.TrimSpace(string(text))
+		if term.Text != "" {
+			terms = append(terms, term)
+		}
+	}
+}
+
+func trimLeftSpace(r []rune) []rune {
+	for len(r) > 0 && unicode.IsSpace(r[0]) {
+		r = r[1:]
+	}
+	return r
+}
+
+// indexInWord returns the index of c in the first word of r, or -1.
+func indexInWord(r []rune, c rune) int {
+	for i, x := range r {
+		if unicode.IsSpace(x) {
+			return -1
+		}
+		if x == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOf(r []rune, c rune) int {
+	for i, x := range r {
+		if x == c {
+			return i
+		}
+	}
+	return -1
+}
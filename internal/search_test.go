@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []domain.SearchTerm
+	}{
+		{"empty", "   ", nil},
+		{"words", "fix  login", []domain.SearchTerm{{Text: "fix"}, {Text: "login"}}},
+		{"prefix", "deplo*", []domain.SearchTerm{{Text: "deplo", Prefix: true}}},
+		{"phrase", `"release notes" draft`, []domain.SearchTerm{{Text: "release notes"}, {Text: "draft"}}},
+		{"phrase prefix", `"release no"*`, []domain.SearchTerm{{Text: "release no", Prefix: true}}},
+		{"unterminated phrase", `"release notes`, []domain.SearchTerm{{Text: "release notes"}}},
+		{"field", "tag:backend Title:login", []domain.SearchTerm{
+			{Field: domain.SearchFieldTags, Text: "backend"},
+			{Field: domain.SearchFieldTitle, Text: "login"},
+		}},
+		{"field phrase", `desc:"stack trace"`, []domain.SearchTerm{{Field: domain.SearchFieldDescription, Text: "stack trace"}}},
+		{"unknown field", "http://example.com", []domain.SearchTerm{{Text: "http://example.com"}}},
+		{"operators are literal", `a OR b NOT c"`, []domain.SearchTerm{{Text: "a"}, {Text: "OR"}, {Text: "b"}, {Text: "NOT"}, {Text: `c"`}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseSearchQuery(tt.query))
+		})
+	}
+}
+
+func TestTask_Search(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		suite.mockTaskProvider.On("Search", ctx, domain.SearchQuery{
+			Terms: []domain.SearchTerm{{Field: domain.SearchFieldTags, Text: "backend"}},
+			Limit: domain.DefaultSearchLimit,
+		}).Return([]domain.SearchResult{{Task: domain.Task{ID: "123"}}}, nil)
+
+		results, err := suite.taskService.Search(ctx, "tag:backend")
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("blank query", func(t *testing.T) {
+		suite := newSuite(t)
+
+		_, err := suite.taskService.Search(context.Background(), " ")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockTaskProvider.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_Search_Storage(t *testing.T) {
+	ctx := context.Background()
+	tasks := newStorageTasks(t)
+	create := func(request domain.CreateTaskRequest) domain.Task {
+		request.Priority = domain.TaskPriorityNone
+		task, err := tasks.Create(ctx, request)
+		require.NoError(t, err)
+		return task
+	}
+	inDescription := create(domain.CreateTaskRequest{Title: "Write docs", Description: "Explain the login flow step by step"})
+	inTags := create(domain.CreateTaskRequest{Title: "Refactor auth", Tags: []string{"login"}})
+	inTitle := create(domain.CreateTaskRequest{Title: "Fix login bug"})
+	trashed := create(domain.CreateTaskRequest{Title: "Login page redesign"})
+	require.NoError(t, tasks.Delete(ctx, trashed.ID))
+
+	ids := func(results []domain.SearchResult) []string {
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.Task.ID)
+		}
+		return ids
+	}
+
+	results, err := tasks.Search(ctx, "login")
+	require.NoError(t, err)
+	assert.Equal(t, []string{inTitle.ID, inTags.ID, inDescription.ID}, ids(results), "title before tags before description, the trash left out")
+	assert.Equal(t, "Fix "+domain.SearchMatchStart+"login"+domain.SearchMatchEnd+" bug", results[0].Title)
+	assert.Empty(t, results[0].Snippet, "the description does not match")
+	assert.Equal(t, "Refactor auth", results[1].Title)
+	assert.Contains(t, results[2].Snippet, domain.SearchMatchStart+"login"+domain.SearchMatchEnd)
+	for i := 1; i < len(results); i++ {
+		assert.LessOrEqual(t, results[i-1].Rank, results[i].Rank)
+	}
+
+	results, err = tasks.Search(ctx, "title:log*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{inTitle.ID}, ids(results))
+
+	results, err = tasks.Search(ctx, `login OR "bug`)
+	require.NoError(t, err)
+	assert.Empty(t, results, "operators are searched for literally")
+
+	_, err = tasks.Restore(ctx, trashed.ID)
+	require.NoError(t, err)
+	results, err = tasks.Search(ctx, "redesign")
+	require.NoError(t, err)
+	assert.Equal(t, []string{trashed.ID}, ids(results), "a restored task is found again")
+}
@@ -16,32 +16,47 @@ import (
 type Task struct {
 	provider TaskProvider
 	modifier TaskModifier
+	history  TaskHistory
 }
 
 //go:generate mockery --name TaskProvider
 type TaskProvider interface {
-	GetAllTasks(ctx context.Context) ([]domain.Task, error)
+	ListTasks(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error)
+	Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchResult, error)
 	GetTaskByID(ctx context.Context, id string) (domain.Task, error)
+	GetChildren(ctx context.Context, parentID string) ([]domain.Task, error)
+	GetTrash(ctx context.Context) ([]domain.Task, error)
+	IsInTrash(ctx context.Context, id string) (bool, error)
+	GetTaskEvents(ctx context.Context, taskID string) ([]domain.TaskEvent, error)
+	GetProjectByID(ctx context.Context, id string) (domain.Project, error)
+	GetSettings(ctx context.Context) (domain.Settings, error)
 }
 
 //go:generate mockery --name TaskModifier
 type TaskModifier interface {
 	CreateTask(ctx context.Context, task domain.Task) (domain.Task, error)
 	UpdateTask(ctx context.Context, task domain.Task) (domain.Task, error)
+	CompleteTask(ctx context.Context, completion domain.TaskCompletion) (domain.TaskCompletion, error)
+	MoveTask(ctx context.Context, id string, parentID *string, position int) (domain.Task, error)
 	DeleteTask(ctx context.Context, id string) error
+	RestoreTask(ctx context.Context, id string) (domain.Task, error)
+	EmptyTrash(ctx context.Context) (int64, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
 }
 
-func NewTask(provider TaskProvider, modifier TaskModifier) Task {
+func NewTask(provider TaskProvider, modifier TaskModifier, history TaskHistory) Task {
 	return Task{
 		provider: provider,
 		modifier: modifier,
+		history:  history,
 	}
 }
 
+// GetAll returns every top-level task.
 func (t Task) GetAll(ctx context.Context) ([]domain.Task, error) {
 	const op = "service.task.get_all"
 
-	tasks, err := t.provider.GetAllTasks(ctx)
+	tasks, err := t.listAll(ctx, domain.TaskQuery{})
 	if err != nil {
 		return nil, handleError(op, err)
 	}
@@ -49,6 +64,87 @@ func (t Task) GetAll(ctx context.Context) ([]domain.Task, error) {
 	return tasks, nil
 }
 
+// GetByProject returns the top-level tasks of the project, an empty projectID returns the tasks in the inbox.
+func (t Task) GetByProject(ctx context.Context, projectID string) ([]domain.Task, error) {
+	const op = "service.task.get_by_project"
+
+	tasks, err := t.listAll(ctx, domain.TaskQuery{ProjectID: &projectID})
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return tasks, nil
+}
+
+// List returns a single page of the tasks matching the query.
+func (t Task) List(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error) {
+	const op = "service.task.list"
+	err := validation.ValidateStruct(&query,
+		validation.Field(&query.Statuses, validation.Each(validation.In(domain.TaskStatusTodo, domain.TaskStatusDone))),
+		validation.Field(&query.Priorities, validation.Each(validation.In(
+			domain.TaskPriorityNone,
+			domain.TaskPriorityLow,
+			domain.TaskPriorityMedium,
+			domain.TaskPriorityHigh,
+		))),
+		validation.Field(&query.SortBy, validation.In(
+			domain.TaskSortCreatedAt,
+			domain.TaskSortModifiedAt,
+			domain.TaskSortDueDate,
+			domain.TaskSortPriority,
+			domain.TaskSortTitle,
+			domain.TaskSortPosition,
+		)),
+		validation.Field(&query.SortDirection, validation.In(domain.SortAsc, domain.SortDesc)),
+		validation.Field(&query.Limit, validation.Min(0), validation.Max(domain.MaxTaskQueryLimit)),
+	)
+	if err != nil {
+		return domain.TaskPage{}, invalidArguments(err)
+	}
+
+	page, err := t.provider.ListTasks(ctx, query)
+	if err != nil {
+		return domain.TaskPage{}, handleError(op, err)
+	}
+
+	return page, nil
+}
+
+// listAll follows the cursor of the query until every matching task is loaded.
+func (t Task) listAll(ctx context.Context, query domain.TaskQuery) ([]domain.Task, error) {
+	query.Limit = domain.MaxTaskQueryLimit
+
+	var tasks []domain.Task
+	for {
+		page, err := t.provider.ListTasks(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Tasks...)
+		if page.NextCursor == "" {
+			return tasks, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// Search finds tasks by words in their title, description or tags, see parseSearchQuery for the query syntax.
+func (t Task) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	const op = "service.task.search"
+
+	terms := parseSearchQuery(query)
+	if len(terms) == 0 {
+		return nil, domain.NewValidationError("query", domain.ValidationRequired, "cannot be blank")
+	}
+
+	results, err := t.provider.Search(ctx, domain.SearchQuery{Terms: terms, Limit: domain.DefaultSearchLimit})
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return results, nil
+}
+
 func (t Task) GetByID(ctx context.Context, id string) (domain.Task, error) {
 	const op = "service.task.get_by_id"
 
@@ -60,14 +156,45 @@ func (t Task) GetByID(ctx context.Context, id string) (domain.Task, error) {
 	return task, nil
 }
 
+// GetTaskHistory returns the audit log of the task, oldest event first.
+// It stays available while the task is in the trash.
+func (t Task) GetTaskHistory(ctx context.Context, id string) ([]domain.TaskEvent, error) {
+	const op = "service.task.get_task_history"
+
+	if id == "" {
+		return nil, domain.NewValidationError("id", domain.ValidationRequired, "cannot be blank")
+	}
+
+	events, err := t.provider.GetTaskEvents(ctx, id)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return events, nil
+}
+
 func (t Task) Create(ctx context.Context, request domain.CreateTaskRequest) (domain.Task, error) {
 	const op = "service.task.create"
 	err := validation.ValidateStruct(&request,
 		validation.Field(&request.Title, validation.Required, validation.By(validateTitle)),
+		validation.Field(&request.Description, validation.By(validateDescription)),
+		validation.Field(&request.Tags, validation.By(validateTags)),
+		validation.Field(&request.Priority, validation.By(validatePriority)),
 		validation.Field(&request.DueDate, validation.By(validateDueDate)),
+		validation.Field(&request.Recurrence, validation.By(validateRecurrence)),
+		validation.Field(&request.Reminders, validation.By(validateReminders)),
 	)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("%w: %w", domain.ErrInvalidArguments, err)
+		return domain.Task{}, invalidArguments(err)
+	}
+
+	if err := t.checkProject(ctx, projectIDOrNil(request.ProjectID)); err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	priority, err := t.priorityOrDefault(ctx, request.Priority)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
 	}
 
 	uid, err := uuid.NewUUID()
@@ -76,13 +203,18 @@ func (t Task) Create(ctx context.Context, request domain.CreateTaskRequest) (dom
 	}
 
 	task := domain.Task{
-		ID:         uid.String(),
-		Title:      strings.Trim(request.Title, " "),
-		Status:     domain.TaskStatusTodo,
-		Priority:   request.Priority,
-		DueDate:    request.DueDate,
-		CreatedAt:  time.Now(),
-		ModifiedAt: time.Now(),
+		ID:          uid.String(),
+		ProjectID:   projectIDOrNil(request.ProjectID),
+		Title:       strings.Trim(request.Title, " "),
+		Description: request.Description,
+		Tags:        request.Tags,
+		Status:      domain.TaskStatusTodo,
+		Priority:    priority,
+		DueDate:     request.DueDate,
+		Recurrence:  recurrenceOrNil(request.Recurrence),
+		Reminders:   rescheduleReminders(nil, nil, request.Reminders, request.DueDate),
+		CreatedAt:   time.Now(),
+		ModifiedAt:  time.Now(),
 	}
 
 	task, err = t.modifier.CreateTask(ctx, task)
@@ -90,6 +222,116 @@ func (t Task) Create(ctx context.Context, request domain.CreateTaskRequest) (dom
 		return domain.Task{}, handleError(op, err)
 	}
 
+	t.record(ctx, domain.HistoryActionCreate, nil, &task)
+
+	return task, nil
+}
+
+// CreateSubtask creates a task under parentID, placing it after the existing subtasks.
+func (t Task) CreateSubtask(ctx context.Context, parentID string, request domain.CreateTaskRequest) (domain.Task, error) {
+	const op = "service.task.create_subtask"
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Title, validation.Required, validation.By(validateTitle)),
+		validation.Field(&request.Description, validation.By(validateDescription)),
+		validation.Field(&request.Tags, validation.By(validateTags)),
+		validation.Field(&request.Priority, validation.By(validatePriority)),
+		validation.Field(&request.DueDate, validation.By(validateDueDate)),
+		validation.Field(&request.Recurrence, validation.By(validateRecurrence)),
+		validation.Field(&request.Reminders, validation.By(validateReminders)),
+	)
+	if err != nil {
+		return domain.Task{}, invalidArguments(err)
+	}
+
+	parent, err := t.provider.GetTaskByID(ctx, parentID)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	priority, err := t.priorityOrDefault(ctx, request.Priority)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	uid, err := uuid.NewUUID()
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	task := domain.Task{
+		ID:          uid.String(),
+		ProjectID:   parent.ProjectID,
+		ParentID:    &parent.ID, // the storage places the subtask after its siblings
+		Title:       strings.Trim(request.Title, " "),
+		Description: request.Description,
+		Tags:        request.Tags,
+		Status:      domain.TaskStatusTodo,
+		Priority:    priority,
+		DueDate:     request.DueDate,
+		Recurrence:  recurrenceOrNil(request.Recurrence),
+		Reminders:   rescheduleReminders(nil, nil, request.Reminders, request.DueDate),
+		CreatedAt:   time.Now(),
+		ModifiedAt:  time.Now(),
+	}
+
+	task, err = t.modifier.CreateTask(ctx, task)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	t.record(ctx, domain.HistoryActionCreate, nil, &task)
+
+	return task, nil
+}
+
+func (t Task) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	const op = "service.task.get_children"
+
+	if _, err := t.provider.GetTaskByID(ctx, parentID); err != nil {
+		return nil, handleError(op, err)
+	}
+
+	tasks, err := t.provider.GetChildren(ctx, parentID)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return tasks, nil
+}
+
+// MoveTask moves the task under a new parent (or to the top level) at the requested position.
+// A task cannot be moved under itself or any of its own subtasks.
+func (t Task) MoveTask(ctx context.Context, request domain.MoveTaskRequest) (domain.Task, error) {
+	const op = "service.task.move"
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.ID, validation.Required),
+		validation.Field(&request.Position, validation.Min(0)),
+	)
+	if err != nil {
+		return domain.Task{}, invalidArguments(err)
+	}
+
+	if _, err := t.provider.GetTaskByID(ctx, request.ID); err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	// walk up from the new parent to make sure the task is not one of its ancestors
+	for ancestorID := request.ParentID; ancestorID != nil; {
+		if *ancestorID == request.ID {
+			return domain.Task{}, domain.NewValidationError("parent_id", domain.ValidationInvalid, "task cannot be moved under itself")
+		}
+		ancestor, err := t.provider.GetTaskByID(ctx, *ancestorID)
+		if err != nil {
+			return domain.Task{}, handleError(op, err)
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	task, err := t.modifier.MoveTask(ctx, request.ID, request.ParentID, request.Position)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
 	return task, nil
 }
 
@@ -97,19 +339,29 @@ func (t Task) Update(ctx context.Context, request domain.UpdateTaskRequest) (dom
 	const op = "service.task.update"
 	err := validation.ValidateStruct(&request,
 		validation.Field(&request.ID, validation.Required),
+		validation.Field(&request.Version, validation.Required, validation.Min(1)),
 		validation.Field(&request.Title, validation.By(validateTitle)),
+		validation.Field(&request.Priority, validation.By(validatePriority)),
 		validation.Field(&request.DueDate, validation.By(validateDueDate)),
+		validation.Field(&request.Recurrence, validation.By(validateRecurrence)),
 		validation.Field(&request.Description, validation.By(validateDescription)),
 		validation.Field(&request.Tags, validation.By(validateTags)),
+		validation.Field(&request.Reminders, validation.By(validateReminders)),
 	)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("%w: %w", domain.ErrInvalidArguments, err)
+		return domain.Task{}, invalidArguments(err)
 	}
 
 	task, err := t.provider.GetTaskByID(ctx, request.ID)
 	if err != nil {
 		return domain.Task{}, handleError(op, err)
 	}
+	if task.Version != request.Version {
+		return domain.Task{}, domain.ErrConflict
+	}
+
+	before := task
+	completing := request.Status == domain.TaskStatusDone && task.Status != domain.TaskStatusDone
 
 	if request.Title != "" {
 		task.Title = strings.Trim(request.Title, " ")
@@ -129,40 +381,215 @@ func (t Task) Update(ctx context.Context, request domain.UpdateTaskRequest) (dom
 	if request.Tags != nil {
 		task.Tags = request.Tags
 	}
+	if request.Recurrence != nil {
+		task.Recurrence = recurrenceOrNil(request.Recurrence)
+	}
+	if request.ProjectID != nil {
+		if err := t.checkProject(ctx, projectIDOrNil(request.ProjectID)); err != nil {
+			return domain.Task{}, handleError(op, err)
+		}
+		task.ProjectID = projectIDOrNil(request.ProjectID)
+	}
+	if request.Reminders != nil {
+		task.Reminders = request.Reminders
+	}
+	task.Reminders = rescheduleReminders(before.Reminders, before.DueDate, task.Reminders, task.DueDate)
+
+	// the rule moves on to the next occurrence, so reopening and completing this one again does not repeat it twice
+	var next *domain.Task
+	if completing && task.Recurrence != nil {
+		occurrence := nextTaskOccurrence(task, time.Now())
+		next = &occurrence
+		task.Recurrence = nil
+	}
 
 	task.ModifiedAt = time.Now()
 
 	updateCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	task, err = t.modifier.UpdateTask(updateCtx, task)
-	if err != nil {
-		return domain.Task{}, handleError(op, err)
+	// the subtasks completed with the task and its next occurrence are undone together with it
+	var effects []domain.HistoryEntry
+	if completing {
+		children, err := t.incompleteChildren(updateCtx, task.ID, request.CompleteChildren)
+		if err != nil {
+			return domain.Task{}, handleError(op, err)
+		}
+
+		completion := domain.TaskCompletion{Task: task, Next: next}
+		for _, child := range children {
+			child.Status = domain.TaskStatusDone
+			child.ModifiedAt = time.Now()
+			completion.Children = append(completion.Children, child)
+		}
+		completion, err = t.modifier.CompleteTask(updateCtx, completion)
+		if err != nil {
+			return domain.Task{}, handleError(op, err)
+		}
+
+		task = completion.Task
+		for i := range children {
+			effects = append(effects, domain.HistoryEntry{Action: domain.HistoryActionUpdate, Before: &children[i], After: &completion.Children[i]})
+		}
+		if completion.Next != nil {
+			effects = append(effects, domain.HistoryEntry{Action: domain.HistoryActionCreate, After: completion.Next})
+		}
+	} else {
+		task, err = t.modifier.UpdateTask(updateCtx, task)
+		if err != nil {
+			return domain.Task{}, handleError(op, err)
+		}
 	}
 
+	t.record(updateCtx, domain.HistoryActionUpdate, &before, &task, effects...)
+
 	return task, nil
 }
 
+// nextTaskOccurrence builds the task that follows a recurring task completed at completedAt.
+func nextTaskOccurrence(task domain.Task, completedAt time.Time) domain.Task {
+	dueDate := nextOccurrence(*task.Recurrence, task.DueDate, completedAt)
+
+	return domain.Task{
+		ID:          uuid.NewString(),
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		Position:    task.Position,
+		Title:       task.Title,
+		Description: task.Description,
+		Tags:        task.Tags,
+		Status:      domain.TaskStatusTodo,
+		Priority:    task.Priority,
+		DueDate:     &dueDate,
+		Recurrence:  task.Recurrence,
+		Reminders:   relativeReminders(task.Reminders),
+		CreatedAt:   completedAt,
+		ModifiedAt:  completedAt,
+	}
+}
+
+// relativeReminders copies the reminders set relative to the due date as new reminders for the next occurrence.
+func relativeReminders(reminders []domain.Reminder) []domain.Reminder {
+	var next []domain.Reminder
+	for _, reminder := range reminders {
+		if reminder.At == nil {
+			next = append(next, domain.Reminder{ID: uuid.NewString(), BeforeMinutes: reminder.BeforeMinutes})
+		}
+	}
+	return next
+}
+
+// checkProject makes sure the project a task is put into exists, nil is the inbox.
+func (t Task) checkProject(ctx context.Context, projectID *string) error {
+	if projectID == nil {
+		return nil
+	}
+	_, err := t.provider.GetProjectByID(ctx, *projectID)
+	return err
+}
+
+// projectIDOrNil treats an empty project id as the inbox.
+func projectIDOrNil(projectID *string) *string {
+	if projectID == nil || *projectID == "" {
+		return nil
+	}
+	return projectID
+}
+
+// priorityOrDefault gives tasks created without a priority, whether in the window, from the command line
+// or through the API, the default priority from the settings.
+func (t Task) priorityOrDefault(ctx context.Context, priority domain.TaskPriority) (domain.TaskPriority, error) {
+	if priority != "" {
+		return priority, nil
+	}
+	settings, err := t.provider.GetSettings(ctx)
+	if err != nil {
+		return "", err
+	}
+	return settings.DefaultPriority, nil
+}
+
+// recurrenceOrNil treats a rule without a frequency as no recurrence at all.
+func recurrenceOrNil(recurrence *domain.Recurrence) *domain.Recurrence {
+	if recurrence == nil || recurrence.Frequency == "" {
+		return nil
+	}
+	return recurrence
+}
+
+// incompleteChildren makes sure every subtask of the task can be done before the task itself is completed.
+// It returns the unfinished subtasks, deepest first, when force is set, otherwise ErrIncompleteSubtasks
+// if there are any.
+func (t Task) incompleteChildren(ctx context.Context, id string, force bool) ([]domain.Task, error) {
+	children, err := t.provider.GetChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var incomplete []domain.Task
+	for _, child := range children {
+		grandchildren, err := t.incompleteChildren(ctx, child.ID, force)
+		if err != nil {
+			return nil, err
+		}
+		incomplete = append(incomplete, grandchildren...)
+		if child.Status == domain.TaskStatusDone {
+			continue
+		}
+		if !force {
+			return nil, domain.ErrIncompleteSubtasks
+		}
+		incomplete = append(incomplete, child)
+	}
+
+	return incomplete, nil
+}
+
+// Delete moves the task together with its subtasks to the trash.
 func (t Task) Delete(ctx context.Context, id string) error {
 	const op = "service.task.delete"
 
 	deleteCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := t.modifier.DeleteTask(deleteCtx, id)
+	task, err := t.provider.GetTaskByID(deleteCtx, id)
 	if err != nil {
 		return handleError(op, err)
 	}
 
+	err = t.modifier.DeleteTask(deleteCtx, id)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	t.record(deleteCtx, domain.HistoryActionDelete, &task, nil)
+
 	return nil
 }
 
 func handleError(op string, err error) error {
+	var validationErr *domain.ValidationError
 	switch {
+	case errors.As(err, &validationErr):
+		return validationErr
 	case errors.Is(err, domain.ErrTaskNotFound):
 		return domain.ErrTaskNotFound
+	case errors.Is(err, domain.ErrProjectNotFound):
+		return domain.ErrProjectNotFound
+	case errors.Is(err, domain.ErrTagNotFound):
+		return domain.ErrTagNotFound
 	case errors.Is(err, domain.ErrInvalidArguments):
 		return domain.ErrInvalidArguments
+	case errors.Is(err, domain.ErrIncompleteSubtasks):
+		return domain.ErrIncompleteSubtasks
+	case errors.Is(err, domain.ErrNothingToUndo):
+		return domain.ErrNothingToUndo
+	case errors.Is(err, domain.ErrNothingToRedo):
+		return domain.ErrNothingToRedo
+	case errors.Is(err, domain.ErrBackupNotFound):
+		return domain.ErrBackupNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return domain.ErrConflict
 	default:
 		log.Error(op, err)
 		return domain.ErrInternal
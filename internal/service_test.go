@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"github.com/ARUMANDESU/todo-app/internal/domain"
 	"github.com/ARUMANDESU/todo-app/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -13,16 +14,20 @@ import (
 type Suite struct {
 	mockTaskProvider *mocks.TaskProvider
 	mockTaskModifier *mocks.TaskModifier
+	mockTaskHistory  *mocks.TaskHistory
 	taskService      Task
 }
 
 func newSuite(t *testing.T) *Suite {
 	mockTaskProvider := mocks.NewTaskProvider(t)
 	mockTaskModifier := mocks.NewTaskModifier(t)
-	taskService := NewTask(mockTaskProvider, mockTaskModifier)
+	mockTaskHistory := mocks.NewTaskHistory(t)
+	mockTaskHistory.On("PushHistory", mock.Anything, mock.Anything, domain.MaxHistoryEntries).Return(nil).Maybe()
+	taskService := NewTask(mockTaskProvider, mockTaskModifier, mockTaskHistory)
 	return &Suite{
 		mockTaskProvider: mockTaskProvider,
 		mockTaskModifier: mockTaskModifier,
+		mockTaskHistory:  mockTaskHistory,
 		taskService:      taskService,
 	}
 }
@@ -53,7 +58,7 @@ func TestTask_Create_InvalidRequest(t *testing.T) {
 	dueDate := time.Now().Add(24 * time.Hour)
 	request := domain.CreateTaskRequest{
 		Title:    "test-title",
-		Priority: "High",
+		Priority: domain.TaskPriorityHigh,
 		DueDate:  &dueDate,
 	}
 
@@ -64,6 +69,71 @@ func TestTask_Create_InvalidRequest(t *testing.T) {
 	suite.mockTaskModifier.AssertCalled(t, "CreateTask", mock.Anything, mock.AnythingOfType("domain.Task"))
 }
 
+func TestTask_Create_Priority(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		suite := newSuite(t)
+
+		_, err := suite.taskService.Create(context.Background(), domain.CreateTaskRequest{Title: "Call back", Priority: "urgent"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockTaskModifier.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty is the default priority", func(t *testing.T) {
+		suite := newSuite(t)
+
+		suite.mockTaskProvider.On("GetSettings", mock.Anything).Return(domain.Settings{DefaultPriority: domain.TaskPriorityMedium}, nil)
+		suite.mockTaskModifier.On("CreateTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+			return task.Priority == domain.TaskPriorityMedium
+		})).Return(func(_ context.Context, task domain.Task) (domain.Task, error) { return task, nil })
+
+		task, err := suite.taskService.Create(context.Background(), domain.CreateTaskRequest{Title: "Call back"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TaskPriorityMedium, task.Priority)
+	})
+}
+
+func TestTask_Project(t *testing.T) {
+	missing := "missing"
+
+	t.Run("create in unknown project", func(t *testing.T) {
+		suite := newSuite(t)
+		suite.mockTaskProvider.On("GetProjectByID", mock.Anything, "missing").
+			Return(domain.Project{}, fmt.Errorf("storage.sqlite.project.get_by_id: %w", domain.ErrProjectNotFound))
+
+		_, err := suite.taskService.Create(context.Background(), domain.CreateTaskRequest{Title: "Call back", ProjectID: &missing})
+
+		assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+		suite.mockTaskModifier.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("move to unknown project", func(t *testing.T) {
+		suite := newSuite(t)
+		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{ID: "123", Version: 1, Title: "Call back"}, nil)
+		suite.mockTaskProvider.On("GetProjectByID", mock.Anything, "missing").
+			Return(domain.Project{}, fmt.Errorf("storage.sqlite.project.get_by_id: %w", domain.ErrProjectNotFound))
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, ProjectID: &missing})
+
+		assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+		suite.mockTaskModifier.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty project is the inbox", func(t *testing.T) {
+		suite := newSuite(t)
+		inbox := ""
+		suite.mockTaskModifier.On("CreateTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+			return task.ProjectID == nil
+		})).Return(func(_ context.Context, task domain.Task) (domain.Task, error) { return task, nil })
+
+		_, err := suite.taskService.Create(context.Background(), domain.CreateTaskRequest{Title: "Call back", Priority: domain.TaskPriorityNone, ProjectID: &inbox})
+
+		assert.NoError(t, err)
+		suite.mockTaskProvider.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+	})
+}
+
 func TestTask_Update_Valid(t *testing.T) {
 	dueDateOld := time.Now().Add(24 * time.Hour)
 	dueDateNew := time.Now().Add(48 * time.Hour)
@@ -77,6 +147,7 @@ func TestTask_Update_Valid(t *testing.T) {
 			name: "update title and priority",
 			request: domain.UpdateTaskRequest{
 				ID:       "123",
+				Version:  1,
 				Title:    "Updated Task",
 				Priority: domain.TaskPriorityMedium,
 				DueDate:  &dueDateNew,
@@ -84,6 +155,7 @@ func TestTask_Update_Valid(t *testing.T) {
 			setup: func(suite *Suite) {
 				suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{
 					ID:         "123",
+					Version:    1,
 					Title:      "Old Task",
 					Status:     domain.TaskStatusTodo,
 					Priority:   domain.TaskPriorityHigh,
@@ -114,12 +186,14 @@ func TestTask_Update_Valid(t *testing.T) {
 		{
 			name: "update status",
 			request: domain.UpdateTaskRequest{
-				ID:     "123",
-				Status: domain.TaskStatusDone,
+				ID:      "123",
+				Version: 1,
+				Status:  domain.TaskStatusDone,
 			},
 			setup: func(suite *Suite) {
 				suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{
 					ID:         "123",
+					Version:    1,
 					Title:      "Old Task",
 					Status:     domain.TaskStatusTodo,
 					Priority:   domain.TaskPriorityHigh,
@@ -127,15 +201,17 @@ func TestTask_Update_Valid(t *testing.T) {
 					CreatedAt:  time.Now(),
 					ModifiedAt: time.Now(),
 				}, nil)
-				suite.mockTaskModifier.On("UpdateTask", mock.Anything, mock.AnythingOfType("domain.Task")).Return(domain.Task{
+				suite.mockTaskProvider.On("GetChildren", mock.Anything, "123").Return(nil, nil)
+				suite.mockTaskModifier.On("CompleteTask", mock.Anything, mock.AnythingOfType("domain.TaskCompletion")).Return(domain.TaskCompletion{Task: domain.Task{
 					ID:         "123",
+					Version:    1,
 					Title:      "Old Task",
 					Status:     domain.TaskStatusDone,
 					Priority:   domain.TaskPriorityHigh,
 					DueDate:    &dueDateOld,
 					CreatedAt:  time.Now(),
 					ModifiedAt: time.Now(),
-				}, nil)
+				}}, nil)
 			},
 			expected: domain.Task{
 				ID:         "123",
@@ -173,8 +249,9 @@ func TestTask_Update_Invalid(t *testing.T) {
 		dueDate := time.Now().Add(24 * time.Hour)
 		request := domain.UpdateTaskRequest{
 			ID:       "test-id",
+			Version:  1,
 			Title:    "Updated Task",
-			Priority: "Medium",
+			Priority: domain.TaskPriorityMedium,
 			DueDate:  &dueDate,
 		}
 
@@ -189,15 +266,16 @@ func TestTask_Update_Invalid(t *testing.T) {
 	t.Run("invalid request", func(t *testing.T) {
 		suite := newSuite(t)
 
-		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, mock.AnythingOfType("string")).Return(domain.Task{}, nil)
+		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, mock.AnythingOfType("string")).Return(domain.Task{Version: 1}, nil)
 		suite.mockTaskModifier.On("UpdateTask", mock.Anything, mock.AnythingOfType("domain.Task")).Return(domain.Task{}, assert.AnError)
 
 		ctx := context.Background()
 		dueDate := time.Now().Add(24 * time.Hour)
 		request := domain.UpdateTaskRequest{
 			ID:       "123",
+			Version:  1,
 			Title:    "",
-			Priority: "Medium",
+			Priority: domain.TaskPriorityMedium,
 			DueDate:  &dueDate,
 		}
 
@@ -217,8 +295,9 @@ func TestTask_Update_InvalidArguments(t *testing.T) {
 		dueDate := time.Now().Add(24 * time.Hour)
 		request := domain.UpdateTaskRequest{
 			ID:       "123",
+			Version:  1,
 			Title:    "12",
-			Priority: "Medium",
+			Priority: domain.TaskPriorityMedium,
 			DueDate:  &dueDate,
 		}
 
@@ -237,8 +316,9 @@ func TestTask_Update_InvalidArguments(t *testing.T) {
 		dueDate := time.Now().Add(-24 * time.Hour)
 		request := domain.UpdateTaskRequest{
 			ID:       "123",
+			Version:  1,
 			Title:    "Updated Task",
-			Priority: "Medium",
+			Priority: domain.TaskPriorityMedium,
 			DueDate:  &dueDate,
 		}
 
@@ -257,8 +337,9 @@ func TestTask_Update_InvalidArguments(t *testing.T) {
 		dueDate := time.Now().Add(24 * time.Hour)
 		request := domain.UpdateTaskRequest{
 			ID:       "",
+			Version:  1,
 			Title:    "Updated Task",
-			Priority: "Medium",
+			Priority: domain.TaskPriorityMedium,
 			DueDate:  &dueDate,
 		}
 
@@ -269,6 +350,304 @@ func TestTask_Update_InvalidArguments(t *testing.T) {
 		suite.mockTaskProvider.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.AnythingOfType("string"))
 		suite.mockTaskModifier.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.AnythingOfType("domain.Task"))
 	})
+
+	t.Run("unknown priority", func(t *testing.T) {
+		suite := newSuite(t)
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, Priority: "urgent"})
+
+		var validationErr *domain.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, []domain.FieldError{
+				{Field: "priority", Code: domain.ValidationInvalid, Message: "must be none, low, medium or high"},
+			}, validationErr.Fields)
+		}
+		suite.mockTaskProvider.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.AnythingOfType("string"))
+	})
+}
+
+func TestTask_Update_Conflict(t *testing.T) {
+	t.Run("stale version", func(t *testing.T) {
+		suite := newSuite(t)
+
+		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{ID: "123", Version: 2, Title: "Changed elsewhere"}, nil)
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, Title: "Updated Task"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		suite.mockTaskModifier.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("changed while updating", func(t *testing.T) {
+		suite := newSuite(t)
+
+		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{ID: "123", Version: 1, Title: "Old Task"}, nil)
+		suite.mockTaskModifier.On("UpdateTask", mock.Anything, mock.AnythingOfType("domain.Task")).
+			Return(domain.Task{}, fmt.Errorf("storage.sqlite.task.update: %w", domain.ErrConflict))
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, Title: "Updated Task"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		suite := newSuite(t)
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Title: "Updated Task"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
+
+func TestTask_CreateSubtask(t *testing.T) {
+	t.Run("created under the parent", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		projectID := "work"
+
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "parent").Return(domain.Task{ID: "parent", ProjectID: &projectID}, nil)
+		suite.mockTaskModifier.On("CreateTask", ctx, mock.AnythingOfType("domain.Task")).
+			Return(func(_ context.Context, task domain.Task) (domain.Task, error) { return task, nil })
+
+		task, err := suite.taskService.CreateSubtask(ctx, "parent", domain.CreateTaskRequest{Title: "Subtask", Priority: domain.TaskPriorityNone})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, task.ParentID) {
+			assert.Equal(t, "parent", *task.ParentID)
+		}
+		assert.Equal(t, &projectID, task.ProjectID)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "parent").Return(domain.Task{}, domain.ErrTaskNotFound)
+
+		_, err := suite.taskService.CreateSubtask(ctx, "parent", domain.CreateTaskRequest{Title: "Subtask"})
+
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+		suite.mockTaskModifier.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_MoveTask(t *testing.T) {
+	t.Run("under another task", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		parentID := "parent"
+
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "123").Return(domain.Task{ID: "123"}, nil)
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "parent").Return(domain.Task{ID: "parent"}, nil)
+		suite.mockTaskModifier.On("MoveTask", ctx, "123", &parentID, 1).Return(domain.Task{ID: "123", ParentID: &parentID, Position: 1}, nil)
+
+		task, err := suite.taskService.MoveTask(ctx, domain.MoveTaskRequest{ID: "123", ParentID: &parentID, Position: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, task.Position)
+	})
+
+	t.Run("under own subtask", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		taskID := "123"
+		childID := "child"
+
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "123").Return(domain.Task{ID: "123"}, nil)
+		suite.mockTaskProvider.On("GetTaskByID", ctx, "child").Return(domain.Task{ID: "child", ParentID: &taskID}, nil)
+
+		_, err := suite.taskService.MoveTask(ctx, domain.MoveTaskRequest{ID: "123", ParentID: &childID})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockTaskModifier.AssertNotCalled(t, "MoveTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_Update_CompleteWithSubtasks(t *testing.T) {
+	parentID := "123"
+	setup := func(suite *Suite) {
+		suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{ID: "123", Version: 1, Title: "Parent", Status: domain.TaskStatusTodo}, nil)
+		suite.mockTaskProvider.On("GetChildren", mock.Anything, "123").Return([]domain.Task{
+			{ID: "done", ParentID: &parentID, Status: domain.TaskStatusDone},
+			{ID: "todo", ParentID: &parentID, Status: domain.TaskStatusTodo},
+		}, nil)
+		suite.mockTaskProvider.On("GetChildren", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil)
+	}
+
+	t.Run("blocked by unfinished subtasks", func(t *testing.T) {
+		suite := newSuite(t)
+		setup(suite)
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, Status: domain.TaskStatusDone})
+
+		assert.ErrorIs(t, err, domain.ErrIncompleteSubtasks)
+		suite.mockTaskModifier.AssertNotCalled(t, "CompleteTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("completes unfinished subtasks", func(t *testing.T) {
+		suite := newSuite(t)
+		setup(suite)
+		suite.mockTaskModifier.On("CompleteTask", mock.Anything, mock.AnythingOfType("domain.TaskCompletion")).
+			Return(func(_ context.Context, completion domain.TaskCompletion) (domain.TaskCompletion, error) {
+				return completion, nil
+			})
+
+		task, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{
+			ID:               "123",
+			Version:          1,
+			Status:           domain.TaskStatusDone,
+			CompleteChildren: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TaskStatusDone, task.Status)
+		suite.mockTaskModifier.AssertCalled(t, "CompleteTask", mock.Anything, mock.MatchedBy(func(completion domain.TaskCompletion) bool {
+			return completion.Task.ID == "123" &&
+				len(completion.Children) == 1 &&
+				completion.Children[0].ID == "todo" &&
+				completion.Children[0].Status == domain.TaskStatusDone
+		}))
+		suite.mockTaskModifier.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale version leaves the subtasks unfinished", func(t *testing.T) {
+		suite := newSuite(t)
+		setup(suite)
+		suite.mockTaskModifier.On("CompleteTask", mock.Anything, mock.AnythingOfType("domain.TaskCompletion")).
+			Return(domain.TaskCompletion{}, fmt.Errorf("storage: %w", domain.ErrConflict))
+
+		_, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{
+			ID:               "123",
+			Version:          1,
+			Status:           domain.TaskStatusDone,
+			CompleteChildren: true,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		suite.mockTaskModifier.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything)
+		suite.mockTaskHistory.AssertNotCalled(t, "PushHistory", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_Update_CompleteRecurring(t *testing.T) {
+	suite := newSuite(t)
+	dueDate := time.Now().Add(time.Hour)
+	rule := &domain.Recurrence{Frequency: domain.RecurrenceDaily}
+
+	suite.mockTaskProvider.On("GetTaskByID", mock.Anything, "123").Return(domain.Task{
+		ID:         "123",
+		Version:    1,
+		Title:      "Water the plants",
+		Status:     domain.TaskStatusTodo,
+		Priority:   domain.TaskPriorityLow,
+		Tags:       domain.StringArray{"home"},
+		DueDate:    &dueDate,
+		Recurrence: rule,
+	}, nil)
+	suite.mockTaskProvider.On("GetChildren", mock.Anything, "123").Return(nil, nil)
+	suite.mockTaskModifier.On("CompleteTask", mock.Anything, mock.AnythingOfType("domain.TaskCompletion")).
+		Return(func(_ context.Context, completion domain.TaskCompletion) (domain.TaskCompletion, error) {
+			return completion, nil
+		})
+
+	task, err := suite.taskService.Update(context.Background(), domain.UpdateTaskRequest{ID: "123", Version: 1, Status: domain.TaskStatusDone})
+
+	assert.NoError(t, err)
+	assert.Nil(t, task.Recurrence)
+	suite.mockTaskModifier.AssertCalled(t, "CompleteTask", mock.Anything, mock.MatchedBy(func(completion domain.TaskCompletion) bool {
+		next := completion.Next
+		return completion.Task.Recurrence == nil &&
+			next != nil &&
+			next.ID != "123" &&
+			next.Title == "Water the plants" &&
+			next.Status == domain.TaskStatusTodo &&
+			next.Recurrence == rule &&
+			next.DueDate.Equal(dueDate.AddDate(0, 0, 1))
+	}))
+	suite.mockTaskModifier.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+}
+
+func TestTask_GetAll_FollowsCursor(t *testing.T) {
+	suite := newSuite(t)
+	ctx := context.Background()
+
+	suite.mockTaskProvider.On("ListTasks", ctx, domain.TaskQuery{Limit: domain.MaxTaskQueryLimit}).
+		Return(domain.TaskPage{Tasks: []domain.Task{{ID: "1"}, {ID: "2"}}, NextCursor: "next"}, nil)
+	suite.mockTaskProvider.On("ListTasks", ctx, domain.TaskQuery{Limit: domain.MaxTaskQueryLimit, Cursor: "next"}).
+		Return(domain.TaskPage{Tasks: []domain.Task{{ID: "3"}}}, nil)
+
+	tasks, err := suite.taskService.GetAll(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 3)
+}
+
+func TestTask_List_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query domain.TaskQuery
+	}{
+		{"unknown status", domain.TaskQuery{Statuses: []domain.TaskStatus{"archived"}}},
+		{"unknown priority", domain.TaskQuery{Priorities: []domain.TaskPriority{"urgent"}}},
+		{"unknown sort key", domain.TaskQuery{SortBy: "tags"}},
+		{"unknown sort direction", domain.TaskQuery{SortDirection: "up"}},
+		{"limit too large", domain.TaskQuery{Limit: domain.MaxTaskQueryLimit + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := newSuite(t)
+
+			_, err := suite.taskService.List(context.Background(), tt.query)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+			suite.mockTaskProvider.AssertNotCalled(t, "ListTasks", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTask_List_InvalidCursor(t *testing.T) {
+	suite := newSuite(t)
+	ctx := context.Background()
+	query := domain.TaskQuery{Cursor: "garbage"}
+
+	suite.mockTaskProvider.On("ListTasks", ctx, query).Return(domain.TaskPage{},
+		fmt.Errorf("storage.sqlite.task.list: %w", domain.NewValidationError("cursor", domain.ValidationInvalid, "is malformed")))
+
+	_, err := suite.taskService.List(ctx, query)
+
+	var validationErr *domain.ValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		assert.Equal(t, []domain.FieldError{{Field: "cursor", Code: domain.ValidationInvalid, Message: "is malformed"}}, validationErr.Fields)
+	}
+	assert.Equal(t, "invalid arguments: cursor: is malformed", err.Error())
+}
+
+func TestTask_GetTaskHistory(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		events := []domain.TaskEvent{
+			{ID: 1, TaskID: "123", Action: domain.TaskEventCreated},
+			{ID: 2, TaskID: "123", Action: domain.TaskEventUpdated, Changes: []domain.TaskFieldChange{{Field: "priority", Old: "low", New: "high"}}},
+		}
+
+		suite.mockTaskProvider.On("GetTaskEvents", ctx, "123").Return(events, nil)
+
+		history, err := suite.taskService.GetTaskHistory(ctx, "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, events, history)
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		_, err := suite.taskService.GetTaskHistory(ctx, "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
 }
 
 func isTaskEqual(t *testing.T, expected, actual domain.Task, tolerance time.Duration) {
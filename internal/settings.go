@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Settings reads and changes the preferences of the profile.
+type Settings struct {
+	store SettingsStore
+}
+
+//go:generate mockery --name SettingsStore
+type SettingsStore interface {
+	GetSettings(ctx context.Context) (domain.Settings, error)
+	UpdateSettings(ctx context.Context, settings domain.Settings) error
+}
+
+func NewSettings(store SettingsStore) Settings {
+	return Settings{store: store}
+}
+
+// Get returns the settings, defaults included.
+func (s Settings) Get(ctx context.Context) (domain.Settings, error) {
+	const op = "service.settings.get"
+
+	settings, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return domain.Settings{}, handleError(op, err)
+	}
+
+	return settings, nil
+}
+
+// Update changes the settings given in the request and returns all of them.
+func (s Settings) Update(ctx context.Context, request domain.UpdateSettingsRequest) (domain.Settings, error) {
+	const op = "service.settings.update"
+
+	settings, err := s.store.GetSettings(ctx)
+	if err != nil {
+		return domain.Settings{}, handleError(op, err)
+	}
+
+	if request.DefaultPriority != "" {
+		settings.DefaultPriority = request.DefaultPriority
+	}
+	if request.ConfirmDeletion != nil {
+		settings.ConfirmDeletion = *request.ConfirmDeletion
+	}
+	if request.WeekStart != nil {
+		settings.WeekStart = *request.WeekStart
+	}
+	if request.DateFormat != "" {
+		settings.DateFormat = request.DateFormat
+	}
+	if request.Theme != "" {
+		settings.Theme = request.Theme
+	}
+	if request.TrashRetentionDays != nil {
+		settings.TrashRetentionDays = *request.TrashRetentionDays
+	}
+	if request.WindowWidth != nil {
+		settings.WindowWidth = *request.WindowWidth
+	}
+	if request.WindowHeight != nil {
+		settings.WindowHeight = *request.WindowHeight
+	}
+
+	if err := validateSettings(settings); err != nil {
+		return domain.Settings{}, invalidArguments(err)
+	}
+
+	if err := s.store.UpdateSettings(ctx, settings); err != nil {
+		return domain.Settings{}, handleError(op, err)
+	}
+
+	return settings, nil
+}
+
+func validateSettings(settings domain.Settings) error {
+	return validation.ValidateStruct(&settings,
+		validation.Field(&settings.DefaultPriority, validation.Required, validation.In(
+			domain.TaskPriorityNone,
+			domain.TaskPriorityLow,
+			domain.TaskPriorityMedium,
+			domain.TaskPriorityHigh,
+		)),
+		validation.Field(&settings.WeekStart, validation.Min(time.Sunday), validation.Max(time.Saturday)),
+		validation.Field(&settings.DateFormat, validation.Required, validation.In(
+			domain.DateFormatISO,
+			domain.DateFormatEuropean,
+			domain.DateFormatBritish,
+			domain.DateFormatAmerican,
+		)),
+		validation.Field(&settings.Theme, validation.Required, validation.In(domain.ThemeSystem, domain.ThemeLight, domain.ThemeDark)),
+		validation.Field(&settings.TrashRetentionDays, validation.Required, validation.Min(1), validation.Max(3650)),
+		validation.Field(&settings.WindowWidth, validation.Required, validation.Min(400), validation.Max(7680)),
+		validation.Field(&settings.WindowHeight, validation.Required, validation.Min(300), validation.Max(4320)),
+	)
+}
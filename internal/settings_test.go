@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestSettings_Update(t *testing.T) {
+	t.Run("changes only the given settings", func(t *testing.T) {
+		store := mocks.NewSettingsStore(t)
+		settings := NewSettings(store)
+
+		confirm := false
+		weekStart := time.Sunday
+		want := domain.DefaultSettings()
+		want.ConfirmDeletion = false
+		want.WeekStart = time.Sunday
+		want.Theme = domain.ThemeDark
+
+		store.On("GetSettings", mock.Anything).Return(domain.DefaultSettings(), nil)
+		store.On("UpdateSettings", mock.Anything, want).Return(nil)
+
+		got, err := settings.Update(context.Background(), domain.UpdateSettingsRequest{
+			ConfirmDeletion: &confirm,
+			WeekStart:       &weekStart,
+			Theme:           domain.ThemeDark,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	weekday, days, width := time.Weekday(7), 0, 100
+	invalid := []struct {
+		name    string
+		request domain.UpdateSettingsRequest
+	}{
+		{"unknown priority", domain.UpdateSettingsRequest{DefaultPriority: "urgent"}},
+		{"unknown date format", domain.UpdateSettingsRequest{DateFormat: "YY-M-D"}},
+		{"unknown theme", domain.UpdateSettingsRequest{Theme: "solarized"}},
+		{"week start out of range", domain.UpdateSettingsRequest{WeekStart: &weekday}},
+		{"no trash retention", domain.UpdateSettingsRequest{TrashRetentionDays: &days}},
+		{"window too small", domain.UpdateSettingsRequest{WindowWidth: &width}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			store := mocks.NewSettingsStore(t)
+			settings := NewSettings(store)
+
+			store.On("GetSettings", mock.Anything).Return(domain.DefaultSettings(), nil)
+
+			_, err := settings.Update(context.Background(), tt.request)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+			store.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDefaultSettings_Valid(t *testing.T) {
+	assert.NoError(t, validateSettings(domain.DefaultSettings()))
+	assert.Equal(t, domain.DefaultTrashRetention, domain.DefaultSettings().TrashRetention())
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	msqlite "modernc.org/sqlite"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeLayout is the UTC time in backup file names, it sorts in the order the backups were made.
+const backupTimeLayout = "20060102T150405.000Z"
+
+// backupsDir is the directory next to the database file where its backups are kept.
+func backupsDir(dataSource string) string {
+	return filepath.Join(filepath.Dir(dataSource), "backups")
+}
+
+// backupDatabase copies the database into a new file in dir with VACUUM INTO and then deletes
+// the oldest backups so that at most keep are left.
+func backupDatabase(ctx context.Context, db *sql.DB, dir string, reason domain.BackupReason, keep int) (domain.Backup, error) {
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return domain.Backup{}, err
+	}
+
+	name := fmt.Sprintf("tasks-%s-%s.db", time.Now().UTC().Format(backupTimeLayout), reason)
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, filepath.Join(dir, name)); err != nil {
+		return domain.Backup{}, err
+	}
+
+	backups, err := listBackups(dir)
+	if err != nil {
+		return domain.Backup{}, err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(dir, backups[i].Name)); err != nil {
+			return domain.Backup{}, err
+		}
+	}
+
+	for _, backup := range backups {
+		if backup.Name == name {
+			return backup, nil
+		}
+	}
+	return domain.Backup{}, fmt.Errorf("backup %s was not written", name)
+}
+
+// listBackups returns the backups in dir, newest first. Files not named like a backup are ignored.
+func listBackups(dir string) ([]domain.Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []domain.Backup
+	for _, entry := range entries {
+		backup, ok := parseBackupName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backup.Size = info.Size()
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// parseBackupName reads a file name written by backupDatabase, e.g. tasks-20240301T101500.000Z-scheduled.db.
+func parseBackupName(name string) (domain.Backup, bool) {
+	rest, ok := strings.CutPrefix(name, "tasks-")
+	if !ok {
+		return domain.Backup{}, false
+	}
+	rest, ok = strings.CutSuffix(rest, ".db")
+	if !ok {
+		return domain.Backup{}, false
+	}
+	at, reason, ok := strings.Cut(rest, "-")
+	if !ok {
+		return domain.Backup{}, false
+	}
+	createdAt, err := time.Parse(backupTimeLayout, at)
+	if err != nil {
+		return domain.Backup{}, false
+	}
+
+	return domain.Backup{
+		Name:      name,
+		Reason:    domain.BackupReason(reason),
+		CreatedAt: createdAt,
+	}, true
+}
+
+// CreateBackup copies the database into the backups directory, keeping only the newest backups.
+func (s Storage) CreateBackup(ctx context.Context, reason domain.BackupReason, keep int) (domain.Backup, error) {
+	const op = "storage.sqlite.backup.create"
+
+	backup, err := backupDatabase(ctx, s.db, backupsDir(s.path), reason, keep)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return backup, nil
+}
+
+// ListBackups returns the backups of the database, newest first.
+func (s Storage) ListBackups(ctx context.Context) ([]domain.Backup, error) {
+	const op = "storage.sqlite.backup.list"
+
+	backups, err := listBackups(backupsDir(s.path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return backups, nil
+}
+
+// RestoreBackup replaces the content of the database with the backup using the SQLite online backup API,
+// so the open connection keeps working. A backup made by an older version is migrated afterwards.
+func (s Storage) RestoreBackup(ctx context.Context, name string) error {
+	const op = "storage.sqlite.backup.restore"
+
+	backup, ok := parseBackupName(name)
+	if !ok || filepath.Base(name) != name {
+		return fmt.Errorf("%s: %w", op, domain.ErrBackupNotFound)
+	}
+	path := filepath.Join(backupsDir(s.path), backup.Name)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s: %w", op, domain.ErrBackupNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		restorer, ok := driverConn.(interface {
+			NewRestore(srcUri string) (*msqlite.Backup, error)
+		})
+		if !ok {
+			return fmt.Errorf("driver does not support restoring backups")
+		}
+
+		restore, err := restorer.NewRestore(path)
+		if err != nil {
+			return err
+		}
+		if _, err := restore.Step(-1); err != nil {
+			restore.Finish()
+			return err
+		}
+		return restore.Finish()
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	conn.Close()
+
+	if err := migrateSchema(s.path); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// the CalDAV account and sync token are kept in the sync_state table next to the state of the folder sync
+const (
+	caldavAccountKey   = "caldav_account"
+	caldavSyncTokenKey = "caldav_sync_token"
+)
+
+// GetCalDAVState returns the state of the CalDAV sync, it is empty before an account is set. The account has
+// a password only when it was saved by an older version, which kept the password in the database.
+func (s Storage) GetCalDAVState(ctx context.Context) (domain.CalDAVState, error) {
+	const op = "storage.sqlite.caldav.get_state"
+
+	state := domain.CalDAVState{Resources: make(map[string]domain.CalDAVResource)}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM sync_state WHERE key IN (?, ?)`, caldavAccountKey, caldavSyncTokenKey)
+	if err != nil {
+		return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var target any = &state.Account
+		if key == caldavSyncTokenKey {
+			target = &state.SyncToken
+		}
+		if err := json.Unmarshal([]byte(value), target); err != nil {
+			return domain.CalDAVState{}, fmt.Errorf("%s: %s: %w", op, key, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT task_id, href, etag, version FROM caldav_resources`)
+	if err != nil {
+		return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resource domain.CalDAVResource
+		if err := rows.Scan(&resource.TaskID, &resource.Href, &resource.ETag, &resource.Version); err != nil {
+			return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+		}
+		state.Resources[resource.TaskID] = resource
+	}
+	if err := rows.Err(); err != nil {
+		return domain.CalDAVState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
+
+// SaveCalDAVState saves the account, the sync token and replaces the resources with the ones of the state.
+// The password of the account is left out, the database is copied into every backup.
+func (s Storage) SaveCalDAVState(ctx context.Context, state domain.CalDAVState) error {
+	const op = "storage.sqlite.caldav.save_state"
+
+	state.Account.Password = ""
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	values := map[string]any{
+		caldavAccountKey:   state.Account,
+		caldavSyncTokenKey: state.SyncToken,
+	}
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO sync_state(key, value) VALUES(?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+			key,
+			string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM caldav_resources`); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, resource := range state.Resources {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO caldav_resources(task_id, href, etag, version) VALUES(?, ?, ?, ?)`,
+			resource.TaskID,
+			resource.Href,
+			resource.ETag,
+			resource.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
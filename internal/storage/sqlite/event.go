@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// taskFields lists the audited fields of a task with their values formatted as text.
+func taskFields(task domain.Task) [][2]string {
+	var dueDate, recurrence, projectID string
+	if task.DueDate != nil {
+		dueDate = task.DueDate.UTC().Format(time.RFC3339)
+	}
+	if task.Recurrence != nil {
+		recurrence = task.Recurrence.String()
+	}
+	if task.ProjectID != nil {
+		projectID = *task.ProjectID
+	}
+
+	return [][2]string{
+		{"title", task.Title},
+		{"description", task.Description},
+		{"status", string(task.Status)},
+		{"priority", string(task.Priority)},
+		{"due_date", dueDate},
+		{"recurrence", recurrence},
+		{"project_id", projectID},
+		{"tags", strings.Join(task.Tags, ", ")},
+	}
+}
+
+// diffTasks returns the fields whose values differ between the old and the new task.
+func diffTasks(old, new domain.Task) []domain.TaskFieldChange {
+	oldFields, newFields := taskFields(old), taskFields(new)
+
+	var changes []domain.TaskFieldChange
+	for i := range newFields {
+		if oldFields[i][1] != newFields[i][1] {
+			changes = append(changes, domain.TaskFieldChange{
+				Field: newFields[i][0],
+				Old:   oldFields[i][1],
+				New:   newFields[i][1],
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffPlacement returns the changes of the parent and position of a moved task, which are not audited fields
+// because only moving a task changes them.
+func diffPlacement(old, new domain.Task) []domain.TaskFieldChange {
+	var oldParent, newParent string
+	if old.ParentID != nil {
+		oldParent = *old.ParentID
+	}
+	if new.ParentID != nil {
+		newParent = *new.ParentID
+	}
+
+	var changes []domain.TaskFieldChange
+	if oldParent != newParent {
+		changes = append(changes, domain.TaskFieldChange{Field: "parent_id", Old: oldParent, New: newParent})
+	}
+	if old.Position != new.Position {
+		changes = append(changes, domain.TaskFieldChange{Field: "position", Old: strconv.Itoa(old.Position), New: strconv.Itoa(new.Position)})
+	}
+	return changes
+}
+
+func insertTaskEvent(ctx context.Context, tx *sql.Tx, taskID string, action domain.TaskEventAction, changes []domain.TaskFieldChange, at time.Time) error {
+	if changes == nil {
+		changes = []domain.TaskFieldChange{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO task_events(task_id, action, changes, created_at) VALUES(?, ?, ?, ?)`,
+		taskID,
+		action,
+		string(data),
+		at,
+	)
+	return err
+}
+
+// recordTaskChanges runs change, which alters the tasks through other tables such as tags, and gives
+// every task whose audited fields changed a new version and an event in its audit log.
+func recordTaskChanges(ctx context.Context, tx *sql.Tx, ids []string, change func() error) error {
+	previous := make([]domain.Task, 0, len(ids))
+	for _, id := range ids {
+		task, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+		if err != nil {
+			return err
+		}
+		previous = append(previous, task)
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	modifiedAt := time.Now()
+	for _, before := range previous {
+		after, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, before.ID))
+		if err != nil {
+			return err
+		}
+		changes := diffTasks(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE tasks SET modified_at = ?, version = version + 1 WHERE id = ?`, modifiedAt, before.ID)
+		if err != nil {
+			return err
+		}
+		if err := insertTaskEvent(ctx, tx, before.ID, domain.TaskEventUpdated, changes, modifiedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTaskEvents returns the audit log of the task, oldest event first.
+func (s Storage) GetTaskEvents(ctx context.Context, taskID string) ([]domain.TaskEvent, error) {
+	const op = "storage.sqlite.task.get_events"
+
+	stmt, err := s.db.Prepare(`SELECT id, task_id, action, changes, created_at FROM task_events WHERE task_id = ? ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []domain.TaskEvent
+	for rows.Next() {
+		var event domain.TaskEvent
+		var changes string
+		if err := rows.Scan(&event.ID, &event.TaskID, &event.Action, &changes, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal([]byte(changes), &event.Changes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,259 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+const historyColumns = `id, action, before, after, undone, created_at, effects`
+
+func scanHistoryEntry(row rowScanner) (domain.HistoryEntry, error) {
+	var entry domain.HistoryEntry
+	var before, after, effects sql.NullString
+	err := row.Scan(
+		&entry.ID,
+		&entry.Action,
+		&before,
+		&after,
+		&entry.Undone,
+		&entry.CreatedAt,
+		&effects,
+	)
+	if err != nil {
+		return domain.HistoryEntry{}, err
+	}
+
+	if entry.Before, err = decodeTaskSnapshot(before); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	if entry.After, err = decodeTaskSnapshot(after); err != nil {
+		return domain.HistoryEntry{}, err
+	}
+	if effects.Valid {
+		if err := json.Unmarshal([]byte(effects.String), &entry.Effects); err != nil {
+			return domain.HistoryEntry{}, fmt.Errorf("failed to decode history effects: %w", err)
+		}
+	}
+
+	return entry, nil
+}
+
+func encodeTaskSnapshot(task *domain.Task) (sql.NullString, error) {
+	if task == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeTaskSnapshot(s sql.NullString) (*domain.Task, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	var task domain.Task
+	if err := json.Unmarshal([]byte(s.String), &task); err != nil {
+		return nil, fmt.Errorf("failed to decode task snapshot: %w", err)
+	}
+	return &task, nil
+}
+
+// PushHistory records the entry on top of the undo history. Undone entries can no longer be redone
+// once a new entry is recorded, and only the newest limit entries are kept.
+func (s Storage) PushHistory(ctx context.Context, entry domain.HistoryEntry, limit int) error {
+	const op = "storage.sqlite.history.push"
+
+	before, err := encodeTaskSnapshot(entry.Before)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	after, err := encodeTaskSnapshot(entry.After)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	var effects sql.NullString
+	if len(entry.Effects) > 0 {
+		data, err := json.Marshal(entry.Effects)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		effects = sql.NullString{String: string(data), Valid: true}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_history WHERE undone`); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO task_history(action, before, after, undone, created_at, effects) VALUES(?, ?, ?, FALSE, ?, ?)`,
+		entry.Action,
+		before,
+		after,
+		entry.CreatedAt,
+		effects,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`DELETE FROM task_history WHERE id NOT IN (SELECT id FROM task_history ORDER BY id DESC LIMIT ?)`,
+		limit,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetUndoEntry returns the most recent entry that has not been undone.
+func (s Storage) GetUndoEntry(ctx context.Context) (domain.HistoryEntry, error) {
+	const op = "storage.sqlite.history.get_undo"
+
+	entry, err := scanHistoryEntry(s.db.QueryRowContext(
+		ctx,
+		`SELECT `+historyColumns+` FROM task_history WHERE NOT undone ORDER BY id DESC LIMIT 1`,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.HistoryEntry{}, fmt.Errorf("%s: %w", op, domain.ErrNothingToUndo)
+		}
+		return domain.HistoryEntry{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+// GetRedoEntry returns the earliest undone entry, which is the last one that was undone.
+func (s Storage) GetRedoEntry(ctx context.Context) (domain.HistoryEntry, error) {
+	const op = "storage.sqlite.history.get_redo"
+
+	entry, err := scanHistoryEntry(s.db.QueryRowContext(
+		ctx,
+		`SELECT `+historyColumns+` FROM task_history WHERE undone ORDER BY id LIMIT 1`,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.HistoryEntry{}, fmt.Errorf("%s: %w", op, domain.ErrNothingToRedo)
+		}
+		return domain.HistoryEntry{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+// ApplyHistory reverts the entry when undo is set and replays it otherwise, then marks it as undone or not, all in
+// a single transaction. The effects of the entry are reverted before it and replayed after it, an effect whose task
+// is gone by now, e.g. because the trash was emptied, is skipped so it does not hold up the rest. Created and deleted
+// tasks are moved to and from the trash.
+func (s Storage) ApplyHistory(ctx context.Context, entry domain.HistoryEntry, undo bool) error {
+	const op = "storage.sqlite.history.apply"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if undo {
+		for i := len(entry.Effects) - 1; i >= 0; i-- {
+			if err := applyHistoryEffect(ctx, tx, entry.Effects[i], undo); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if err := applyHistoryEntry(ctx, tx, entry, undo); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !undo {
+		for _, effect := range entry.Effects {
+			if err := applyHistoryEffect(ctx, tx, effect, undo); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE task_history SET undone = ? WHERE id = ?`, undo, entry.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func applyHistoryEffect(ctx context.Context, tx *sql.Tx, effect domain.HistoryEntry, undo bool) error {
+	err := applyHistoryEntry(ctx, tx, effect, undo)
+	if errors.Is(err, domain.ErrTaskNotFound) {
+		return nil
+	}
+	return err
+}
+
+func applyHistoryEntry(ctx context.Context, tx *sql.Tx, entry domain.HistoryEntry, undo bool) error {
+	switch entry.Action {
+	case domain.HistoryActionCreate:
+		if undo {
+			return deleteTask(ctx, tx, entry.After.ID)
+		}
+		_, err := restoreTask(ctx, tx, entry.After.ID)
+		return err
+	case domain.HistoryActionDelete:
+		if undo {
+			_, err := restoreTask(ctx, tx, entry.Before.ID)
+			return err
+		}
+		return deleteTask(ctx, tx, entry.Before.ID)
+	case domain.HistoryActionUpdate:
+		snapshot := *entry.After
+		if undo {
+			snapshot = *entry.Before
+		}
+		// the history is applied over whatever changed since, so the snapshot takes the current version
+		err := tx.QueryRowContext(ctx, `SELECT version FROM tasks WHERE id = ? AND deleted_at IS NULL`, snapshot.ID).Scan(&snapshot.Version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = updateTask(ctx, tx, snapshot)
+		return err
+	default:
+		return fmt.Errorf("unknown history action %q", entry.Action)
+	}
+}
+
+// DeleteHistoryEntry drops an entry that can no longer be applied.
+func (s Storage) DeleteHistoryEntry(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.history.delete"
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM task_history WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
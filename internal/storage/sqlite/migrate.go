@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"log"
+	"os"
+)
+
+// ErrMigration is returned when the schema of the database could not be migrated,
+// the database is left as it was or marked dirty, see MigrationStatus.
+var ErrMigration = errors.New("failed to perform migrations")
+
+// migrator applies the embedded migrations to the database file at path.
+type migrator struct {
+	path    string
+	db      *sql.DB
+	src     source.Driver
+	migrate *migrate.Migrate
+}
+
+func newMigrator(path string) (*migrator, error) {
+	db, err := sql.Open("sqlite", path+dataSourceParams)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite connection: %w", err)
+	}
+
+	migrateDriver, err := sqlite.WithInstance(db, &sqlite.Config{
+		MigrationsTable: "migrations",
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	srcDriver, err := iofs.New(migrationsFs, "migrations")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration source driver: %w", err)
+	}
+	preparedMigrations, err := migrate.NewWithInstance(
+		"iofs",
+		srcDriver,
+		"",
+		migrateDriver,
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration tooling instance: %w", err)
+	}
+
+	return &migrator{path: path, db: db, src: srcDriver, migrate: preparedMigrations}, nil
+}
+
+func (m *migrator) Close() {
+	m.migrate.Close()
+	m.db.Close()
+}
+
+// status compares the version of the database with the embedded migrations.
+func (m *migrator) status() (domain.MigrationStatus, error) {
+	var status domain.MigrationStatus
+
+	version, dirty, err := m.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return domain.MigrationStatus{}, err
+	}
+	status.Version, status.Dirty = version, dirty
+
+	next, err := m.src.First()
+	for err == nil {
+		status.Latest = next
+		if next <= status.Version {
+			status.Applied++
+		} else {
+			status.Pending++
+		}
+		next, err = m.src.Next(next)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return domain.MigrationStatus{}, err
+	}
+
+	return status, nil
+}
+
+// backUp copies the database into the backups directory before it is migrated.
+func (m *migrator) backUp() (domain.Backup, error) {
+	backup, err := backupDatabase(context.Background(), m.db, backupsDir(m.path), domain.BackupMigration, domain.DefaultBackupsKept)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("failed to back up before migrating: %w", err)
+	}
+	log.Println("Backed up db before migrating to", backup.Name)
+	return backup, nil
+}
+
+// migrateSchema backs the database up before applying the migrations, when there are any to apply.
+func migrateSchema(dataSource string) error {
+	m, err := newMigrator(dataSource)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	status, err := m.status()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	// a new database has nothing to lose
+	if status.Version > 0 && (status.Pending > 0 || status.Dirty) {
+		if _, err := m.backUp(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	log.Println("Successfully applied db migrations")
+	return nil
+}
+
+// openMigrator opens an existing database for the migration commands, which should not create a database by mistake.
+func openMigrator(path string) (*migrator, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return newMigrator(path)
+}
+
+// MigrationStatus returns the schema version of the database at path without migrating it.
+func MigrationStatus(path string) (domain.MigrationStatus, error) {
+	const op = "storage.sqlite.migration.status"
+
+	m, err := openMigrator(path)
+	if err != nil {
+		return domain.MigrationStatus{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer m.Close()
+
+	status, err := m.status()
+	if err != nil {
+		return domain.MigrationStatus{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return status, nil
+}
+
+// MigrateSteps applies the next steps migrations to the database at path, all pending ones when steps is 0,
+// or rolls back -steps migrations when steps is negative. The database is backed up first, the backup is
+// returned, it is empty when there was nothing to migrate.
+func MigrateSteps(path string, steps int) (domain.Backup, error) {
+	const op = "storage.sqlite.migration.steps"
+
+	m, err := openMigrator(path)
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer m.Close()
+
+	status, err := m.status()
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if status.Dirty {
+		return domain.Backup{}, fmt.Errorf("%s: %w: version %d is dirty, force a version after repairing the database", op, ErrMigration, status.Version)
+	}
+	switch {
+	case steps > status.Pending:
+		return domain.Backup{}, domain.NewValidationError("steps", domain.ValidationTooLarge, fmt.Sprintf("only %d migrations are pending", status.Pending))
+	case -steps > status.Applied:
+		return domain.Backup{}, domain.NewValidationError("steps", domain.ValidationTooLarge, fmt.Sprintf("only %d migrations are applied", status.Applied))
+	case steps == 0 && status.Pending == 0:
+		return domain.Backup{}, nil
+	}
+
+	var backup domain.Backup
+	if status.Version > 0 {
+		if backup, err = m.backUp(); err != nil {
+			return domain.Backup{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if steps == 0 {
+		err = m.migrate.Up()
+	} else {
+		err = m.migrate.Steps(steps)
+	}
+	if err != nil {
+		return backup, fmt.Errorf("%s: %w: %w", op, ErrMigration, err)
+	}
+
+	return backup, nil
+}
+
+// ForceMigrationVersion marks the database at path as migrated to the version and clears the dirty flag,
+// without running any migration. It is for repairing the database by hand after a migration failed halfway,
+// version 0 marks it as having no schema.
+func ForceMigrationVersion(path string, version uint) error {
+	const op = "storage.sqlite.migration.force"
+
+	m, err := openMigrator(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer m.Close()
+
+	if version == 0 {
+		err = m.migrate.Force(-1)
+	} else {
+		migration, _, readErr := m.src.ReadUp(version)
+		if readErr != nil {
+			return domain.NewValidationError("version", domain.ValidationInvalid, fmt.Sprintf("there is no migration %d", version))
+		}
+		migration.Close()
+		err = m.migrate.Force(int(version))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+ALTER TABLE tasks DROP COLUMN parent_id;
+ALTER TABLE tasks DROP COLUMN position;
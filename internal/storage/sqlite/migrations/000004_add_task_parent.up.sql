@@ -0,0 +1,4 @@
+-- add parent reference and sibling order to tasks table
+ALTER TABLE tasks ADD COLUMN parent_id TEXT; -- NULL for top-level tasks
+ALTER TABLE tasks ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
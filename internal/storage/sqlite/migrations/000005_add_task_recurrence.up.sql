@@ -0,0 +1,2 @@
+-- add recurrence rule column to tasks table
+ALTER TABLE tasks ADD COLUMN recurrence TEXT; -- RRULE-like rule, NULL for one-off tasks
@@ -0,0 +1,13 @@
+-- Description: Create the project table and assign tasks to projects
+CREATE TABLE IF NOT EXISTS projects (
+    id TEXT PRIMARY KEY, -- UUID
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '',
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE tasks ADD COLUMN project_id TEXT; -- NULL for tasks in the inbox
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
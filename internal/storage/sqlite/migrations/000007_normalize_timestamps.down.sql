@@ -0,0 +1,2 @@
+-- the normalized timestamps are readable by older versions, nothing to revert
+SELECT 1;
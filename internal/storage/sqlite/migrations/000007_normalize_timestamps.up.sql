@@ -0,0 +1,12 @@
+-- rewrite timestamps stored in Go's time.Time.String format ("2006-01-02 15:04:05.999 -0700 MST")
+-- into the format understood by the SQLite date functions ("2006-01-02 15:04:05.999-07:00")
+UPDATE tasks SET created_at = substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1) || substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1, 3) || ':' || substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 4, 2)
+WHERE instr(substr(created_at, 12), ' ') > 0;
+UPDATE tasks SET modified_at = substr(modified_at, 1, 11) || substr(substr(modified_at, 12), 1, instr(substr(modified_at, 12), ' ') - 1) || substr(substr(modified_at, 12), instr(substr(modified_at, 12), ' ') + 1, 3) || ':' || substr(substr(modified_at, 12), instr(substr(modified_at, 12), ' ') + 4, 2)
+WHERE instr(substr(modified_at, 12), ' ') > 0;
+UPDATE tasks SET due_date = substr(due_date, 1, 11) || substr(substr(due_date, 12), 1, instr(substr(due_date, 12), ' ') - 1) || substr(substr(due_date, 12), instr(substr(due_date, 12), ' ') + 1, 3) || ':' || substr(substr(due_date, 12), instr(substr(due_date, 12), ' ') + 4, 2)
+WHERE instr(substr(due_date, 12), ' ') > 0;
+UPDATE projects SET created_at = substr(created_at, 1, 11) || substr(substr(created_at, 12), 1, instr(substr(created_at, 12), ' ') - 1) || substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 1, 3) || ':' || substr(substr(created_at, 12), instr(substr(created_at, 12), ' ') + 4, 2)
+WHERE instr(substr(created_at, 12), ' ') > 0;
+UPDATE projects SET modified_at = substr(modified_at, 1, 11) || substr(substr(modified_at, 12), 1, instr(substr(modified_at, 12), ' ') - 1) || substr(substr(modified_at, 12), instr(substr(modified_at, 12), ' ') + 1, 3) || ':' || substr(substr(modified_at, 12), instr(substr(modified_at, 12), ' ') + 4, 2)
+WHERE instr(substr(modified_at, 12), ' ') > 0;
@@ -0,0 +1,26 @@
+-- Description: Full-text index over task titles, descriptions and tags kept in sync by triggers
+CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+    id UNINDEXED, -- task id
+    title,
+    description,
+    tags,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO tasks_fts(id, title, description, tags)
+SELECT id, title, IFNULL(description, ''), IFNULL(tags, '') FROM tasks;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO tasks_fts(id, title, description, tags)
+    VALUES (new.id, new.title, IFNULL(new.description, ''), IFNULL(new.tags, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF title, description, tags ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+    INSERT INTO tasks_fts(id, title, description, tags)
+    VALUES (new.id, new.title, IFNULL(new.description, ''), IFNULL(new.tags, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+END;
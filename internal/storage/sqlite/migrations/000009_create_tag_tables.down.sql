@@ -0,0 +1,38 @@
+
+DROP TRIGGER IF EXISTS tags_fts_update;
+DROP TRIGGER IF EXISTS task_tags_fts_delete;
+DROP TRIGGER IF EXISTS task_tags_fts_insert;
+DROP TRIGGER IF EXISTS tasks_fts_delete;
+DROP TRIGGER IF EXISTS tasks_fts_update;
+DROP TRIGGER IF EXISTS tasks_fts_insert;
+
+ALTER TABLE tasks ADD COLUMN tags TEXT; -- tags will be a comma separated list of tags
+UPDATE tasks SET tags = (
+    SELECT group_concat(name, ',') FROM (
+        SELECT tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+        WHERE task_tags.task_id = tasks.id ORDER BY task_tags.position
+    )
+);
+
+DROP INDEX IF EXISTS idx_task_tags_tag_id;
+DROP TABLE IF EXISTS task_tags;
+DROP TABLE IF EXISTS tags;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO tasks_fts(id, title, description, tags)
+    VALUES (new.id, new.title, IFNULL(new.description, ''), IFNULL(new.tags, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF title, description, tags ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+    INSERT INTO tasks_fts(id, title, description, tags)
+    VALUES (new.id, new.title, IFNULL(new.description, ''), IFNULL(new.tags, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+END;
+
+DELETE FROM tasks_fts;
+INSERT INTO tasks_fts(id, title, description, tags)
+SELECT id, title, IFNULL(description, ''), IFNULL(tags, '') FROM tasks;
@@ -0,0 +1,98 @@
+-- Description: Move tags from the comma separated tasks.tags column into their own tables
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY, -- UUID
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    color TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id TEXT NOT NULL,
+    tag_id TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0, -- order of the tag on the task
+    PRIMARY KEY (task_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id);
+
+INSERT OR IGNORE INTO tags(id, name, color, created_at)
+WITH RECURSIVE split(task_id, name, rest, position) AS (
+    SELECT id, '', tags || ',', -1 FROM tasks WHERE IFNULL(tags, '') != ''
+    UNION ALL
+    SELECT task_id, trim(substr(rest, 1, instr(rest, ',') - 1)), substr(rest, instr(rest, ',') + 1), position + 1
+    FROM split WHERE rest != ''
+)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(6))),
+       name,
+       '',
+       strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+FROM (SELECT DISTINCT name FROM split WHERE name != '');
+
+INSERT OR IGNORE INTO task_tags(task_id, tag_id, position)
+WITH RECURSIVE split(task_id, name, rest, position) AS (
+    SELECT id, '', tags || ',', -1 FROM tasks WHERE IFNULL(tags, '') != ''
+    UNION ALL
+    SELECT task_id, trim(substr(rest, 1, instr(rest, ',') - 1)), substr(rest, instr(rest, ',') + 1), position + 1
+    FROM split WHERE rest != ''
+)
+SELECT split.task_id, tags.id, split.position
+FROM split JOIN tags ON tags.name = split.name
+WHERE split.name != '';
+
+-- the search index triggers reference tasks.tags, so they are recreated on top of the new tables
+DROP TRIGGER IF EXISTS tasks_fts_insert;
+DROP TRIGGER IF EXISTS tasks_fts_update;
+DROP TRIGGER IF EXISTS tasks_fts_delete;
+ALTER TABLE tasks DROP COLUMN tags;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO tasks_fts(id, title, description, tags)
+    VALUES (new.id, new.title, IFNULL(new.description, ''), '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF title, description ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+    INSERT INTO tasks_fts(id, title, description, tags)
+    SELECT id, title, IFNULL(description, ''), IFNULL((
+        SELECT group_concat(tags.name, ' ') FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id
+    ), '')
+    FROM tasks WHERE id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM tasks_fts WHERE id = old.id;
+    DELETE FROM task_tags WHERE task_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_tags_fts_insert AFTER INSERT ON task_tags BEGIN
+    DELETE FROM tasks_fts WHERE id = new.task_id;
+    INSERT INTO tasks_fts(id, title, description, tags)
+    SELECT id, title, IFNULL(description, ''), IFNULL((
+        SELECT group_concat(tags.name, ' ') FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id
+    ), '')
+    FROM tasks WHERE id = new.task_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_tags_fts_delete AFTER DELETE ON task_tags BEGIN
+    DELETE FROM tasks_fts WHERE id = old.task_id;
+    INSERT INTO tasks_fts(id, title, description, tags)
+    SELECT id, title, IFNULL(description, ''), IFNULL((
+        SELECT group_concat(tags.name, ' ') FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id
+    ), '')
+    FROM tasks WHERE id = old.task_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS tags_fts_update AFTER UPDATE OF name ON tags BEGIN
+    DELETE FROM tasks_fts WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = new.id);
+    INSERT INTO tasks_fts(id, title, description, tags)
+    SELECT id, title, IFNULL(description, ''), IFNULL((
+        SELECT group_concat(tags.name, ' ') FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id
+    ), '')
+    FROM tasks WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = new.id);
+END;
+
+DELETE FROM tasks_fts;
+INSERT INTO tasks_fts(id, title, description, tags)
+SELECT id, title, IFNULL(description, ''), IFNULL((
+    SELECT group_concat(tags.name, ' ') FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id
+), '')
+FROM tasks;
@@ -0,0 +1,3 @@
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DROP INDEX idx_tasks_deleted_at;
+ALTER TABLE tasks DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+-- tasks moved to the trash keep the time they were deleted at, NULL for live tasks
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_tasks_deleted_at ON tasks(deleted_at);
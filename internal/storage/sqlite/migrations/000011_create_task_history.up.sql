@@ -0,0 +1,9 @@
+-- undo/redo history of task mutations, snapshots are stored as JSON documents
+CREATE TABLE IF NOT EXISTS task_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL, -- create, update or delete
+    before TEXT, -- task before the mutation, NULL for create
+    after TEXT, -- task after the mutation, NULL for delete
+    undone BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS task_events_delete;
+DROP INDEX IF EXISTS idx_task_events_task_id;
+DROP TABLE IF EXISTS task_events;
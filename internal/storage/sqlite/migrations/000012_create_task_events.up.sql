@@ -0,0 +1,14 @@
+-- audit log of task changes, changes is a JSON array of {field, old, new}
+CREATE TABLE IF NOT EXISTS task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL,
+    action TEXT NOT NULL, -- created, updated, deleted or restored
+    changes TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events(task_id);
+
+-- the log of a task goes away once the task is deleted permanently
+CREATE TRIGGER IF NOT EXISTS task_events_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM task_events WHERE task_id = old.id;
+END;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS reminders_delete;
+DROP INDEX IF EXISTS idx_reminders_task_id;
+DROP TABLE IF EXISTS reminders;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id TEXT PRIMARY KEY, -- UUID
+    task_id TEXT NOT NULL,
+    at TIMESTAMP, -- absolute time, NULL for reminders relative to the due date
+    before_minutes INTEGER NOT NULL DEFAULT 0,
+    fire_at TIMESTAMP, -- when the reminder goes off, NULL while a relative reminder has no due date
+    fired_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_reminders_task_id ON reminders(task_id);
+
+CREATE TRIGGER IF NOT EXISTS reminders_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM reminders WHERE task_id = old.id;
+END;
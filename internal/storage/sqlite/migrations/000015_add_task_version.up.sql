@@ -0,0 +1,2 @@
+-- incremented on every change, an update based on an older version is rejected as a conflict
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
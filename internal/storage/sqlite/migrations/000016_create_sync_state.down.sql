@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sync_fields;
+DROP TABLE IF EXISTS sync_state;
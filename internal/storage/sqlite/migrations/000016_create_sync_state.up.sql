@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS sync_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL -- JSON
+);
+
+CREATE TABLE IF NOT EXISTS sync_fields (
+    entity TEXT NOT NULL,
+    id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    value TEXT NOT NULL, -- JSON
+    timestamp TEXT NOT NULL, -- hybrid logical clock, see domain.HLC
+    PRIMARY KEY (entity, id, field)
+);
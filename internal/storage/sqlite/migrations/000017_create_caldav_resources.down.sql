@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS caldav_resources;
+DELETE FROM sync_state WHERE key IN ('caldav_account', 'caldav_sync_token');
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS caldav_resources (
+    task_id TEXT PRIMARY KEY,
+    href TEXT NOT NULL,
+    etag TEXT NOT NULL,
+    version INTEGER NOT NULL -- of the task when it was last synced
+);
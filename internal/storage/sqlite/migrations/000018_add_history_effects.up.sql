@@ -0,0 +1,3 @@
+-- JSON array of the entries made along with the mutation, e.g. the subtasks completed with a task
+-- and the next occurrence of a recurring task, they are undone and redone together with it
+ALTER TABLE task_history ADD COLUMN effects TEXT;
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"time"
+)
+
+const projectColumns = `id, name, color, archived, sort_order, created_at, modified_at`
+
+func scanProject(row rowScanner) (domain.Project, error) {
+	var project domain.Project
+	err := row.Scan(
+		&project.ID,
+		&project.Name,
+		&project.Color,
+		&project.Archived,
+		&project.SortOrder,
+		&project.CreatedAt,
+		&project.ModifiedAt,
+	)
+	return project, err
+}
+
+func (s Storage) GetAllProjects(ctx context.Context) ([]domain.Project, error) {
+	const op = "storage.sqlite.project.get_all"
+
+	stmt, err := s.db.Prepare(`SELECT ` + projectColumns + ` FROM projects ORDER BY sort_order, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var projects []domain.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+func (s Storage) GetProjectByID(ctx context.Context, id string) (domain.Project, error) {
+	const op = "storage.sqlite.project.get_by_id"
+
+	stmt, err := s.db.Prepare(`SELECT ` + projectColumns + ` FROM projects WHERE id = ?`)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	project, err := scanProject(stmt.QueryRowContext(ctx, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Project{}, fmt.Errorf("%s: %w", op, domain.ErrProjectNotFound)
+		}
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return project, nil
+}
+
+func (s Storage) CreateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	const op = "storage.sqlite.project.create"
+
+	stmt, err := s.db.Prepare(`INSERT INTO projects(id, name, color, archived, sort_order, created_at, modified_at) VALUES(?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(
+		ctx,
+		project.ID,
+		project.Name,
+		project.Color,
+		project.Archived,
+		project.SortOrder,
+		project.CreatedAt,
+		project.ModifiedAt,
+	)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return project, nil
+}
+
+func (s Storage) UpdateProject(ctx context.Context, project domain.Project) (domain.Project, error) {
+	const op = "storage.sqlite.project.update"
+
+	stmt, err := s.db.Prepare(`UPDATE projects SET name = ?, color = ?, archived = ?, sort_order = ?, modified_at = ? WHERE id = ?`)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(
+		ctx,
+		project.Name,
+		project.Color,
+		project.Archived,
+		project.SortOrder,
+		time.Now(),
+		project.ID,
+	)
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.Project{}, fmt.Errorf("%s: %w", op, domain.ErrProjectNotFound)
+	}
+
+	return project, nil
+}
+
+// DeleteProject deletes the project and, depending on deleteTasks, either moves its tasks
+// together with their subtasks to the trash or moves them to the inbox. Trashed tasks are detached
+// from the project, so restoring them puts them into the inbox.
+func (s Storage) DeleteProject(ctx context.Context, id string, deleteTasks bool) error {
+	const op = "storage.sqlite.project.delete"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if deleteTasks {
+		ids, err := liveSubtree(ctx, tx, `project_id = ? AND parent_id IS NULL`, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := trashTasks(ctx, tx, ids, time.Now()); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM tasks WHERE project_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := setTasksProject(ctx, tx, ids, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrProjectNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"strings"
+	"time"
+)
+
+// taskSortExpressions maps sort keys to the SQL expressions tasks are ordered by.
+// Timestamps are compared through julianday so values written with different time zones order correctly,
+// tasks without a due date are placed after the ones that have it.
+var taskSortExpressions = map[domain.TaskSortKey]string{
+	domain.TaskSortCreatedAt:  `julianday(created_at)`,
+	domain.TaskSortModifiedAt: `julianday(modified_at)`,
+	domain.TaskSortDueDate:    `IFNULL(julianday(due_date), 9999999)`,
+	domain.TaskSortPriority:   `CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END`,
+	domain.TaskSortTitle:      `lower(title)`,
+	domain.TaskSortPosition:   `position`,
+}
+
+// taskCursor points right after the last task of a page.
+type taskCursor struct {
+	Value any    `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeTaskCursor(cursor taskCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeTaskCursor(s string) (taskCursor, error) {
+	var cursor taskCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return taskCursor{}, domain.NewValidationError("cursor", domain.ValidationInvalid, "is malformed")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return taskCursor{}, domain.NewValidationError("cursor", domain.ValidationInvalid, "is malformed")
+	}
+	return cursor, nil
+}
+
+// ListTasks returns a page of tasks matching the query. The query is expected to be validated by the caller,
+// only the cursor is checked here since its format is private to the storage.
+func (s Storage) ListTasks(ctx context.Context, query domain.TaskQuery) (domain.TaskPage, error) {
+	const op = "storage.sqlite.task.list"
+
+	sortBy := query.SortBy
+	if sortBy == "" {
+		sortBy = domain.TaskSortCreatedAt
+	}
+	sortExpr, ok := taskSortExpressions[sortBy]
+	if !ok {
+		return domain.TaskPage{}, fmt.Errorf("%s: %w", op, domain.NewValidationError("sort_by", domain.ValidationInvalid, fmt.Sprintf("unknown sort key %q", sortBy)))
+	}
+	direction, comparison := "ASC", ">"
+	if query.SortDirection == domain.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = domain.DefaultTaskQueryLimit
+	}
+
+	where, args := taskQueryConditions(query)
+	if query.Cursor != "" {
+		cursor, err := decodeTaskCursor(query.Cursor)
+		if err != nil {
+			return domain.TaskPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		where = append(where, fmt.Sprintf(`(%s, id) %s (?, ?)`, sortExpr, comparison))
+		args = append(args, cursor.Value, cursor.ID)
+	}
+
+	stmt := `SELECT ` + taskColumns + `, ` + sortExpr + ` FROM tasks`
+	stmt += ` WHERE ` + strings.Join(where, ` AND `)
+	stmt += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT ?`, sortExpr, direction, direction)
+	// one extra row tells whether there is a next page
+	args = append(args, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return domain.TaskPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var page domain.TaskPage
+	var lastSortValue any
+	for rows.Next() {
+		if len(page.Tasks) == limit {
+			last := page.Tasks[len(page.Tasks)-1]
+			page.NextCursor, err = encodeTaskCursor(taskCursor{Value: lastSortValue, ID: last.ID})
+			if err != nil {
+				return domain.TaskPage{}, fmt.Errorf("%s: %w", op, err)
+			}
+			break
+		}
+
+		task, err := scanTask(rows, &lastSortValue)
+		if err != nil {
+			return domain.TaskPage{}, fmt.Errorf("%s: %w", op, err)
+		}
+		page.Tasks = append(page.Tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.TaskPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return page, nil
+}
+
+// taskQueryConditions translates the filters of the query into SQL conditions joined with AND.
+func taskQueryConditions(query domain.TaskQuery) ([]string, []any) {
+	where := []string{`deleted_at IS NULL`}
+	var args []any
+
+	switch {
+	case query.ParentID != nil:
+		where = append(where, `parent_id = ?`)
+		args = append(args, *query.ParentID)
+	case !query.IncludeSubtasks:
+		where = append(where, `parent_id IS NULL`)
+	}
+	if query.ProjectID != nil {
+		where = append(where, `IFNULL(project_id, '') = ?`)
+		args = append(args, *query.ProjectID)
+	}
+	if len(query.Statuses) > 0 {
+		where = append(where, `status IN (`+placeholders(len(query.Statuses))+`)`)
+		for _, status := range query.Statuses {
+			args = append(args, status)
+		}
+	}
+	if len(query.Priorities) > 0 {
+		where = append(where, `priority IN (`+placeholders(len(query.Priorities))+`)`)
+		for _, priority := range query.Priorities {
+			args = append(args, priority)
+		}
+	}
+	for _, tag := range query.Tags {
+		where = append(where, `EXISTS (
+			SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+			WHERE task_tags.task_id = tasks.id AND tags.name = ?
+		)`)
+		args = append(args, tag)
+	}
+
+	ranges := []struct {
+		column string
+		op     string
+		value  *time.Time
+	}{
+		{"due_date", ">=", query.DueAfter},
+		{"due_date", "<=", query.DueBefore},
+		{"created_at", ">=", query.CreatedAfter},
+		{"created_at", "<=", query.CreatedBefore},
+		{"modified_at", ">=", query.ModifiedAfter},
+		{"modified_at", "<=", query.ModifiedBefore},
+	}
+	for _, r := range ranges {
+		if r.value == nil {
+			continue
+		}
+		where = append(where, fmt.Sprintf(`julianday(%s) %s julianday(?)`, r.column, r.op))
+		args = append(args, *r.value)
+	}
+
+	return where, args
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"time"
+)
+
+// replaceTaskReminders sets the reminders of the task, storing when each of them goes off.
+func replaceTaskReminders(ctx context.Context, tx *sql.Tx, task domain.Task) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM reminders WHERE task_id = ?`, task.ID); err != nil {
+		return err
+	}
+
+	for _, reminder := range task.Reminders {
+		var fireAt *time.Time
+		if at, ok := reminder.FireAt(task.DueDate); ok {
+			fireAt = &at
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO reminders(id, task_id, at, before_minutes, fire_at, fired_at) VALUES(?, ?, ?, ?, ?, ?)`,
+			reminder.ID,
+			task.ID,
+			reminder.At,
+			reminder.BeforeMinutes,
+			fireAt,
+			reminder.FiredAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pendingReminders joins the reminders that have not gone off yet with their unfinished tasks outside the trash.
+const pendingReminders = `reminders JOIN tasks ON tasks.id = reminders.task_id
+	WHERE reminders.fired_at IS NULL AND reminders.fire_at IS NOT NULL
+		AND tasks.deleted_at IS NULL AND tasks.status != 'done'`
+
+// GetDueReminders returns the pending reminders that go off until the given time, earliest first.
+func (s Storage) GetDueReminders(ctx context.Context, until time.Time) ([]domain.ReminderNotification, error) {
+	const op = "storage.sqlite.reminder.get_due"
+
+	stmt, err := s.db.Prepare(`SELECT reminders.id, tasks.id, tasks.title, tasks.due_date, reminders.fire_at
+		FROM ` + pendingReminders + ` AND julianday(reminders.fire_at) <= julianday(?)
+		ORDER BY julianday(reminders.fire_at)`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, until)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.ReminderNotification
+	for rows.Next() {
+		var notification domain.ReminderNotification
+		err := rows.Scan(
+			&notification.ReminderID,
+			&notification.TaskID,
+			&notification.Title,
+			&notification.DueDate,
+			&notification.FireAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+// GetNextReminderTime returns when the earliest pending reminder goes off, nil when there is none.
+func (s Storage) GetNextReminderTime(ctx context.Context) (*time.Time, error) {
+	const op = "storage.sqlite.reminder.get_next_time"
+
+	var fireAt time.Time
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT reminders.fire_at FROM `+pendingReminders+` ORDER BY julianday(reminders.fire_at) LIMIT 1`,
+	).Scan(&fireAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &fireAt, nil
+}
+
+func (s Storage) MarkReminderFired(ctx context.Context, id string, firedAt time.Time) error {
+	const op = "storage.sqlite.reminder.mark_fired"
+
+	res, err := s.db.ExecContext(ctx, `UPDATE reminders SET fired_at = ? WHERE id = ?`, firedAt, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, domain.ErrReminderNotFound)
+	}
+
+	return nil
+}
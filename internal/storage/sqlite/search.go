@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"strings"
+)
+
+// searchColumns are the tasks_fts columns a search term can be scoped to.
+var searchColumns = map[domain.SearchField]string{
+	domain.SearchFieldAny:         `{title description tags}`,
+	domain.SearchFieldTitle:       `title`,
+	domain.SearchFieldDescription: `description`,
+	domain.SearchFieldTags:        `tags`,
+}
+
+// matchExpression renders the terms as an FTS5 query. Every term is quoted,
+// so FTS5 operators typed by the user are searched for literally.
+func matchExpression(terms []domain.SearchTerm) (string, error) {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		column, ok := searchColumns[term.Field]
+		if !ok {
+			return "", domain.NewValidationError("query", domain.ValidationInvalid, fmt.Sprintf("unknown search field %q", term.Field))
+		}
+		phrase := `"` + strings.ReplaceAll(term.Text, `"`, `""`) + `"`
+		if term.Prefix {
+			phrase += ` *`
+		}
+		parts = append(parts, column+` : `+phrase)
+	}
+	return strings.Join(parts, ` AND `), nil
+}
+
+// Search returns the tasks matching every term of the query ordered by relevance,
+// matches in the title weigh more than matches in the tags, which weigh more than the description.
+func (s Storage) Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchResult, error) {
+	const op = "storage.sqlite.task.search"
+
+	match, err := matchExpression(query.Terms)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := s.db.Prepare(`
+		SELECT ` + taskColumns + `,
+			highlight(tasks_fts, 1, ?, ?),
+			snippet(tasks_fts, 2, ?, ?, '…', 16),
+			bm25(tasks_fts, 0, 10.0, 1.0, 5.0) AS rank
+		FROM tasks_fts JOIN tasks ON tasks.id = tasks_fts.id
+		WHERE tasks_fts MATCH ? AND tasks.deleted_at IS NULL
+		ORDER BY rank
+		LIMIT ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(
+		ctx,
+		domain.SearchMatchStart, domain.SearchMatchEnd,
+		domain.SearchMatchStart, domain.SearchMatchEnd,
+		match,
+		query.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		var result domain.SearchResult
+		result.Task, err = scanTask(rows, &result.Title, &result.Snippet, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		// snippet returns the beginning of the column even when only other columns matched
+		if !strings.Contains(result.Snippet, domain.SearchMatchStart) {
+			result.Snippet = ""
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// GetSettings returns the settings, the ones that were never changed have their default value.
+// Every setting is kept as a JSON value in its own row, so settings added later need no migration.
+func (s Storage) GetSettings(ctx context.Context) (domain.Settings, error) {
+	const op = "storage.sqlite.settings.get"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM settings`)
+	if err != nil {
+		return domain.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return domain.Settings{}, fmt.Errorf("%s: %w", op, err)
+		}
+		values[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return domain.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+	settings := domain.DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return domain.Settings{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings saves every setting, replacing the stored values.
+func (s Storage) UpdateSettings(ctx context.Context, settings domain.Settings) error {
+	const op = "storage.sqlite.settings.update"
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for key, value := range values {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO settings(key, value) VALUES(?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+			key,
+			string(value),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -8,10 +8,6 @@ import (
 	"fmt"
 	"github.com/ARUMANDESU/todo-app/internal/domain"
 	_ "github.com/golang-migrate/migrate/source/file"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
-	"log"
 	_ "modernc.org/sqlite"
 	"os"
 	"path/filepath"
@@ -22,144 +18,128 @@ import (
 var migrationsFs embed.FS
 
 type Storage struct {
-	db *sql.DB
+	db   *sql.DB
+	path string // of the database file
 }
 
-func getDataSource() string {
-	cacheDir, _ := os.UserCacheDir()
-	dataDir := filepath.Join(cacheDir, "todo-app")
-	os.MkdirAll(dataDir, os.FileMode(0755))
+// dataSourceParams makes the driver write timestamps in a format understood by the SQLite date functions.
+// The todo CLI and the background jobs of the app write to the same file, so a connection waits up to
+// 5 seconds for a lock held by another one, and transactions take the write lock when they begin instead
+// of failing when they upgrade a read lock.
+const dataSourceParams = "?_time_format=sqlite&_txlock=immediate&_pragma=busy_timeout(5000)"
 
-	// if file is not found, it will be created automatically
-	if _, err := os.Stat(filepath.Join(dataDir, "tasks.db")); os.IsNotExist(err) {
-		file, err := os.Create(filepath.Join(dataDir, "tasks.db"))
-		if err != nil {
-			log.Fatal(err)
-		}
-		file.Close()
+// prepareDataSource creates the database file and its directory when they do not exist yet.
+func prepareDataSource(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755)); err != nil {
+		return err
 	}
 
-	return filepath.Join(dataDir, "tasks.db")
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		return err
+	}
+	return file.Close()
 }
 
-func NewStorage() (*Storage, error) {
-	if err := migrateSchema(nil); err != nil {
-		return nil, fmt.Errorf("failed to perform migrations: %w", err)
+// NewStorage opens the database at the path, creating it when it does not exist, and migrates its schema.
+func NewStorage(path string) (*Storage, error) {
+	if err := prepareDataSource(path); err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
 	}
-	db, err := sql.Open("sqlite", getDataSource())
+	if err := migrateSchema(path); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMigration, err)
+	}
+	db, err := sql.Open("sqlite", path+dataSourceParams)
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite connection: %w", err)
 	}
 
-	// Set the busy timeout to 5 seconds
 	db.SetConnMaxLifetime(time.Minute * 5)
 	db.SetMaxOpenConns(1)
 	db.SetMaxIdleConns(1)
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, path: path}, nil
 }
 
-func migrateSchema(nSteps *int) error {
-	db, err := sql.Open("sqlite", getDataSource())
-	if err != nil {
-		return fmt.Errorf("open sqlite connection: %w", err)
-	}
-
-	migrateDriver, err := sqlite.WithInstance(db, &sqlite.Config{
-		MigrationsTable: "migrations",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
-	}
-	srcDriver, err := iofs.New(migrationsFs, "migrations")
-	if err != nil {
-		return fmt.Errorf("failed to create migration source driver: %w", err)
-	}
-	preparedMigrations, err := migrate.NewWithInstance(
-		"iofs",
-		srcDriver,
-		"",
-		migrateDriver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migration tooling instance: %w", err)
-	}
-	defer func() {
-		preparedMigrations.Close()
-		db.Close()
-	}()
-	if nSteps != nil {
-		fmt.Printf("stepping migrations %d...\n", *nSteps)
-		err = preparedMigrations.Steps(*nSteps)
-	} else {
-		err = preparedMigrations.Up()
-	}
-
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to apply migrations: %w", err)
-	}
-
-	log.Println("Successfully applied db migrations")
-	return nil
+// Close closes the database.
+func (s Storage) Close() error {
+	return s.db.Close()
 }
 
-func (s Storage) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
-	const op = "storage.sqlite.task.get_all"
+// Path returns the path of the database file.
+func (s Storage) Path() string {
+	return s.path
+}
 
-	stmt, err := s.db.Prepare(`SELECT id, title, status, priority, due_date, created_at, modified_at, description, tags FROM tasks`)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+// taskColumns selects a task from the tasks table, tags are collected from task_tags and reminders
+// from reminders as JSON arrays.
+const taskColumns = `tasks.id, tasks.project_id, tasks.parent_id, tasks.position, tasks.title, tasks.status, tasks.priority,
+	tasks.due_date, tasks.recurrence, tasks.created_at, tasks.modified_at, tasks.deleted_at, tasks.description, tasks.version,
+	(SELECT json_group_array(name) FROM (
+		SELECT tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id ORDER BY task_tags.position
+	)),
+	(SELECT json_group_array(json(reminder)) FROM (
+		SELECT json_object(
+			'id', reminders.id,
+			'at', strftime('%Y-%m-%dT%H:%M:%fZ', reminders.at),
+			'before_minutes', reminders.before_minutes,
+			'fired_at', strftime('%Y-%m-%dT%H:%M:%fZ', reminders.fired_at)
+		) AS reminder
+		FROM reminders WHERE reminders.task_id = tasks.id ORDER BY reminders.rowid
+	))`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	rows, err := stmt.QueryContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+// scanTask reads a row selected with taskColumns, extra receives any columns selected after them.
+func scanTask(row rowScanner, extra ...any) (domain.Task, error) {
+	var task domain.Task
+	dest := []any{
+		&task.ID,
+		&task.ProjectID,
+		&task.ParentID,
+		&task.Position,
+		&task.Title,
+		&task.Status,
+		&task.Priority,
+		&task.DueDate,
+		&task.Recurrence,
+		&task.CreatedAt,
+		&task.ModifiedAt,
+		&task.DeletedAt,
+		&task.Description,
+		&task.Version,
+		&task.Tags,
+		&task.Reminders,
 	}
-	defer rows.Close()
+	err := row.Scan(append(dest, extra...)...)
+	return task, err
+}
 
+func scanTasks(rows *sql.Rows) ([]domain.Task, error) {
 	var tasks []domain.Task
 	for rows.Next() {
-		var task domain.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.ModifiedAt,
-			&task.Description,
-			&task.Tags,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return nil, err
 		}
 		tasks = append(tasks, task)
 	}
 
-	return tasks, nil
+	return tasks, rows.Err()
 }
 
 func (s Storage) GetTaskByID(ctx context.Context, id string) (domain.Task, error) {
 	const op = "storage.sqlite.task.get_by_id"
 
-	stmt, err := s.db.Prepare(`SELECT id, title, status, priority, due_date, created_at, modified_at, description, tags  FROM tasks WHERE id = ?`)
+	stmt, err := s.db.Prepare(`SELECT ` + taskColumns + ` FROM tasks WHERE id = ? AND deleted_at IS NULL`)
 	if err != nil {
 		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var task domain.Task
-	err = stmt.QueryRowContext(ctx, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Status,
-		&task.Priority,
-		&task.DueDate,
-		&task.CreatedAt,
-		&task.ModifiedAt,
-		&task.Description,
-		&task.Tags,
-	)
+	task, err := scanTask(stmt.QueryRowContext(ctx, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Task{}, fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
@@ -170,87 +150,370 @@ func (s Storage) GetTaskByID(ctx context.Context, id string) (domain.Task, error
 	return task, nil
 }
 
+func (s Storage) GetChildren(ctx context.Context, parentID string) ([]domain.Task, error) {
+	const op = "storage.sqlite.task.get_children"
+
+	stmt, err := s.db.Prepare(`SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = ? AND deleted_at IS NULL ORDER BY position, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tasks, nil
+}
+
+// CreateTask inserts the task. A subtask is placed after the other subtasks of its parent, the ones in the trash
+// included, so it does not share a position with a subtask that is restored later.
 func (s Storage) CreateTask(ctx context.Context, task domain.Task) (domain.Task, error) {
 	const op = "storage.sqlite.task.create"
 
-	stmt, err := s.db.Prepare(`INSERT INTO tasks(id, title, status, priority, due_date, created_at, modified_at) VALUES(?, ?, ?, ?, ?, ?, ?)`)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	_, err = stmt.ExecContext(
+	task, err = createTask(ctx, tx, task)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, nil
+}
+
+// createTask inserts the task within tx, see CreateTask.
+func createTask(ctx context.Context, tx *sql.Tx, task domain.Task) (domain.Task, error) {
+	task.Version = 1
+	if task.ParentID != nil {
+		err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), -1) + 1 FROM tasks WHERE parent_id = ?`, *task.ParentID).Scan(&task.Position)
+		if err != nil {
+			return domain.Task{}, err
+		}
+	}
+	_, err := tx.ExecContext(
 		ctx,
+		`INSERT INTO tasks(id, project_id, parent_id, position, title, status, priority, due_date, recurrence, created_at, modified_at, description, version) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		task.ID,
+		task.ProjectID,
+		task.ParentID,
+		task.Position,
 		task.Title,
 		task.Status,
 		task.Priority,
 		task.DueDate,
+		task.Recurrence,
 		task.CreatedAt,
 		task.ModifiedAt,
+		task.Description,
+		task.Version,
 	)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+		return domain.Task{}, err
+	}
+
+	if err := replaceTaskTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := replaceTaskReminders(ctx, tx, task); err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := insertTaskEvent(ctx, tx, task.ID, domain.TaskEventCreated, diffTasks(domain.Task{}, task), task.CreatedAt); err != nil {
+		return domain.Task{}, err
 	}
 
 	return task, nil
 }
 
+// UpdateTask saves the task and records the fields that changed in its audit log. The task must have the
+// version stored in the database, otherwise it was changed in the meantime and ErrConflict is returned.
+// The returned task has the new version.
 func (s Storage) UpdateTask(ctx context.Context, task domain.Task) (domain.Task, error) {
 	const op = "storage.sqlite.task.update"
 
-	stmt, err := s.db.Prepare(`UPDATE tasks SET title = ?, status = ?, priority = ?, due_date = ?, modified_at = ?,description = ?, tags =? WHERE id = ?`)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.ExecContext(
+	task, err = updateTask(ctx, tx, task)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, nil
+}
+
+// CompleteTask saves the completed task together with the subtasks completed along with it and creates the next
+// occurrence of a recurring task. The updates are version checked as in UpdateTask and either all or none of
+// the changes are saved.
+func (s Storage) CompleteTask(ctx context.Context, completion domain.TaskCompletion) (domain.TaskCompletion, error) {
+	const op = "storage.sqlite.task.complete"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.TaskCompletion{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	saved := domain.TaskCompletion{Children: make([]domain.Task, 0, len(completion.Children))}
+	if saved.Task, err = updateTask(ctx, tx, completion.Task); err != nil {
+		return domain.TaskCompletion{}, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, child := range completion.Children {
+		child, err = updateTask(ctx, tx, child)
+		if err != nil {
+			return domain.TaskCompletion{}, fmt.Errorf("%s: %w", op, err)
+		}
+		saved.Children = append(saved.Children, child)
+	}
+	if completion.Next != nil {
+		next, err := createTask(ctx, tx, *completion.Next)
+		if err != nil {
+			return domain.TaskCompletion{}, fmt.Errorf("%s: %w", op, err)
+		}
+		saved.Next = &next
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.TaskCompletion{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
+
+// updateTask saves the task within tx, see UpdateTask.
+func updateTask(ctx context.Context, tx *sql.Tx, task domain.Task) (domain.Task, error) {
+	previous, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ? AND deleted_at IS NULL`, task.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Task{}, domain.ErrTaskNotFound
+		}
+		return domain.Task{}, err
+	}
+	if previous.Version != task.Version {
+		return domain.Task{}, domain.ErrConflict
+	}
+
+	modifiedAt := time.Now()
+	res, err := tx.ExecContext(
 		ctx,
+		`UPDATE tasks SET project_id = ?, title = ?, status = ?, priority = ?, due_date = ?, recurrence = ?, modified_at = ?,description = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NULL AND version = ?`,
+		task.ProjectID,
 		task.Title,
 		task.Status,
 		task.Priority,
 		task.DueDate,
-		time.Now(),
+		task.Recurrence,
+		modifiedAt,
 		task.Description,
-		task.Tags.Value(),
 		task.ID,
+		task.Version,
 	)
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+		return domain.Task{}, err
 	}
 
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+		return domain.Task{}, err
 	}
 
 	if rowsAffected == 0 {
-		return domain.Task{}, fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
+		return domain.Task{}, domain.ErrConflict
+	}
+	task.Version++
+
+	if err := replaceTaskTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := replaceTaskReminders(ctx, tx, task); err != nil {
+		return domain.Task{}, err
+	}
+
+	if changes := diffTasks(previous, task); len(changes) > 0 {
+		if err := insertTaskEvent(ctx, tx, task.ID, domain.TaskEventUpdated, changes, modifiedAt); err != nil {
+			return domain.Task{}, err
+		}
+	}
+
+	return task, nil
+}
+
+// MoveTask places the task under parentID (or at the top level when parentID is nil)
+// at the given position, closing the gap it leaves among its old siblings and shifting
+// the new siblings that follow it. A position past the last sibling places the task last.
+// A task moved under a parent, together with its subtasks, takes the project of the parent.
+// Tasks in the trash keep their positions.
+func (s Storage) MoveTask(ctx context.Context, id string, parentID *string, position int) (domain.Task, error) {
+	const op = "storage.sqlite.task.move"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	previous, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ? AND deleted_at IS NULL`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Task{}, fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
+		}
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	projectID := previous.ProjectID
+	if parentID != nil {
+		err := tx.QueryRowContext(ctx, `SELECT project_id FROM tasks WHERE id = ? AND deleted_at IS NULL`, *parentID).Scan(&projectID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.Task{}, fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
+			}
+			return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	var siblings int
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM tasks WHERE parent_id IS ? AND id != ? AND deleted_at IS NULL`,
+		parentID,
+		id,
+	).Scan(&siblings)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+	position = min(position, siblings)
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE tasks SET position = position - 1 WHERE parent_id IS ? AND position > ? AND id != ? AND deleted_at IS NULL`,
+		previous.ParentID,
+		previous.Position,
+		id,
+	)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE tasks SET position = position + 1 WHERE parent_id IS ? AND position >= ? AND id != ? AND deleted_at IS NULL`,
+		parentID,
+		position,
+		id,
+	)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	modifiedAt := time.Now()
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE tasks SET parent_id = ?, project_id = ?, position = ?, modified_at = ?, version = version + 1 WHERE id = ?`,
+		parentID,
+		projectID,
+		position,
+		modifiedAt,
+		id,
+	)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	task, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if changes := append(diffTasks(previous, task), diffPlacement(previous, task)...); len(changes) > 0 {
+		if err := insertTaskEvent(ctx, tx, id, domain.TaskEventUpdated, changes, modifiedAt); err != nil {
+			return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	descendants, err := liveSubtree(ctx, tx, `parent_id = ?`, id)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := setTasksProject(ctx, tx, descendants, task.ProjectID); err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return task, nil
 }
 
+// setTasksProject moves the tasks into the project, or the inbox when projectID is nil.
+func setTasksProject(ctx context.Context, tx *sql.Tx, ids []string, projectID *string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return recordTaskChanges(ctx, tx, ids, func() error {
+		args := append([]any{projectID}, anySlice(ids)...)
+		_, err := tx.ExecContext(ctx, `UPDATE tasks SET project_id = ? WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+		return err
+	})
+}
+
+// DeleteTask moves the task together with all of its subtasks to the trash.
+// Every task of the subtree is stamped with the same deletion time, so they can be restored together.
 func (s Storage) DeleteTask(ctx context.Context, id string) error {
 	const op = "storage.sqlite.task.delete"
 
-	stmt, err := s.db.Prepare(`DELETE FROM tasks WHERE id = ?`)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.ExecContext(ctx, id)
-	if err != nil {
+	if err := deleteTask(ctx, tx, id); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %w", op, domain.ErrTaskNotFound)
+	return nil
+}
+
+// deleteTask moves the task and its subtasks to the trash within tx, see DeleteTask.
+func deleteTask(ctx context.Context, tx *sql.Tx, id string) error {
+	ids, err := liveSubtree(ctx, tx, `id = ?`, id)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	if len(ids) == 0 {
+		return domain.ErrTaskNotFound
+	}
+
+	return trashTasks(ctx, tx, ids, time.Now())
 }
@@ -0,0 +1,180 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// syncStateKeys are the rows of the sync_state table, each holds a JSON value.
+const (
+	syncDirectoryKey = "directory"
+	syncDeviceKey    = "device"
+	syncClockKey     = "clock"
+	syncOffsetsKey   = "offsets"
+)
+
+// GetSyncState returns the state of the folder sync, it is empty before the first sync.
+func (s Storage) GetSyncState(ctx context.Context) (domain.SyncState, error) {
+	const op = "storage.sqlite.sync.get_state"
+
+	state := domain.SyncState{
+		Offsets: make(map[string]int64),
+		Fields:  make(map[domain.SyncKey]domain.SyncField),
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM sync_state`)
+	if err != nil {
+		return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var target any
+		switch key {
+		case syncDirectoryKey:
+			target = &state.Directory
+		case syncDeviceKey:
+			target = &state.Device
+		case syncClockKey:
+			target = &state.Clock
+		case syncOffsetsKey:
+			target = &state.Offsets
+		default:
+			continue
+		}
+		if err := json.Unmarshal([]byte(value), target); err != nil {
+			return domain.SyncState{}, fmt.Errorf("%s: %s: %w", op, key, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT entity, id, field, value, timestamp FROM sync_fields`)
+	if err != nil {
+		return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key domain.SyncKey
+		var value, timestamp string
+		if err := rows.Scan(&key.Entity, &key.ID, &key.Field, &value, &timestamp); err != nil {
+			return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+		}
+		hlc, err := domain.ParseHLC(timestamp)
+		if err != nil {
+			return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+		}
+		state.Fields[key] = domain.SyncField{Value: json.RawMessage(value), Timestamp: hlc}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SyncState{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return state, nil
+}
+
+// SaveSyncState saves the directory, device, clock and offsets of the state and the fields with the given keys.
+func (s Storage) SaveSyncState(ctx context.Context, state domain.SyncState, changed []domain.SyncKey) error {
+	const op = "storage.sqlite.sync.save_state"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	values := map[string]any{
+		syncDirectoryKey: state.Directory,
+		syncDeviceKey:    state.Device,
+		syncClockKey:     state.Clock,
+		syncOffsetsKey:   state.Offsets,
+	}
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO sync_state(key, value) VALUES(?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+			key,
+			string(data),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, key := range changed {
+		if err := saveSyncField(ctx, tx, key, state.Fields[key]); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ApplySync writes the projects and tasks merged from the other devices in a single transaction. A task that
+// already exists must still have the version it had when the merge began, otherwise it was changed locally in
+// the meantime and ErrConflict is returned without writing anything. The returned tasks have their new versions.
+func (s Storage) ApplySync(ctx context.Context, projects []domain.Project, tasks []domain.Task) ([]domain.Task, error) {
+	const op = "storage.sqlite.sync.apply"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, project := range projects {
+		if err := upsertProject(ctx, tx, project); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	saved := make([]domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		task, written, err := upsertTask(ctx, tx, task, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !written {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrConflict)
+		}
+		saved = append(saved, task)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return saved, nil
+}
+
+func saveSyncField(ctx context.Context, tx *sql.Tx, key domain.SyncKey, field domain.SyncField) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO sync_fields(entity, id, field, value, timestamp) VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(entity, id, field) DO UPDATE SET value = excluded.value, timestamp = excluded.timestamp`,
+		key.Entity,
+		key.ID,
+		key.Field,
+		string(field.Value),
+		field.Timestamp.String(),
+	)
+	return err
+}
@@ -0,0 +1,276 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+const tagColumns = `tags.id, tags.name, tags.color, tags.created_at,
+	(SELECT COUNT(*) FROM task_tags WHERE task_tags.tag_id = tags.id)`
+
+func scanTag(row rowScanner) (domain.Tag, error) {
+	var tag domain.Tag
+	err := row.Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+		&tag.UsageCount,
+	)
+	return tag, err
+}
+
+// replaceTaskTags sets the tags of the task to the given names in order, creating the tags that do not exist yet.
+// Names are matched case-insensitively, so an existing tag keeps its original spelling.
+func replaceTaskTags(ctx context.Context, tx *sql.Tx, taskID string, names []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+
+	for position, name := range names {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO tags(id, name, color, created_at) VALUES(?, ?, '', ?) ON CONFLICT(name) DO NOTHING`,
+			uuid.NewString(),
+			name,
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT OR IGNORE INTO task_tags(task_id, tag_id, position) SELECT ?, id, ? FROM tags WHERE name = ?`,
+			taskID,
+			position,
+			name,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetAllTags returns every tag ordered by name together with the number of tasks using it.
+func (s Storage) GetAllTags(ctx context.Context) ([]domain.Tag, error) {
+	const op = "storage.sqlite.tag.get_all"
+
+	stmt, err := s.db.Prepare(`SELECT ` + tagColumns + ` FROM tags ORDER BY tags.name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tags []domain.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tags, nil
+}
+
+func (s Storage) GetTagByID(ctx context.Context, id string) (domain.Tag, error) {
+	const op = "storage.sqlite.tag.get_by_id"
+
+	return s.getTag(ctx, op, `tags.id = ?`, id)
+}
+
+// GetTagByName finds a tag by its name ignoring case.
+func (s Storage) GetTagByName(ctx context.Context, name string) (domain.Tag, error) {
+	const op = "storage.sqlite.tag.get_by_name"
+
+	return s.getTag(ctx, op, `tags.name = ?`, name)
+}
+
+func (s Storage) getTag(ctx context.Context, op, condition string, arg any) (domain.Tag, error) {
+	stmt, err := s.db.Prepare(`SELECT ` + tagColumns + ` FROM tags WHERE ` + condition)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tag, err := scanTag(stmt.QueryRowContext(ctx, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Tag{}, fmt.Errorf("%s: %w", op, domain.ErrTagNotFound)
+		}
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag, nil
+}
+
+// UpdateTag renames and recolors the tag, every task using it picks up the change.
+func (s Storage) UpdateTag(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	const op = "storage.sqlite.tag.update"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	taskIDs, err := taggedTasks(ctx, tx, []string{tag.ID})
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = recordTaskChanges(ctx, tx, taskIDs, func() error {
+		res, err := tx.ExecContext(ctx, `UPDATE tags SET name = ?, color = ? WHERE id = ?`, tag.Name, tag.Color, tag.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrTagNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Tag{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag, nil
+}
+
+// MergeTags replaces the source tags with the target tag on every task and deletes the source tags.
+func (s Storage) MergeTags(ctx context.Context, sourceIDs []string, targetID string) error {
+	const op = "storage.sqlite.tag.merge"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	taskIDs, err := taggedTasks(ctx, tx, sourceIDs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = recordTaskChanges(ctx, tx, taskIDs, func() error {
+		for _, sourceID := range sourceIDs {
+			// tasks that already have the target tag keep it at its current position
+			_, err := tx.ExecContext(
+				ctx,
+				`INSERT OR IGNORE INTO task_tags(task_id, tag_id, position) SELECT task_id, ?, position FROM task_tags WHERE tag_id = ?`,
+				targetID,
+				sourceID,
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := deleteTag(ctx, tx, sourceID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteTag removes the tag from every task and deletes it.
+func (s Storage) DeleteTag(ctx context.Context, id string) error {
+	const op = "storage.sqlite.tag.delete"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	taskIDs, err := taggedTasks(ctx, tx, []string{id})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = recordTaskChanges(ctx, tx, taskIDs, func() error {
+		return deleteTag(ctx, tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// taggedTasks returns the tasks using any of the tags, including the ones in the trash.
+func taggedTasks(ctx context.Context, tx *sql.Tx, tagIDs []string) ([]string, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT DISTINCT task_id FROM task_tags WHERE tag_id IN (`+placeholders(len(tagIDs))+`)`,
+		anySlice(tagIDs)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIDs(rows)
+}
+
+func deleteTag(ctx context.Context, tx *sql.Tx, id string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_tags WHERE tag_id = ?`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"time"
+)
+
+// ExportTasks returns every task including subtasks and the tasks in the trash, oldest first.
+func (s Storage) ExportTasks(ctx context.Context) ([]domain.Task, error) {
+	const op = "storage.sqlite.task.export"
+
+	stmt, err := s.db.Prepare(`SELECT ` + taskColumns + ` FROM tasks ORDER BY julianday(tasks.created_at), tasks.id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tasks, nil
+}
+
+// ImportData writes the projects and tasks in a single transaction, replacing the existing ones with the same ID.
+// With replace set every other task and project is deleted first.
+func (s Storage) ImportData(ctx context.Context, projects []domain.Project, tasks []domain.Task, replace bool) error {
+	const op = "storage.sqlite.import"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if replace {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tasks`); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM projects`); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, project := range projects {
+		if err := upsertProject(ctx, tx, project); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, task := range tasks {
+		if _, _, err := upsertTask(ctx, tx, task, false); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if replace {
+		// tags used by the imported tasks keep their colors, the rest go away with the replaced tasks
+		if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM task_tags)`); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func upsertProject(ctx context.Context, tx *sql.Tx, project domain.Project) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO projects(id, name, color, archived, sort_order, created_at, modified_at) VALUES(?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET name = excluded.name, color = excluded.color, archived = excluded.archived,
+			sort_order = excluded.sort_order, created_at = excluded.created_at, modified_at = excluded.modified_at`,
+		project.ID,
+		project.Name,
+		project.Color,
+		project.Archived,
+		project.SortOrder,
+		project.CreatedAt,
+		project.ModifiedAt,
+	)
+	return err
+}
+
+// upsertTask inserts the task or replaces the existing one with the same ID and records the change in the audit
+// log of the task. With checkVersion set an existing task is only replaced while it still has the version of the
+// given task. It returns the task with the version it was saved with, and whether it was written.
+func upsertTask(ctx context.Context, tx *sql.Tx, task domain.Task, checkVersion bool) (domain.Task, bool, error) {
+	previous, err := scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, task.ID))
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.Task{}, false, err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO tasks(id, project_id, parent_id, position, title, status, priority, due_date, recurrence, created_at, modified_at, deleted_at, description, version)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET project_id = excluded.project_id, parent_id = excluded.parent_id,
+			position = excluded.position, title = excluded.title, status = excluded.status, priority = excluded.priority,
+			due_date = excluded.due_date, recurrence = excluded.recurrence, created_at = excluded.created_at,
+			modified_at = excluded.modified_at, deleted_at = excluded.deleted_at, description = excluded.description,
+			version = tasks.version + 1
+		WHERE NOT ? OR tasks.version = excluded.version`,
+		task.ID,
+		task.ProjectID,
+		task.ParentID,
+		task.Position,
+		task.Title,
+		task.Status,
+		task.Priority,
+		task.DueDate,
+		task.Recurrence,
+		task.CreatedAt,
+		task.ModifiedAt,
+		task.DeletedAt,
+		task.Description,
+		max(task.Version, 1),
+		checkVersion,
+	)
+	if err != nil {
+		return domain.Task{}, false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return domain.Task{}, false, err
+	}
+	if rowsAffected == 0 {
+		return domain.Task{}, false, nil
+	}
+	task.Version = max(task.Version, 1)
+	if exists {
+		task.Version = previous.Version + 1
+	}
+
+	if err := replaceTaskTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return domain.Task{}, false, err
+	}
+
+	if err := replaceTaskReminders(ctx, tx, task); err != nil {
+		return domain.Task{}, false, err
+	}
+
+	if !exists {
+		if err := insertTaskEvent(ctx, tx, task.ID, domain.TaskEventCreated, diffTasks(domain.Task{}, task), task.CreatedAt); err != nil {
+			return domain.Task{}, false, err
+		}
+		return task, true, nil
+	}
+
+	action := domain.TaskEventUpdated
+	switch {
+	case previous.DeletedAt == nil && task.DeletedAt != nil:
+		action = domain.TaskEventDeleted
+	case previous.DeletedAt != nil && task.DeletedAt == nil:
+		action = domain.TaskEventRestored
+	}
+	if changes := diffTasks(previous, task); len(changes) > 0 || action != domain.TaskEventUpdated {
+		if err := insertTaskEvent(ctx, tx, task.ID, action, changes, time.Now()); err != nil {
+			return domain.Task{}, false, err
+		}
+	}
+
+	return task, true, nil
+}
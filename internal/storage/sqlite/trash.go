@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"time"
+)
+
+// liveSubtree returns the IDs of the tasks matching the condition together with all of their subtasks,
+// leaving out the tasks that are already in the trash.
+func liveSubtree(ctx context.Context, tx *sql.Tx, condition string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM tasks WHERE `+condition+` AND deleted_at IS NULL
+			UNION
+			SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id WHERE tasks.deleted_at IS NULL
+		)
+		SELECT id FROM subtree`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIDs(rows)
+}
+
+// trashTasks stamps the tasks with the same deletion time, so they can be restored together.
+func trashTasks(ctx context.Context, tx *sql.Tx, ids []string, deletedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := append([]any{deletedAt}, anySlice(ids)...)
+	_, err := tx.ExecContext(ctx, `UPDATE tasks SET deleted_at = ?, version = version + 1 WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := insertTaskEvent(ctx, tx, id, domain.TaskEventDeleted, nil, deletedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func anySlice(values []string) []any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	return args
+}
+
+// GetTrash returns the tasks that were moved to the trash, most recently deleted first.
+// Subtasks deleted together with their parent are left out, they are restored with it.
+func (s Storage) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	const op = "storage.sqlite.task.get_trash"
+
+	stmt, err := s.db.Prepare(`SELECT ` + taskColumns + ` FROM tasks
+		WHERE tasks.deleted_at IS NOT NULL AND NOT EXISTS (
+			SELECT 1 FROM tasks AS parent WHERE parent.id = tasks.parent_id AND parent.deleted_at = tasks.deleted_at
+		)
+		ORDER BY julianday(tasks.deleted_at) DESC, tasks.id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tasks, nil
+}
+
+// IsInTrash reports whether the task is in the trash, including subtasks deleted together with their parent.
+func (s Storage) IsInTrash(ctx context.Context, id string) (bool, error) {
+	const op = "storage.sqlite.task.is_in_trash"
+
+	var inTrash bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NOT NULL)`, id).Scan(&inTrash)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inTrash, nil
+}
+
+// RestoreTask brings the task back from the trash together with the subtasks deleted along with it.
+// A task whose parent is still in the trash or whose project no longer exists is restored to the top level
+// or to the inbox respectively.
+func (s Storage) RestoreTask(ctx context.Context, id string) (domain.Task, error) {
+	const op = "storage.sqlite.task.restore"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	task, err := restoreTask(ctx, tx, id)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Task{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, nil
+}
+
+// restoreTask brings the task back from the trash within tx, see RestoreTask.
+func restoreTask(ctx context.Context, tx *sql.Tx, id string) (domain.Task, error) {
+	rows, err := tx.QueryContext(ctx, `
+		WITH RECURSIVE subtree(id, deleted_at) AS (
+			SELECT id, deleted_at FROM tasks WHERE id = ? AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT tasks.id, subtree.deleted_at FROM tasks JOIN subtree ON tasks.parent_id = subtree.id
+			WHERE tasks.deleted_at = subtree.deleted_at
+		)
+		SELECT id FROM subtree`, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	if len(ids) == 0 {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+
+	restoredAt := time.Now()
+	args := append([]any{restoredAt}, anySlice(ids)...)
+	_, err = tx.ExecContext(ctx, `UPDATE tasks SET deleted_at = NULL, modified_at = ?, version = version + 1 WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	for _, taskID := range ids {
+		if err := insertTaskEvent(ctx, tx, taskID, domain.TaskEventRestored, nil, restoredAt); err != nil {
+			return domain.Task{}, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tasks SET parent_id = NULL WHERE id = ? AND parent_id IS NOT NULL AND NOT EXISTS (
+			SELECT 1 FROM tasks AS parent WHERE parent.id = tasks.parent_id AND parent.deleted_at IS NULL
+		)`, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tasks SET project_id = NULL WHERE id = ? AND project_id IS NOT NULL AND NOT EXISTS (
+			SELECT 1 FROM projects WHERE projects.id = tasks.project_id
+		)`, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return scanTask(tx.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+}
+
+// EmptyTrash permanently deletes every task in the trash and returns how many were deleted.
+func (s Storage) EmptyTrash(ctx context.Context) (int64, error) {
+	const op = "storage.sqlite.task.empty_trash"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM tasks WHERE deleted_at IS NOT NULL`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// PurgeTrash permanently deletes the tasks that were moved to the trash before the given time
+// and returns how many were deleted.
+func (s Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.task.purge_trash"
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM tasks WHERE deleted_at IS NOT NULL AND julianday(deleted_at) < julianday(?)`,
+		before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,610 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// folderSyncInterval is how often the tasks and the change logs in the shared folder are checked for changes.
+const folderSyncInterval = 5 * time.Second
+
+// syncLogExt is the extension of the change logs, each device appends to <device>.jsonl in the shared folder.
+const syncLogExt = ".jsonl"
+
+var syncDeviceName = regexp.MustCompile(`^[a-z0-9]{1,64}$`)
+
+// syncIgnoredFields are the JSON fields of tasks and projects that are not synced: the ID names the record,
+// and every device keeps its own version and modification time.
+var syncIgnoredFields = map[string]bool{"id": true, "version": true, "modified_at": true}
+
+// syncDeletedField marks a project deleted on some device, deleted tasks are synced with their deleted_at field.
+const syncDeletedField = "deleted"
+
+// syncConflictRetries is how many times a sync starts over when a task was changed locally while the changes
+// of the other devices were being merged.
+const syncConflictRetries = 3
+
+// FolderSync keeps the tasks and projects of several devices in step through a shared folder, e.g. one synced by
+// Syncthing or Dropbox. Every device appends the fields it changes to its own change log in the folder and merges
+// the change logs of the others. When two devices change the same field the change with the later hybrid logical
+// clock timestamp wins, changes to different fields of the same task are both kept.
+//
+// Tasks deleted permanently are not synced, the other devices keep them in the trash until they purge it themselves.
+type FolderSync struct {
+	store SyncStore
+	clock Clock
+
+	mu    sync.Mutex
+	state *domain.SyncState // loaded on first use, only FolderSync changes it
+}
+
+//go:generate mockery --name SyncStore
+type SyncStore interface {
+	GetAllProjects(ctx context.Context) ([]domain.Project, error)
+	ExportTasks(ctx context.Context) ([]domain.Task, error)
+	ApplySync(ctx context.Context, projects []domain.Project, tasks []domain.Task) ([]domain.Task, error)
+	DeleteProject(ctx context.Context, id string, deleteTasks bool) error
+	GetSyncState(ctx context.Context) (domain.SyncState, error)
+	SaveSyncState(ctx context.Context, state domain.SyncState, changed []domain.SyncKey) error
+}
+
+func NewFolderSync(store SyncStore, clock Clock) *FolderSync {
+	return &FolderSync{
+		store: store,
+		clock: clock,
+	}
+}
+
+// Directory returns the shared folder, or an empty string while syncing is off.
+func (s *FolderSync) Directory(ctx context.Context) (string, error) {
+	const op = "service.folder_sync.directory"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return "", handleError(op, err)
+	}
+
+	return state.Directory, nil
+}
+
+// SetDirectory starts syncing with the shared folder, or stops syncing when dir is empty.
+// The folder is remembered, syncing goes on after the app is restarted.
+func (s *FolderSync) SetDirectory(ctx context.Context, dir string) error {
+	const op = "service.folder_sync.set_directory"
+
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return domain.NewValidationError("directory", domain.ValidationInvalid, fmt.Sprintf("%s is not a directory", dir))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	state.Directory = dir
+	if err := s.store.SaveSyncState(ctx, *state, nil); err != nil {
+		return handleError(op, err)
+	}
+
+	return nil
+}
+
+// Run syncs until the context is cancelled, calling changed whenever changes from other devices were merged.
+func (s *FolderSync) Run(ctx context.Context, changed func()) {
+	const op = "service.folder_sync.run"
+
+	for {
+		merged, err := s.Sync(ctx)
+		if err != nil {
+			log.Error(op, err)
+		}
+		if merged > 0 {
+			changed()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(folderSyncInterval):
+		}
+	}
+}
+
+// Sync appends the local changes since the previous sync to the change log of this device and merges
+// the changes of the other devices. It returns the number of tasks and projects changed by the merge,
+// it does nothing while syncing is off.
+func (s *FolderSync) Sync(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		merged, err := s.sync(ctx)
+		if err != nil {
+			s.state = nil // changed halfway, the next sync starts over from the saved state
+		}
+		// a task edited during the merge is picked up as a local change when the sync starts over
+		if errors.Is(err, domain.ErrConflict) && attempt < syncConflictRetries {
+			continue
+		}
+		return merged, err
+	}
+}
+
+func (s *FolderSync) sync(ctx context.Context) (int, error) {
+	const op = "service.folder_sync.sync"
+
+	state, err := s.loadState(ctx)
+	if err != nil {
+		return 0, handleError(op, err)
+	}
+	if state.Directory == "" {
+		return 0, nil
+	}
+	if state.Device == "" {
+		state.Device = strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	}
+	clock := hybridClock{clock: s.clock, last: state.Clock, device: state.Device}
+
+	projects, tasks, err := s.export(ctx)
+	if err != nil {
+		return 0, handleError(op, err)
+	}
+
+	// local changes are stamped with the time their task or project was modified, so the later of a local
+	// and a remote change to the same field wins no matter which device syncs first
+	local := localChanges(state, syncRecords(projects, tasks), modifiedTimes(projects, tasks), &clock)
+	if err := appendSyncLog(filepath.Join(state.Directory, state.Device+syncLogExt), local); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	changed := make([]domain.SyncKey, 0, len(local))
+	for _, change := range local {
+		changed = append(changed, change.Key())
+	}
+
+	remote, err := s.readSyncLogs(state, &clock)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	merged := make(map[domain.SyncKey]bool) // the records changed, with an empty field name
+	var mergedFields []domain.SyncKey
+	for _, change := range remote {
+		key := change.Key()
+		if field, ok := state.Fields[key]; ok && field.Timestamp.Compare(change.Timestamp) >= 0 {
+			continue
+		}
+		state.Fields[key] = domain.SyncField{Value: change.Value, Timestamp: change.Timestamp}
+		merged[domain.SyncKey{Entity: key.Entity, ID: key.ID}] = true
+		mergedFields = append(mergedFields, key)
+	}
+
+	if len(merged) > 0 {
+		if err := s.apply(ctx, state, merged, projects, tasks); err != nil {
+			return 0, handleError(op, err)
+		}
+		if mergedFields, err = s.readBack(ctx, state, merged, mergedFields); err != nil {
+			return 0, handleError(op, err)
+		}
+		changed = append(changed, mergedFields...)
+	}
+
+	state.Clock = clock.last
+	if err := s.store.SaveSyncState(ctx, *state, changed); err != nil {
+		return 0, handleError(op, err)
+	}
+
+	return len(merged), nil
+}
+
+func (s *FolderSync) loadState(ctx context.Context) (*domain.SyncState, error) {
+	if s.state == nil {
+		state, err := s.store.GetSyncState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if state.Offsets == nil {
+			state.Offsets = make(map[string]int64)
+		}
+		if state.Fields == nil {
+			state.Fields = make(map[domain.SyncKey]domain.SyncField)
+		}
+		s.state = &state
+	}
+	return s.state, nil
+}
+
+func (s *FolderSync) export(ctx context.Context) ([]domain.Project, []domain.Task, error) {
+	projects, err := s.store.GetAllProjects(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := s.store.ExportTasks(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return projects, tasks, nil
+}
+
+// syncRecord is a task or project split into the JSON values of its fields.
+type syncRecord map[string]json.RawMessage
+
+// syncRecords returns the synced fields of every project and task, keyed by their entity and ID.
+func syncRecords(projects []domain.Project, tasks []domain.Task) map[domain.SyncKey]syncRecord {
+	records := make(map[domain.SyncKey]syncRecord, len(projects)+len(tasks))
+	for _, project := range projects {
+		records[domain.SyncKey{Entity: domain.SyncEntityProject, ID: project.ID}] = toSyncRecord(project)
+	}
+	for _, task := range tasks {
+		records[domain.SyncKey{Entity: domain.SyncEntityTask, ID: task.ID}] = toSyncRecord(task)
+	}
+	return records
+}
+
+// modifiedTimes returns when every project and task was last modified on this device.
+func modifiedTimes(projects []domain.Project, tasks []domain.Task) map[domain.SyncKey]time.Time {
+	times := make(map[domain.SyncKey]time.Time, len(projects)+len(tasks))
+	for _, project := range projects {
+		times[domain.SyncKey{Entity: domain.SyncEntityProject, ID: project.ID}] = project.ModifiedAt
+	}
+	for _, task := range tasks {
+		times[domain.SyncKey{Entity: domain.SyncEntityTask, ID: task.ID}] = task.ModifiedAt
+	}
+	return times
+}
+
+func toSyncRecord(v any) syncRecord {
+	data, _ := json.Marshal(v) // tasks and projects always encode
+	var record syncRecord
+	json.Unmarshal(data, &record)
+	for field := range syncIgnoredFields {
+		delete(record, field)
+	}
+	return record
+}
+
+// localChanges stamps the fields that differ from the state with the time their record was modified and
+// updates the state with them. Fields left out of the JSON because they are empty are compared as null,
+// a project that is gone was deleted.
+func localChanges(state *domain.SyncState, records map[domain.SyncKey]syncRecord, modified map[domain.SyncKey]time.Time, clock *hybridClock) []domain.SyncChange {
+	var changes []domain.SyncChange
+	set := func(key domain.SyncKey, value json.RawMessage) {
+		field, ok := state.Fields[key]
+		if ok && bytes.Equal(field.Value, value) {
+			return
+		}
+		timestamp := clock.now()
+		if at, ok := modified[domain.SyncKey{Entity: key.Entity, ID: key.ID}]; ok {
+			timestamp = clock.at(at, field.Timestamp)
+		}
+		change := domain.SyncChange{Timestamp: timestamp, Entity: key.Entity, ID: key.ID, Field: key.Field, Value: value}
+		state.Fields[key] = domain.SyncField{Value: value, Timestamp: change.Timestamp}
+		changes = append(changes, change)
+	}
+
+	for _, record := range sortedKeys(records) {
+		fields := make([]string, 0, len(records[record]))
+		for field := range records[record] {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			set(domain.SyncKey{Entity: record.Entity, ID: record.ID, Field: field}, records[record][field])
+		}
+	}
+
+	for _, key := range sortedKeys(state.Fields) {
+		record, ok := records[domain.SyncKey{Entity: key.Entity, ID: key.ID}]
+		switch {
+		case ok && key.Field != syncDeletedField:
+			if _, ok := record[key.Field]; !ok {
+				set(key, json.RawMessage("null"))
+			}
+		case !ok && key.Entity == domain.SyncEntityProject && key.Field == "name": // once per project, they all have a name
+			set(domain.SyncKey{Entity: key.Entity, ID: key.ID, Field: syncDeletedField}, json.RawMessage("true"))
+		}
+	}
+
+	return changes
+}
+
+// appendSyncLog writes the changes to the end of the change log in a single write.
+func appendSyncLog(path string, changes []domain.SyncChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, change := range changes {
+		if err := encoder.Encode(change); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readSyncLogs returns the changes appended to the change logs of the other devices since the previous sync,
+// in the order of their timestamps. The own change log is read only to move the clock past it, which matters
+// when the database was restored from a backup.
+func (s *FolderSync) readSyncLogs(state *domain.SyncState, clock *hybridClock) ([]domain.SyncChange, error) {
+	entries, err := os.ReadDir(state.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []domain.SyncChange
+	for _, entry := range entries {
+		device, ok := strings.CutSuffix(entry.Name(), syncLogExt)
+		if !ok || !syncDeviceName.MatchString(device) || !entry.Type().IsRegular() {
+			continue // e.g. a conflict copy made by the sync tool
+		}
+
+		read, n, err := readSyncLog(filepath.Join(state.Directory, entry.Name()), state.Offsets[device])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		state.Offsets[device] += n
+
+		for _, change := range read {
+			clock.observe(change.Timestamp)
+		}
+		if device != state.Device {
+			changes = append(changes, read...)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Timestamp.Compare(changes[j].Timestamp) < 0
+	})
+
+	return changes, nil
+}
+
+// readSyncLog reads the complete lines after the offset and returns the changes and the number of bytes read.
+// A line still being written, or only partly copied by the sync tool, is read by a later sync.
+func readSyncLog(path string, offset int64) ([]domain.SyncChange, int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	var changes []domain.SyncChange
+	var n int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return changes, n, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		n += int64(len(line))
+
+		var change domain.SyncChange
+		if err := json.Unmarshal(line, &change); err != nil || change.ID == "" || change.Field == "" {
+			log.Warnf("skipping invalid line in %s: %s", path, bytes.TrimSpace(line))
+			continue
+		}
+		changes = append(changes, change)
+	}
+}
+
+// apply writes the merged records. A record is written once all of its required fields arrived,
+// until then its fields wait in the state. The local tasks are those the local changes were taken from,
+// a task changed since then is not overwritten and ErrConflict is returned instead.
+func (s *FolderSync) apply(ctx context.Context, state *domain.SyncState, merged map[domain.SyncKey]bool, local []domain.Project, localTasks []domain.Task) error {
+	fields := make(map[domain.SyncKey]syncRecord)
+	for key, field := range state.Fields {
+		record := domain.SyncKey{Entity: key.Entity, ID: key.ID}
+		if !merged[record] {
+			continue
+		}
+		if fields[record] == nil {
+			fields[record] = make(syncRecord)
+		}
+		fields[record][key.Field] = field.Value
+	}
+
+	exists := make(map[string]bool, len(local))
+	for _, project := range local {
+		exists[project.ID] = true
+	}
+	versions := make(map[string]int, len(localTasks))
+	for _, task := range localTasks {
+		versions[task.ID] = task.Version
+	}
+
+	var projects []domain.Project
+	var tasks []domain.Task
+	var deleted []string
+	now := s.clock.Now()
+	for _, key := range sortedKeys(fields) {
+		record := fields[key]
+		record["id"], _ = json.Marshal(key.ID)
+		data, _ := json.Marshal(record)
+
+		switch key.Entity {
+		case domain.SyncEntityProject:
+			if string(record[syncDeletedField]) == "true" {
+				if exists[key.ID] {
+					deleted = append(deleted, key.ID)
+				}
+				continue
+			}
+			var project domain.Project
+			if _, ok := record["name"]; !ok || json.Unmarshal(data, &project) != nil {
+				continue
+			}
+			project.ModifiedAt = now
+			projects = append(projects, project)
+		case domain.SyncEntityTask:
+			var task domain.Task
+			if _, ok := record["title"]; !ok || json.Unmarshal(data, &task) != nil {
+				continue
+			}
+			if _, ok := record["created_at"]; !ok {
+				continue
+			}
+			task.ModifiedAt = now
+			task.Version = versions[key.ID]
+			tasks = append(tasks, task)
+		}
+	}
+
+	applyCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	if len(projects) > 0 || len(tasks) > 0 {
+		if _, err := s.store.ApplySync(applyCtx, projects, tasks); err != nil {
+			return err
+		}
+	}
+	for _, id := range deleted {
+		// the tasks of the project are moved or trashed by the changes the other device made to them
+		err := s.store.DeleteProject(applyCtx, id, false)
+		if err != nil && !errors.Is(err, domain.ErrProjectNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readBack replaces the merged values in the state with the values read back from the storage, so a value
+// stored in another form, e.g. a time in another time zone, is not taken for a local change by the next sync.
+// Fields the other device did not send, e.g. because its app is older, are added with a zero timestamp for
+// the same reason. It returns the keys of the fields changed in the state.
+func (s *FolderSync) readBack(ctx context.Context, state *domain.SyncState, merged map[domain.SyncKey]bool, keys []domain.SyncKey) ([]domain.SyncKey, error) {
+	projects, tasks, err := s.export(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := syncRecords(projects, tasks)
+
+	for _, key := range keys {
+		record, ok := records[domain.SyncKey{Entity: key.Entity, ID: key.ID}]
+		if !ok || key.Field == syncDeletedField {
+			continue
+		}
+		field := state.Fields[key]
+		if value, ok := record[key.Field]; ok {
+			field.Value = value
+		} else {
+			field.Value = json.RawMessage("null")
+		}
+		state.Fields[key] = field
+	}
+
+	for _, id := range sortedKeys(merged) {
+		for name, value := range records[id] {
+			key := domain.SyncKey{Entity: id.Entity, ID: id.ID, Field: name}
+			if _, ok := state.Fields[key]; !ok {
+				state.Fields[key] = domain.SyncField{Value: value}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// hybridClock makes the timestamps of the changes, see domain.HLC.
+type hybridClock struct {
+	clock  Clock
+	last   domain.HLC
+	device string
+}
+
+// now returns a timestamp after every timestamp made or observed before.
+func (c *hybridClock) now() domain.HLC {
+	wall := c.clock.Now().UnixMilli()
+	if wall > c.last.Wall {
+		c.last = domain.HLC{Wall: wall, Device: c.device}
+	} else {
+		c.last = domain.HLC{Wall: c.last.Wall, Logical: c.last.Logical + 1, Device: c.device}
+	}
+	return c.last
+}
+
+// at returns the timestamp of a change made at t to a field whose previous value has the timestamp previous.
+// It is never before previous, as the change was made after that value was known.
+func (c *hybridClock) at(t time.Time, previous domain.HLC) domain.HLC {
+	timestamp := domain.HLC{Wall: t.UnixMilli(), Device: c.device}
+	if timestamp.Compare(previous) <= 0 {
+		timestamp = domain.HLC{Wall: previous.Wall, Logical: previous.Logical + 1, Device: c.device}
+	}
+	c.observe(timestamp)
+	return timestamp
+}
+
+// observe moves the clock past a timestamp made on another device.
+func (c *hybridClock) observe(t domain.HLC) {
+	if t.Wall > c.last.Wall || (t.Wall == c.last.Wall && t.Logical > c.last.Logical) {
+		c.last = domain.HLC{Wall: t.Wall, Logical: t.Logical, Device: c.device}
+	}
+}
+
+func sortedKeys[V any](m map[domain.SyncKey]V) []domain.SyncKey {
+	keys := make([]domain.SyncKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Entity != b.Entity {
+			return a.Entity > b.Entity // projects before the tasks in them
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Field < b.Field
+	})
+	return keys
+}
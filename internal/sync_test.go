@@ -0,0 +1,331 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// replica is a device with its own database, syncing with the others through a shared folder.
+type replica struct {
+	t        *testing.T
+	clock    *fakeClock
+	tasks    Task
+	projects Project
+	sync     *FolderSync
+}
+
+func newReplica(t *testing.T, shared string, now time.Time) *replica {
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+
+	clock := newFakeClock(now)
+	r := &replica{
+		t:        t,
+		clock:    clock,
+		tasks:    NewTask(storage, storage, storage),
+		projects: NewProject(storage, storage),
+		sync:     NewFolderSync(storage, clock),
+	}
+	require.NoError(t, r.sync.SetDirectory(context.Background(), shared))
+	return r
+}
+
+func (r *replica) syncNow() int {
+	merged, err := r.sync.Sync(context.Background())
+	require.NoError(r.t, err)
+	return merged
+}
+
+func (r *replica) create(title string) domain.Task {
+	task, err := r.tasks.Create(context.Background(), domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone})
+	require.NoError(r.t, err)
+	return task
+}
+
+func (r *replica) get(id string) domain.Task {
+	task, err := r.tasks.GetByID(context.Background(), id)
+	require.NoError(r.t, err)
+	return task
+}
+
+func (r *replica) update(request domain.UpdateTaskRequest) {
+	request.Version = r.get(request.ID).Version
+	_, err := r.tasks.Update(context.Background(), request)
+	require.NoError(r.t, err)
+}
+
+func (r *replica) titles() []string {
+	page, err := r.tasks.List(context.Background(), domain.TaskQuery{SortBy: domain.TaskSortTitle})
+	require.NoError(r.t, err)
+	titles := make([]string, 0, len(page.Tasks))
+	for _, task := range page.Tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+// syncAll syncs every replica twice, so each one has seen the changes of all the others.
+func syncAll(replicas ...*replica) {
+	for i := 0; i < 2; i++ {
+		for _, r := range replicas {
+			r.syncNow()
+		}
+	}
+}
+
+func newReplicas(t *testing.T) (shared string, laptop, desktop *replica) {
+	shared = t.TempDir()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	return shared, newReplica(t, shared, now), newReplica(t, shared, now)
+}
+
+func TestFolderSync_MergesDivergedLists(t *testing.T) {
+	shared, laptop, desktop := newReplicas(t)
+	laptop.create("Write report")
+	desktop.create("Buy milk")
+
+	syncAll(laptop, desktop)
+
+	assert.Equal(t, []string{"Buy milk", "Write report"}, laptop.titles())
+	assert.Equal(t, []string{"Buy milk", "Write report"}, desktop.titles())
+
+	t.Run("nothing more to sync", func(t *testing.T) {
+		sizes := logSizes(t, shared)
+
+		syncAll(laptop, desktop)
+
+		assert.Equal(t, sizes, logSizes(t, shared), "merged values are not written back as local changes")
+		assert.Zero(t, laptop.syncNow())
+	})
+}
+
+func TestFolderSync_ConcurrentEdits(t *testing.T) {
+	t.Run("different fields are both kept", func(t *testing.T) {
+		_, laptop, desktop := newReplicas(t)
+		task := laptop.create("Write report")
+		syncAll(laptop, desktop)
+
+		laptop.clock.Advance(time.Minute)
+		desktop.clock.Advance(time.Minute)
+		laptop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		desktop.update(domain.UpdateTaskRequest{ID: task.ID, Priority: domain.TaskPriorityHigh, Tags: []string{"work"}})
+		syncAll(laptop, desktop)
+
+		for _, r := range []*replica{laptop, desktop} {
+			got := r.get(task.ID)
+			assert.Equal(t, "Write the report", got.Title)
+			assert.Equal(t, domain.TaskPriorityHigh, got.Priority)
+			assert.Equal(t, []string{"work"}, []string(got.Tags))
+		}
+	})
+
+	t.Run("the later change to a field wins", func(t *testing.T) {
+		_, laptop, desktop := newReplicas(t)
+		task := laptop.create("Write report")
+		syncAll(laptop, desktop)
+
+		desktop.clock.Advance(time.Minute)
+		desktop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		desktop.syncNow()
+		laptop.clock.Advance(2 * time.Minute)
+		laptop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the final report"})
+		syncAll(desktop, laptop) // the desktop merges first and still loses
+
+		assert.Equal(t, "Write the final report", laptop.get(task.ID).Title)
+		assert.Equal(t, "Write the final report", desktop.get(task.ID).Title)
+	})
+
+	t.Run("an older local change loses to a later remote one", func(t *testing.T) {
+		_, laptop, desktop := newReplicas(t)
+		task := laptop.create("Write report")
+		syncAll(laptop, desktop)
+
+		laptop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		time.Sleep(5 * time.Millisecond) // the changes are stamped with the time the tasks were modified
+		desktop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the final report"})
+		desktop.syncNow()
+		laptop.clock.Advance(time.Hour) // the laptop syncs much later
+		syncAll(laptop, desktop)
+
+		assert.Equal(t, "Write the final report", laptop.get(task.ID).Title)
+		assert.Equal(t, "Write the final report", desktop.get(task.ID).Title)
+	})
+
+	t.Run("a clock running behind does not lose changes", func(t *testing.T) {
+		shared := t.TempDir()
+		now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		ahead, behind := newReplica(t, shared, now.Add(time.Hour)), newReplica(t, shared, now)
+		task := ahead.create("Write report")
+		syncAll(ahead, behind)
+
+		behind.clock.Advance(time.Minute)
+		behind.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+		syncAll(behind, ahead)
+
+		assert.Equal(t, "Write the report", ahead.get(task.ID).Title)
+	})
+}
+
+// editingStore runs edit once right before the merged changes are applied, like an edit made in the window
+// while the sync is running.
+type editingStore struct {
+	*sqlite.Storage
+	edit func()
+}
+
+func (s *editingStore) ApplySync(ctx context.Context, projects []domain.Project, tasks []domain.Task) ([]domain.Task, error) {
+	if s.edit != nil {
+		edit := s.edit
+		s.edit = nil
+		edit()
+	}
+	return s.Storage.ApplySync(ctx, projects, tasks)
+}
+
+func TestFolderSync_EditDuringMerge(t *testing.T) {
+	shared, _, desktop := newReplicas(t)
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	store := &editingStore{Storage: storage}
+	laptop := &replica{
+		t:        t,
+		clock:    newFakeClock(desktop.clock.Now()),
+		tasks:    NewTask(storage, storage, storage),
+		projects: NewProject(storage, storage),
+	}
+	laptop.sync = NewFolderSync(store, laptop.clock)
+	require.NoError(t, laptop.sync.SetDirectory(context.Background(), shared))
+
+	task := laptop.create("Write report")
+	syncAll(laptop, desktop)
+
+	desktop.update(domain.UpdateTaskRequest{ID: task.ID, Title: "Write the report"})
+	desktop.syncNow()
+	store.edit = func() {
+		laptop.update(domain.UpdateTaskRequest{ID: task.ID, Priority: domain.TaskPriorityHigh})
+	}
+	syncAll(laptop, desktop)
+
+	for _, r := range []*replica{laptop, desktop} {
+		got := r.get(task.ID)
+		assert.Equal(t, "Write the report", got.Title)
+		assert.Equal(t, domain.TaskPriorityHigh, got.Priority, "the edit made during the merge is kept")
+	}
+}
+
+func TestFolderSync_Deletions(t *testing.T) {
+	ctx := context.Background()
+	_, laptop, desktop := newReplicas(t)
+
+	project, err := laptop.projects.Create(ctx, domain.CreateProjectRequest{Name: "Work", Color: "#3b82f6"})
+	require.NoError(t, err)
+	task := laptop.create("Write report")
+	laptop.update(domain.UpdateTaskRequest{ID: task.ID, ProjectID: &project.ID})
+	syncAll(laptop, desktop)
+
+	got := desktop.get(task.ID)
+	require.NotNil(t, got.ProjectID)
+	assert.Equal(t, project.ID, *got.ProjectID)
+	_, err = desktop.projects.GetByID(ctx, project.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, laptop.projects.Delete(ctx, project.ID, domain.ProjectDeleteMoveToInbox))
+	require.NoError(t, desktop.tasks.Delete(ctx, task.ID))
+	syncAll(laptop, desktop)
+
+	for _, r := range []*replica{laptop, desktop} {
+		_, err := r.projects.GetByID(ctx, project.ID)
+		assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+
+		trash, err := r.tasks.GetTrash(ctx)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, task.ID, trash[0].ID)
+		assert.Nil(t, trash[0].ProjectID)
+	}
+}
+
+func TestFolderSync_OtherTimeZone(t *testing.T) {
+	shared, laptop, _ := newReplicas(t)
+	lines := `{"ts":"1717243200000.0@remote","entity":"task","id":"t1","field":"title","value":"Write report"}
+{"ts":"1717243200000.1@remote","entity":"task","id":"t1","field":"status","value":"todo"}
+{"ts":"1717243200000.2@remote","entity":"task","id":"t1","field":"priority","value":"none"}
+{"ts":"1717243200000.3@remote","entity":"task","id":"t1","field":"created_at","value":"2024-06-01T17:00:00+05:00"}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "remote"+syncLogExt), []byte(lines), 0o644))
+
+	assert.Equal(t, 1, laptop.syncNow())
+	assert.Equal(t, []string{"Write report"}, laptop.titles())
+
+	sizes := logSizes(t, shared)
+	laptop.syncNow()
+	assert.Equal(t, sizes, logSizes(t, shared), "the time stored in the local time zone is not a change")
+}
+
+func TestFolderSync_PartialLine(t *testing.T) {
+	shared, laptop, desktop := newReplicas(t)
+	laptop.create("Write report")
+	laptop.syncNow()
+
+	// the sync tool has copied only part of the next line so far
+	path := logPaths(t, shared)[0]
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(content, []byte(`{"ts":"1.0@x","entity":"task"`)...), 0o644))
+
+	desktop.syncNow()
+	assert.Equal(t, []string{"Write report"}, desktop.titles())
+}
+
+func TestFolderSync_Off(t *testing.T) {
+	_, laptop, _ := newReplicas(t)
+	require.NoError(t, laptop.sync.SetDirectory(context.Background(), ""))
+	laptop.create("Write report")
+
+	assert.Zero(t, laptop.syncNow())
+
+	err := laptop.sync.SetDirectory(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+}
+
+func TestHybridClock(t *testing.T) {
+	clock := hybridClock{clock: newFakeClock(time.UnixMilli(1000)), device: "a"}
+
+	first := clock.now()
+	second := clock.now()
+	assert.Equal(t, domain.HLC{Wall: 1000, Logical: 1, Device: "a"}, second, "the same millisecond")
+	assert.Equal(t, 1, second.Compare(first))
+
+	remote := domain.HLC{Wall: 5000, Logical: 7, Device: "b"}
+	clock.observe(remote)
+	assert.Equal(t, 1, clock.now().Compare(remote), "after a timestamp from a clock running ahead")
+
+	parsed, err := domain.ParseHLC(remote.String())
+	require.NoError(t, err)
+	assert.Equal(t, remote, parsed)
+}
+
+func logPaths(t *testing.T, dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+syncLogExt))
+	require.NoError(t, err)
+	return paths
+}
+
+func logSizes(t *testing.T, dir string) map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, path := range logPaths(t, dir) {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		sizes[path] = info.Size()
+	}
+	return sizes
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type Tag struct {
+	provider TagProvider
+	modifier TagModifier
+}
+
+//go:generate mockery --name TagProvider
+type TagProvider interface {
+	GetAllTags(ctx context.Context) ([]domain.Tag, error)
+	GetTagByID(ctx context.Context, id string) (domain.Tag, error)
+	GetTagByName(ctx context.Context, name string) (domain.Tag, error)
+}
+
+//go:generate mockery --name TagModifier
+type TagModifier interface {
+	UpdateTag(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	MergeTags(ctx context.Context, sourceIDs []string, targetID string) error
+	DeleteTag(ctx context.Context, id string) error
+}
+
+func NewTag(provider TagProvider, modifier TagModifier) Tag {
+	return Tag{
+		provider: provider,
+		modifier: modifier,
+	}
+}
+
+// GetAll returns every tag with the number of tasks using it.
+func (t Tag) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	const op = "service.tag.get_all"
+
+	tags, err := t.provider.GetAllTags(ctx)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return tags, nil
+}
+
+// Update renames or recolors the tag on every task. Renaming to the name of another tag
+// fails with ErrTagAlreadyExists, such tags have to be merged instead.
+func (t Tag) Update(ctx context.Context, request domain.UpdateTagRequest) (domain.Tag, error) {
+	const op = "service.tag.update"
+	request.Name = strings.TrimSpace(request.Name)
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.ID, validation.Required),
+		validation.Field(&request.Name, validation.By(validateTagName)),
+		validation.Field(&request.Color, validation.By(validateColor)),
+	)
+	if err != nil {
+		return domain.Tag{}, invalidArguments(err)
+	}
+
+	tag, err := t.provider.GetTagByID(ctx, request.ID)
+	if err != nil {
+		return domain.Tag{}, handleError(op, err)
+	}
+
+	if request.Name != "" && request.Name != tag.Name {
+		existing, err := t.provider.GetTagByName(ctx, request.Name)
+		switch {
+		case err == nil && existing.ID != tag.ID:
+			return domain.Tag{}, domain.ErrTagAlreadyExists
+		case err != nil && !errors.Is(err, domain.ErrTagNotFound):
+			return domain.Tag{}, handleError(op, err)
+		}
+		tag.Name = request.Name
+	}
+	if request.Color != "" {
+		tag.Color = request.Color
+	}
+
+	tag, err = t.modifier.UpdateTag(ctx, tag)
+	if err != nil {
+		return domain.Tag{}, handleError(op, err)
+	}
+
+	return tag, nil
+}
+
+// Merge replaces the source tags with the target tag on every task and deletes the source tags.
+func (t Tag) Merge(ctx context.Context, request domain.MergeTagsRequest) (domain.Tag, error) {
+	const op = "service.tag.merge"
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.SourceIDs, validation.Required, validation.Each(
+			validation.Required,
+			validation.NotIn(request.TargetID).Error("must not contain the target tag"),
+		)),
+		validation.Field(&request.TargetID, validation.Required),
+	)
+	if err != nil {
+		return domain.Tag{}, invalidArguments(err)
+	}
+
+	if _, err := t.provider.GetTagByID(ctx, request.TargetID); err != nil {
+		return domain.Tag{}, handleError(op, err)
+	}
+
+	mergeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := t.modifier.MergeTags(mergeCtx, request.SourceIDs, request.TargetID); err != nil {
+		return domain.Tag{}, handleError(op, err)
+	}
+
+	tag, err := t.provider.GetTagByID(ctx, request.TargetID)
+	if err != nil {
+		return domain.Tag{}, handleError(op, err)
+	}
+
+	return tag, nil
+}
+
+// Delete removes the tag from every task and deletes it.
+func (t Tag) Delete(ctx context.Context, id string) error {
+	const op = "service.tag.delete"
+
+	deleteCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := t.modifier.DeleteTag(deleteCtx, id); err != nil {
+		return handleError(op, err)
+	}
+
+	return nil
+}
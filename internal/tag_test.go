@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+type TagSuite struct {
+	mockTagProvider *mocks.TagProvider
+	mockTagModifier *mocks.TagModifier
+	tagService      Tag
+}
+
+func newTagSuite(t *testing.T) *TagSuite {
+	mockTagProvider := mocks.NewTagProvider(t)
+	mockTagModifier := mocks.NewTagModifier(t)
+	return &TagSuite{
+		mockTagProvider: mockTagProvider,
+		mockTagModifier: mockTagModifier,
+		tagService:      NewTag(mockTagProvider, mockTagModifier),
+	}
+}
+
+func TestTag_Update(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		suite := newTagSuite(t)
+		ctx := context.Background()
+
+		suite.mockTagProvider.On("GetTagByID", ctx, "1").Return(domain.Tag{ID: "1", Name: "backend", Color: "#ef4444"}, nil)
+		suite.mockTagProvider.On("GetTagByName", ctx, "server").Return(domain.Tag{}, domain.ErrTagNotFound)
+		suite.mockTagModifier.On("UpdateTag", ctx, domain.Tag{ID: "1", Name: "server", Color: "#ef4444"}).
+			Return(domain.Tag{ID: "1", Name: "server", Color: "#ef4444"}, nil)
+
+		tag, err := suite.tagService.Update(ctx, domain.UpdateTagRequest{ID: "1", Name: " server "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "server", tag.Name)
+	})
+
+	t.Run("change case only", func(t *testing.T) {
+		suite := newTagSuite(t)
+		ctx := context.Background()
+
+		suite.mockTagProvider.On("GetTagByID", ctx, "1").Return(domain.Tag{ID: "1", Name: "backend"}, nil)
+		suite.mockTagProvider.On("GetTagByName", ctx, "Backend").Return(domain.Tag{ID: "1", Name: "backend"}, nil)
+		suite.mockTagModifier.On("UpdateTag", ctx, domain.Tag{ID: "1", Name: "Backend"}).Return(domain.Tag{ID: "1", Name: "Backend"}, nil)
+
+		_, err := suite.tagService.Update(ctx, domain.UpdateTagRequest{ID: "1", Name: "Backend"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rename to existing tag", func(t *testing.T) {
+		suite := newTagSuite(t)
+		ctx := context.Background()
+
+		suite.mockTagProvider.On("GetTagByID", ctx, "1").Return(domain.Tag{ID: "1", Name: "backend"}, nil)
+		suite.mockTagProvider.On("GetTagByName", ctx, "server").Return(domain.Tag{ID: "2", Name: "server"}, nil)
+
+		_, err := suite.tagService.Update(ctx, domain.UpdateTagRequest{ID: "1", Name: "server"})
+
+		assert.ErrorIs(t, err, domain.ErrTagAlreadyExists)
+		suite.mockTagModifier.AssertNotCalled(t, "UpdateTag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid color", func(t *testing.T) {
+		suite := newTagSuite(t)
+
+		_, err := suite.tagService.Update(context.Background(), domain.UpdateTagRequest{ID: "1", Color: "red"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	})
+}
+
+func TestTag_Merge(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		suite := newTagSuite(t)
+		ctx := context.Background()
+
+		suite.mockTagProvider.On("GetTagByID", ctx, "target").Return(domain.Tag{ID: "target", UsageCount: 3}, nil)
+		suite.mockTagModifier.On("MergeTags", mock.Anything, []string{"a", "b"}, "target").Return(nil)
+
+		tag, err := suite.tagService.Merge(ctx, domain.MergeTagsRequest{SourceIDs: []string{"a", "b"}, TargetID: "target"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "target", tag.ID)
+	})
+
+	t.Run("target among sources", func(t *testing.T) {
+		suite := newTagSuite(t)
+
+		_, err := suite.tagService.Merge(context.Background(), domain.MergeTagsRequest{SourceIDs: []string{"a", "target"}, TargetID: "target"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockTagModifier.AssertNotCalled(t, "MergeTags", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTag_ChangesTaskVersions(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	tasks, tags := NewTask(storage, storage, storage), NewTag(storage, storage)
+
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Fix login", Tags: []string{"backend", "bug", "urgent"}})
+	require.NoError(t, err)
+	all, err := tags.GetAll(ctx)
+	require.NoError(t, err)
+	ids := make(map[string]string)
+	for _, tag := range all {
+		ids[tag.Name] = tag.ID
+	}
+
+	// each change of the tags makes the previous version of the task stale and is recorded in its history
+	_, err = tags.Update(ctx, domain.UpdateTagRequest{ID: ids["backend"], Name: "server"})
+	require.NoError(t, err)
+	_, err = tags.Merge(ctx, domain.MergeTagsRequest{SourceIDs: []string{ids["urgent"]}, TargetID: ids["bug"]})
+	require.NoError(t, err)
+	require.NoError(t, tags.Delete(ctx, ids["bug"]))
+
+	changed, err := tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StringArray{"server"}, changed.Tags)
+	assert.Equal(t, task.Version+3, changed.Version)
+
+	_, err = tasks.Update(ctx, domain.UpdateTaskRequest{ID: task.ID, Version: task.Version, Tags: []string{"backend"}})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	history, err := tasks.GetTaskHistory(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, []domain.TaskFieldChange{{Field: "tags", Old: "backend, bug, urgent", New: "server, bug, urgent"}}, history[1].Changes)
+	assert.Equal(t, []domain.TaskFieldChange{{Field: "tags", Old: "server, bug", New: "server"}}, history[3].Changes)
+}
@@ -0,0 +1,362 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+const todoTxtDateLayout = "2006-01-02"
+
+// todoTxtIDLength is how many characters of the task ID are written in the id: tag of its line.
+const todoTxtIDLength = 8
+
+// todoTxtPriorities maps todo.txt priorities to task priorities, the letters after C are read as low.
+var todoTxtPriorities = map[domain.TaskPriority]string{
+	domain.TaskPriorityHigh:   "A",
+	domain.TaskPriorityMedium: "B",
+	domain.TaskPriorityLow:    "C",
+}
+
+// parseTodoTxt reads a task from a todo.txt line. Both +project and @context become tags,
+// contexts keep their @ so that they are written back as contexts. The ID of the task is
+// the short ID of its id: tag, it is empty for lines without one.
+func parseTodoTxt(line string) (domain.Task, error) {
+	task := domain.Task{
+		Status:   domain.TaskStatusTodo,
+		Priority: domain.TaskPriorityNone,
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) > 0 && fields[0] == "x" {
+		task.Status = domain.TaskStatusDone
+		fields = fields[1:]
+		if completed, ok := parseTodoTxtDate(fields); ok {
+			task.ModifiedAt = completed
+			fields = fields[1:]
+		}
+	} else if len(fields) > 0 && isTodoTxtPriority(fields[0]) {
+		task.Priority = todoTxtPriority(fields[0][1])
+		fields = fields[1:]
+	}
+	if created, ok := parseTodoTxtDate(fields); ok {
+		task.CreatedAt = created
+		fields = fields[1:]
+	}
+
+	var words []string
+	for _, field := range fields {
+		key, value, _ := strings.Cut(field, ":")
+		switch {
+		case len(field) > 1 && field[0] == '+':
+			task.Tags = append(task.Tags, field[1:])
+		case len(field) > 1 && field[0] == '@':
+			task.Tags = append(task.Tags, field)
+		case key == "due" && value != "":
+			dueDate, err := time.ParseInLocation(todoTxtDateLayout, value, time.Local)
+			if err != nil {
+				return domain.Task{}, fmt.Errorf("due: must be a date like %s", todoTxtDateLayout)
+			}
+			task.DueDate = &dueDate
+		case key == "id" && value != "" && todoTxtID(value) == value:
+			task.ID = value
+		case key == "pri" && len(value) == 1 && task.Status == domain.TaskStatusDone:
+			// completed tasks keep their priority as pri:A since the (A) prefix is not allowed after x
+			task.Priority = todoTxtPriority(value[0])
+		default:
+			words = append(words, field)
+		}
+	}
+	task.Title = strings.Join(words, " ")
+
+	if err := validation.Validate(task.Title, validation.Required, validation.By(validateTitle)); err != nil {
+		return domain.Task{}, fmt.Errorf("title: %w", err)
+	}
+	if err := validateTags([]string(task.Tags)); err != nil {
+		return domain.Task{}, fmt.Errorf("tags: %w", err)
+	}
+
+	return task, nil
+}
+
+func parseTodoTxtDate(fields []string) (time.Time, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(todoTxtDateLayout, fields[0], time.Local)
+	return date, err == nil
+}
+
+func isTodoTxtPriority(field string) bool {
+	return len(field) == 3 && field[0] == '(' && field[1] >= 'A' && field[1] <= 'Z' && field[2] == ')'
+}
+
+func todoTxtPriority(letter byte) domain.TaskPriority {
+	switch letter {
+	case 'A':
+		return domain.TaskPriorityHigh
+	case 'B':
+		return domain.TaskPriorityMedium
+	default:
+		return domain.TaskPriorityLow
+	}
+}
+
+// formatTodoTxt writes the task as a todo.txt line, the description and the time of the due date are left out.
+func formatTodoTxt(task domain.Task) string {
+	var fields []string
+	letter, hasPriority := todoTxtPriorities[task.Priority]
+
+	if task.Status == domain.TaskStatusDone {
+		fields = append(fields, "x")
+		// the creation date may only follow a completion date
+		if !task.ModifiedAt.IsZero() {
+			fields = append(fields, task.ModifiedAt.Local().Format(todoTxtDateLayout))
+			if !task.CreatedAt.IsZero() {
+				fields = append(fields, task.CreatedAt.Local().Format(todoTxtDateLayout))
+			}
+		}
+	} else {
+		if hasPriority {
+			fields = append(fields, "("+letter+")")
+		}
+		if !task.CreatedAt.IsZero() {
+			fields = append(fields, task.CreatedAt.Local().Format(todoTxtDateLayout))
+		}
+	}
+
+	fields = append(fields, strings.Fields(task.Title)...)
+	for _, tag := range task.Tags {
+		tag = strings.Join(strings.Fields(tag), "-")
+		if !strings.HasPrefix(tag, "@") {
+			tag = "+" + tag
+		}
+		fields = append(fields, tag)
+	}
+	if task.DueDate != nil {
+		fields = append(fields, "due:"+task.DueDate.Local().Format(todoTxtDateLayout))
+	}
+	if task.Status == domain.TaskStatusDone && hasPriority {
+		fields = append(fields, "pri:"+letter)
+	}
+	if id := todoTxtID(task.ID); id != "" {
+		fields = append(fields, "id:"+id)
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// readTodoTxt parses every non-blank line, the first invalid line is reported by its number.
+func readTodoTxt(r io.Reader) ([]domain.Task, error) {
+	var tasks []domain.Task
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if number == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+
+		task, err := parseTodoTxt(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// todoTxtID returns the short ID written in the id: tag of the line of the task with the given ID,
+// its first letters and digits. The short ID of a short ID is itself.
+func todoTxtID(id string) string {
+	short := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, id)
+	if len(short) > todoTxtIDLength {
+		short = short[:todoTxtIDLength]
+	}
+	return short
+}
+
+// todoTxtKey is how a line without an id: tag is recognised, by the title of its task.
+func todoTxtKey(task domain.Task) string {
+	return strings.ToLower(strings.Join(strings.Fields(task.Title), " "))
+}
+
+// matchTodoTxt pairs the parsed lines with the tasks, each task with one line at most. Lines with an id: tag
+// are matched by it, the other lines by title. It returns the task of every matched line by the index of
+// the line and the tasks left without a line.
+func matchTodoTxt(tasks, lines []domain.Task) (map[int]domain.Task, []domain.Task) {
+	byID := make(map[string][]domain.Task)
+	byTitle := make(map[string][]domain.Task)
+	for _, task := range tasks {
+		byID[todoTxtID(task.ID)] = append(byID[todoTxtID(task.ID)], task)
+		byTitle[todoTxtKey(task)] = append(byTitle[todoTxtKey(task)], task)
+	}
+
+	used := make(map[string]bool)
+	matched := make(map[int]domain.Task)
+	take := func(i int, candidates []domain.Task) {
+		for _, task := range candidates {
+			if !used[task.ID] {
+				used[task.ID] = true
+				matched[i] = task
+				return
+			}
+		}
+	}
+	// the lines with a tag go first, so a line matched by title cannot take the task of a tagged line
+	for i, line := range lines {
+		if line.ID != "" {
+			take(i, byID[line.ID])
+		}
+	}
+	for i, line := range lines {
+		if line.ID == "" {
+			take(i, byTitle[todoTxtKey(line)])
+		}
+	}
+
+	var unmatched []domain.Task
+	for _, task := range tasks {
+		if !used[task.ID] {
+			unmatched = append(unmatched, task)
+		}
+	}
+	return matched, unmatched
+}
+
+// mergeTodoTxt matches the parsed tasks with the existing ones and returns the tasks to write.
+// Matched tasks only take the fields a todo.txt line holds, a due date on the same day keeps its time.
+// The existing tasks left without a matching line are returned as well.
+func mergeTodoTxt(existing, parsed []domain.Task) ([]domain.Task, domain.ImportReport, []domain.Task) {
+	matched, unmatched := matchTodoTxt(existing, parsed)
+
+	now := time.Now()
+	var report domain.ImportReport
+	var tasks []domain.Task
+	for i, task := range parsed {
+		current, ok := matched[i]
+		if !ok {
+			task.ID = uuid.NewString()
+			if task.CreatedAt.IsZero() {
+				task.CreatedAt = now
+			}
+			if task.ModifiedAt.IsZero() || task.Status != domain.TaskStatusDone {
+				task.ModifiedAt = now
+			}
+			report.Created++
+			tasks = append(tasks, task)
+			continue
+		}
+
+		updated := current
+		updated.Title = task.Title
+		updated.Status = task.Status
+		updated.Priority = task.Priority
+		updated.Tags = task.Tags
+		updated.DueDate = task.DueDate
+		if task.DueDate != nil && current.DueDate != nil &&
+			current.DueDate.Local().Format(todoTxtDateLayout) == task.DueDate.Format(todoTxtDateLayout) {
+			updated.DueDate = current.DueDate
+		}
+
+		if todoTxtEqual(current, updated) {
+			report.Unchanged++
+			continue
+		}
+		updated.ModifiedAt = now
+		report.Updated++
+		tasks = append(tasks, updated)
+	}
+
+	return tasks, report, unmatched
+}
+
+// todoTxtEqual reports whether the tasks are written as the same todo.txt line, ignoring their dates.
+func todoTxtEqual(a, b domain.Task) bool {
+	a.CreatedAt, a.ModifiedAt = time.Time{}, time.Time{}
+	b.CreatedAt, b.ModifiedAt = time.Time{}, time.Time{}
+	return formatTodoTxt(a) == formatTodoTxt(b)
+}
+
+// liveTasks returns the tasks outside the trash.
+func (t Transfer) liveTasks(ctx context.Context) ([]domain.Task, error) {
+	tasks, err := t.provider.ExportTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	live := tasks[:0]
+	for _, task := range tasks {
+		if task.DeletedAt == nil {
+			live = append(live, task)
+		}
+	}
+	return live, nil
+}
+
+// ExportTodoTxt writes the tasks outside the trash in the todo.txt format, one task per line.
+func (t Transfer) ExportTodoTxt(ctx context.Context, w io.Writer) error {
+	const op = "service.transfer.export_todo_txt"
+
+	tasks, err := t.liveTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	for _, task := range tasks {
+		if _, err := io.WriteString(w, formatTodoTxt(task)+"\n"); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportTodoTxt reads a todo.txt file. A line updates the existing task named by its id: tag or, when it has
+// no tag, the one whose title matches its text. The other lines create new tasks.
+func (t Transfer) ImportTodoTxt(ctx context.Context, r io.Reader) (domain.ImportReport, error) {
+	const op = "service.transfer.import_todo_txt"
+
+	parsed, err := readTodoTxt(r)
+	if err != nil {
+		return domain.ImportReport{}, domain.NewValidationError("file", domain.ValidationInvalid, err.Error())
+	}
+
+	existing, err := t.liveTasks(ctx)
+	if err != nil {
+		return domain.ImportReport{}, handleError(op, err)
+	}
+
+	tasks, report, _ := mergeTodoTxt(existing, parsed)
+	if len(tasks) == 0 {
+		return report, nil
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	if err := t.modifier.ImportData(importCtx, nil, tasks, false); err != nil {
+		return domain.ImportReport{}, handleError(op, err)
+	}
+
+	return report, nil
+}
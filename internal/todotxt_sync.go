@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/labstack/gommon/log"
+)
+
+// todoTxtSyncInterval is how often the synced file and the tasks are checked for changes.
+const todoTxtSyncInterval = 2 * time.Second
+
+// TodoTxtSync keeps a todo.txt file and the tasks in step. Lines added to or edited in the file
+// are imported, lines removed from it move their task to the trash, and every change to the tasks
+// is written back to the file. When both sides change between two checks the file wins.
+type TodoTxtSync struct {
+	transfer Transfer
+	path     string
+	clock    Clock
+
+	mu     sync.Mutex
+	synced bool
+	last   []byte // the file content after the previous sync
+}
+
+// TodoTxtSync returns a sync of the tasks with the todo.txt file at the path, it does nothing until it is run.
+func (t Transfer) TodoTxtSync(path string, clock Clock) *TodoTxtSync {
+	return &TodoTxtSync{
+		transfer: t,
+		path:     path,
+		clock:    clock,
+	}
+}
+
+// Path returns the path of the synced file.
+func (s *TodoTxtSync) Path() string {
+	return s.path
+}
+
+// Run syncs the file until the context is cancelled.
+func (s *TodoTxtSync) Run(ctx context.Context) {
+	const op = "service.todo_txt_sync.run"
+
+	for {
+		if err := s.Sync(ctx); err != nil {
+			log.Error(op, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(todoTxtSyncInterval):
+		}
+	}
+}
+
+// Sync imports the changes made to the file since the previous sync and writes the tasks back to it.
+func (s *TodoTxtSync) Sync(ctx context.Context) error {
+	const op = "service.todo_txt_sync.sync"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if content != nil && (!s.synced || !bytes.Equal(content, s.last)) {
+		if err := s.importChanges(ctx, op, content); err != nil {
+			return err
+		}
+	}
+
+	tasks, err := s.transfer.liveTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	rendered := renderTodoTxt(tasks, content)
+	if !bytes.Equal(rendered, content) {
+		if err := writeFileAtomic(s.path, rendered); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	s.last = rendered
+	s.synced = true
+
+	return nil
+}
+
+// importChanges writes the lines of the file to the tasks and trashes the tasks whose lines were removed
+// since the previous sync. Tasks that were never in the file are left alone.
+func (s *TodoTxtSync) importChanges(ctx context.Context, op string, content []byte) error {
+	parsed, err := readTodoTxt(bytes.NewReader(content))
+	if err != nil {
+		return domain.NewValidationError("file", domain.ValidationInvalid, fmt.Sprintf("%s: %s", s.path, err))
+	}
+
+	existing, err := s.transfer.liveTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	tasks, _, unmatched := mergeTodoTxt(existing, parsed)
+
+	var removed []string
+	if s.synced {
+		previous, err := readTodoTxt(bytes.NewReader(s.last))
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		removed = removedTodoTxtTasks(previous, parsed, unmatched)
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	if len(tasks) > 0 {
+		if err := s.transfer.modifier.ImportData(importCtx, nil, tasks, false); err != nil {
+			return handleError(op, err)
+		}
+	}
+	for _, id := range removed {
+		err := s.transfer.modifier.DeleteTask(importCtx, id)
+		if err != nil && !errors.Is(err, domain.ErrTaskNotFound) { // already trashed together with its parent
+			return handleError(op, err)
+		}
+	}
+
+	return nil
+}
+
+// removedTodoTxtTasks returns the IDs of the unmatched tasks whose lines were in the previous file
+// but are no longer in the current one. A line is the same line as long as it keeps its id: tag.
+func removedTodoTxtTasks(previous, current, unmatched []domain.Task) []string {
+	lineKey := func(line domain.Task) string {
+		if line.ID != "" {
+			return "id:" + line.ID
+		}
+		return todoTxtKey(line)
+	}
+	counts := make(map[string]int)
+	for _, line := range current {
+		counts[lineKey(line)]++
+	}
+	var removed []domain.Task
+	for _, line := range previous {
+		if key := lineKey(line); counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		removed = append(removed, line)
+	}
+
+	matched, _ := matchTodoTxt(unmatched, removed)
+	ids := make([]string, 0, len(matched))
+	for _, task := range matched {
+		ids = append(ids, task.ID)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// renderTodoTxt writes the tasks in the order of their lines in the file, the tasks missing from it go last.
+// The line of a task that did not change is kept as it was written, so the file is only rewritten where needed.
+func renderTodoTxt(tasks []domain.Task, content []byte) []byte {
+	type fileLine struct {
+		index int
+		text  string
+		task  domain.Task
+	}
+	var lines []fileLine
+	var parsed []domain.Task
+	for i, text := range strings.Split(string(content), "\n") {
+		if task, err := parseTodoTxt(text); err == nil {
+			lines = append(lines, fileLine{index: i, text: strings.TrimRight(text, "\r"), task: task})
+			parsed = append(parsed, task)
+		}
+	}
+	matched, _ := matchTodoTxt(tasks, parsed)
+	lineOf := make(map[string]fileLine, len(matched))
+	for i, task := range matched {
+		lineOf[task.ID] = lines[i]
+	}
+
+	type renderedLine struct {
+		index int // position in the file, -1 for tasks missing from it
+		text  string
+	}
+	rendered := make([]renderedLine, len(tasks))
+	for i, task := range tasks {
+		rendered[i] = renderedLine{index: -1, text: formatTodoTxt(task)}
+
+		line, ok := lineOf[task.ID]
+		if !ok {
+			continue
+		}
+		rendered[i].index = line.index
+		if todoTxtEqual(task, line.task) {
+			rendered[i].text = line.text
+		}
+	}
+
+	sort.SliceStable(rendered, func(i, j int) bool {
+		a, b := rendered[i].index, rendered[j].index
+		if a >= 0 && b >= 0 {
+			return a < b
+		}
+		return a >= 0 && b < 0
+	})
+
+	var buf bytes.Buffer
+	for _, line := range rendered {
+		buf.WriteString(line.text)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// writeFileAtomic replaces the file in one step, so editors and other readers never see it half written.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
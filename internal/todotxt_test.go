@@ -0,0 +1,336 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	date := func(value string) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", value, time.Local)
+		return d
+	}
+	dueDate := date("2024-03-01")
+
+	tests := []struct {
+		name string
+		line string
+		want domain.Task
+	}{
+		{
+			name: "plain",
+			line: "Call the plumber",
+			want: domain.Task{Title: "Call the plumber", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+		},
+		{
+			name: "priority, creation date, project, context and due date",
+			line: "(A) 2024-02-01 Write the report +work @office due:2024-03-01",
+			want: domain.Task{
+				Title:     "Write the report",
+				Status:    domain.TaskStatusTodo,
+				Priority:  domain.TaskPriorityHigh,
+				Tags:      domain.StringArray{"work", "@office"},
+				DueDate:   &dueDate,
+				CreatedAt: date("2024-02-01"),
+			},
+		},
+		{
+			name: "lower priorities",
+			line: "(D) Water the plants",
+			want: domain.Task{Title: "Water the plants", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow},
+		},
+		{
+			name: "completed",
+			line: "x 2024-02-03 2024-02-01 Pay the rent pri:B",
+			want: domain.Task{
+				Title:      "Pay the rent",
+				Status:     domain.TaskStatusDone,
+				Priority:   domain.TaskPriorityMedium,
+				CreatedAt:  date("2024-02-01"),
+				ModifiedAt: date("2024-02-03"),
+			},
+		},
+		{
+			name: "id tag",
+			line: "Call the plumber id:3f2a1b4c",
+			want: domain.Task{ID: "3f2a1b4c", Title: "Call the plumber", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+		},
+		{
+			name: "unknown keys stay in the title",
+			line: "Renew passport t:2024-01-01",
+			want: domain.Task{Title: "Renew passport t:2024-01-01", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := parseTodoTxt(tt.line)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, task)
+		})
+	}
+}
+
+func TestParseTodoTxt_Invalid(t *testing.T) {
+	for _, line := range []string{"(A) +work", "Fix the bike due:tomorrow", "Go +to the shop"} {
+		t.Run(line, func(t *testing.T) {
+			_, err := parseTodoTxt(line)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatTodoTxt(t *testing.T) {
+	created := time.Date(2024, 2, 1, 10, 0, 0, 0, time.Local)
+	modified := time.Date(2024, 2, 3, 18, 30, 0, 0, time.Local)
+	dueDate := time.Date(2024, 3, 1, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		task domain.Task
+		want string
+	}{
+		{
+			name: "todo",
+			task: domain.Task{
+				Title:       "Write the report",
+				Description: "left out",
+				Status:      domain.TaskStatusTodo,
+				Priority:    domain.TaskPriorityHigh,
+				Tags:        domain.StringArray{"work", "@office", "side project"},
+				DueDate:     &dueDate,
+				CreatedAt:   created,
+				ModifiedAt:  modified,
+			},
+			want: "(A) 2024-02-01 Write the report +work @office +side-project due:2024-03-01",
+		},
+		{
+			name: "done",
+			task: domain.Task{
+				Title:      "Pay the rent",
+				Status:     domain.TaskStatusDone,
+				Priority:   domain.TaskPriorityMedium,
+				CreatedAt:  created,
+				ModifiedAt: modified,
+			},
+			want: "x 2024-02-03 2024-02-01 Pay the rent pri:B",
+		},
+		{
+			name: "id",
+			task: domain.Task{ID: "3f2a1b4c-9d1e-4f6a-8b2c-5e7d9a0b1c2d", Title: "Call the plumber", Status: domain.TaskStatusTodo},
+			want: "Call the plumber id:3f2a1b4c",
+		},
+		{
+			name: "no dates",
+			task: domain.Task{Title: "Call the plumber", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+			want: "Call the plumber",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := formatTodoTxt(tt.task)
+
+			assert.Equal(t, tt.want, line)
+
+			parsed, err := parseTodoTxt(line)
+			require.NoError(t, err)
+			assert.True(t, todoTxtEqual(tt.task, parsed))
+		})
+	}
+}
+
+func TestTransfer_ExportTodoTxt(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	deletedAt := time.Now()
+
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return([]domain.Task{
+		{ID: "1", Title: "Call the plumber", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow},
+		{ID: "2", Title: "Deleted", Status: domain.TaskStatusTodo, DeletedAt: &deletedAt},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := suite.transferService.ExportTodoTxt(ctx, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "(C) Call the plumber id:1\n", buf.String())
+}
+
+func TestTransfer_ImportTodoTxt(t *testing.T) {
+	t.Run("creates and updates tasks by title", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+		dueDate := time.Date(2030, 3, 1, 15, 0, 0, 0, time.Local)
+		existing := []domain.Task{
+			{ID: "1", Title: "Write the report", Description: "kept", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone, DueDate: &dueDate},
+			{ID: "2", Title: "Pay the rent", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+		}
+		file := "(A) write the report due:2030-03-01\n\nPay the rent\nx Water the plants\n"
+
+		suite.mockTransferProvider.On("ExportTasks", mock.Anything).Return(existing, nil)
+		suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 2 &&
+				tasks[0].ID == "1" && tasks[0].Priority == domain.TaskPriorityHigh && tasks[0].Description == "kept" &&
+				tasks[0].DueDate.Equal(dueDate) &&
+				tasks[1].Title == "Water the plants" && tasks[1].Status == domain.TaskStatusDone
+		}), false).Return(nil)
+
+		report, err := suite.transferService.ImportTodoTxt(ctx, strings.NewReader(file))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ImportReport{Created: 1, Updated: 1, Unchanged: 1}, report)
+	})
+
+	t.Run("matches lines with an id tag by it", func(t *testing.T) {
+		suite := newTransferSuite(t)
+		ctx := context.Background()
+		existing := []domain.Task{
+			{ID: "3f2a1b4c-9d1e-4f6a-8b2c-5e7d9a0b1c2d", Title: "Write the report", Description: "kept", Status: domain.TaskStatusTodo},
+			{ID: "7c4e2a10-1b3d-4e5f-9a8b-0c1d2e3f4a5b", Title: "Pay the rent", Status: domain.TaskStatusTodo},
+		}
+		file := "Write the final report id:3f2a1b4c\nPay the rent id:0badc0de\n"
+
+		suite.mockTransferProvider.On("ExportTasks", mock.Anything).Return(existing, nil)
+		suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 2 &&
+				tasks[0].ID == existing[0].ID && tasks[0].Title == "Write the final report" && tasks[0].Description == "kept" &&
+				tasks[1].ID != existing[1].ID && tasks[1].Title == "Pay the rent"
+		}), false).Return(nil)
+
+		report, err := suite.transferService.ImportTodoTxt(ctx, strings.NewReader(file))
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ImportReport{Created: 1, Updated: 1}, report, "a tag of no task is not matched by title")
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		suite := newTransferSuite(t)
+
+		_, err := suite.transferService.ImportTodoTxt(context.Background(), strings.NewReader("Call the plumber\n(A) ok\n"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+// memoryTransferStore keeps tasks in memory for the todo.txt sync tests.
+type memoryTransferStore struct {
+	tasks []domain.Task
+}
+
+func (m *memoryTransferStore) GetAllProjects(context.Context) ([]domain.Project, error) {
+	return nil, nil
+}
+
+func (m *memoryTransferStore) ExportTasks(context.Context) ([]domain.Task, error) {
+	tasks := make([]domain.Task, len(m.tasks))
+	copy(tasks, m.tasks)
+	return tasks, nil
+}
+
+func (m *memoryTransferStore) ImportData(_ context.Context, _ []domain.Project, tasks []domain.Task, _ bool) error {
+	for _, task := range tasks {
+		m.put(task)
+	}
+	return nil
+}
+
+func (m *memoryTransferStore) DeleteTask(_ context.Context, id string) error {
+	for i, task := range m.tasks {
+		if task.ID == id && task.DeletedAt == nil {
+			deletedAt := time.Now()
+			m.tasks[i].DeletedAt = &deletedAt
+			return nil
+		}
+	}
+	return domain.ErrTaskNotFound
+}
+
+func (m *memoryTransferStore) put(task domain.Task) {
+	for i := range m.tasks {
+		if m.tasks[i].ID == task.ID {
+			m.tasks[i] = task
+			return
+		}
+	}
+	m.tasks = append(m.tasks, task)
+}
+
+func (m *memoryTransferStore) live() []string {
+	var titles []string
+	for _, task := range m.tasks {
+		if task.DeletedAt == nil {
+			titles = append(titles, task.Title)
+		}
+	}
+	return titles
+}
+
+func TestTodoTxtSync(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	store := &memoryTransferStore{tasks: []domain.Task{
+		{ID: "1", Title: "Write the report", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityNone},
+	}}
+	sync := NewTransfer(store, store).TodoTxtSync(path, SystemClock{})
+
+	writeFile := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	readFile := func() string {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	// the first sync merges the existing file with the tasks and tags every line with the ID of its task
+	writeFile("(B) Pay the rent +home\nwrite the report\n")
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, []string{"write the report", "Pay the rent"}, store.live(), "the file wins")
+	rent := formatTodoTxt(store.tasks[1]) // e.g. (B) 2024-02-01 Pay the rent +home id:3f2a1b4c
+	assert.Equal(t, rent+"\nwrite the report id:1\n", readFile())
+
+	// a line edited in the file updates its task
+	writeFile(rent + "\nx write the report id:1\n")
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, domain.TaskStatusDone, store.tasks[0].Status)
+
+	// a line reworded in the file keeps its task
+	writeFile(rent + "\nx write the quarterly report id:1\n")
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, []string{"write the quarterly report", "Pay the rent"}, store.live())
+	assert.Equal(t, "1", store.tasks[0].ID)
+
+	// a task created in the app is appended to the file
+	store.put(domain.Task{ID: "3", Title: "Water the plants", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityHigh})
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, rent+"\nx write the quarterly report id:1\n(A) Water the plants id:3\n", readFile())
+
+	// a line removed from the file moves its task to the trash
+	writeFile(rent + "\n(A) Water the plants id:3\n")
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, []string{"Pay the rent", "Water the plants"}, store.live())
+
+	// a task deleted in the app is removed from the file
+	require.NoError(t, store.DeleteTask(ctx, "3"))
+	require.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, rent+"\n", readFile())
+
+	// an invalid file is reported and left alone
+	writeFile(rent + "\n(A) ok\n")
+	assert.ErrorIs(t, sync.Sync(ctx), domain.ErrInvalidArguments)
+	assert.Equal(t, rent+"\n(A) ok\n", readFile())
+	assert.Equal(t, []string{"Pay the rent"}, store.live())
+}
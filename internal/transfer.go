@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// importTimeout is longer than the timeout of other mutations since a whole database is written at once.
+const importTimeout = 30 * time.Second
+
+// Transfer moves the whole task database in and out of the app.
+type Transfer struct {
+	provider TransferProvider
+	modifier TransferModifier
+}
+
+//go:generate mockery --name TransferProvider
+type TransferProvider interface {
+	GetAllProjects(ctx context.Context) ([]domain.Project, error)
+	ExportTasks(ctx context.Context) ([]domain.Task, error)
+}
+
+//go:generate mockery --name TransferModifier
+type TransferModifier interface {
+	ImportData(ctx context.Context, projects []domain.Project, tasks []domain.Task, replace bool) error
+	DeleteTask(ctx context.Context, id string) error
+}
+
+func NewTransfer(provider TransferProvider, modifier TransferModifier) Transfer {
+	return Transfer{
+		provider: provider,
+		modifier: modifier,
+	}
+}
+
+// ExportJSON writes every project and task, including subtasks and the trash, as a versioned JSON document.
+func (t Transfer) ExportJSON(ctx context.Context, w io.Writer) error {
+	const op = "service.transfer.export_json"
+
+	projects, err := t.provider.GetAllProjects(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	tasks, err := t.provider.ExportTasks(ctx)
+	if err != nil {
+		return handleError(op, err)
+	}
+
+	document := domain.ExportDocument{
+		Version:    domain.ExportVersion,
+		ExportedAt: time.Now(),
+		Projects:   projects,
+		Tasks:      tasks,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ImportJSON reads a document written by ExportJSON. In merge mode an existing task is only overwritten
+// by an imported copy modified after it, the skipped tasks are reported as conflicts.
+func (t Transfer) ImportJSON(ctx context.Context, r io.Reader, mode domain.ImportMode) (domain.ImportReport, error) {
+	const op = "service.transfer.import_json"
+
+	var document domain.ExportDocument
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return domain.ImportReport{}, domain.NewValidationError("file", domain.ValidationInvalid, fmt.Sprintf("malformed document: %s", err))
+	}
+	if document.Version < 1 || document.Version > domain.ExportVersion {
+		return domain.ImportReport{}, domain.NewValidationError("version", domain.ValidationInvalid, fmt.Sprintf("unsupported document version %d", document.Version))
+	}
+
+	return t.importData(ctx, op, document.Projects, document.Tasks, mode)
+}
+
+// importData validates the imported tasks and writes them according to the mode.
+func (t Transfer) importData(ctx context.Context, op string, projects []domain.Project, tasks []domain.Task, mode domain.ImportMode) (domain.ImportReport, error) {
+	err := validation.Validate(mode, validation.Required, validation.In(domain.ImportMerge, domain.ImportReplace))
+	if err != nil {
+		return domain.ImportReport{}, invalidField("mode", err)
+	}
+	if err := validateImportedTasks(tasks); err != nil {
+		return domain.ImportReport{}, invalidArguments(err)
+	}
+
+	existingProjects := make(map[string]domain.Project)
+	existingTasks := make(map[string]domain.Task)
+	if mode == domain.ImportMerge {
+		current, err := t.provider.GetAllProjects(ctx)
+		if err != nil {
+			return domain.ImportReport{}, handleError(op, err)
+		}
+		for _, project := range current {
+			existingProjects[project.ID] = project
+		}
+
+		currentTasks, err := t.provider.ExportTasks(ctx)
+		if err != nil {
+			return domain.ImportReport{}, handleError(op, err)
+		}
+		for _, task := range currentTasks {
+			existingTasks[task.ID] = task
+		}
+	}
+
+	var report domain.ImportReport
+	projectIDs := make(map[string]bool)
+	var importedProjects []domain.Project
+	for _, project := range projects {
+		projectIDs[project.ID] = true
+		if existing, ok := existingProjects[project.ID]; ok && !project.ModifiedAt.After(existing.ModifiedAt) {
+			continue
+		}
+		importedProjects = append(importedProjects, project)
+	}
+	for id := range existingProjects {
+		projectIDs[id] = true
+	}
+
+	taskIDs := make(map[string]bool)
+	for _, task := range tasks {
+		taskIDs[task.ID] = true
+	}
+	for id := range existingTasks {
+		taskIDs[id] = true
+	}
+
+	var importedTasks []domain.Task
+	for _, task := range tasks {
+		// references that point nowhere put the task into the inbox or at the top level
+		if task.ProjectID != nil && !projectIDs[*task.ProjectID] {
+			task.ProjectID = nil
+		}
+		if task.ParentID != nil && !taskIDs[*task.ParentID] {
+			task.ParentID = nil
+		}
+		if task.Priority == "" {
+			task.Priority = domain.TaskPriorityNone
+		}
+
+		existing, ok := existingTasks[task.ID]
+		existingModifiedAt := existing.ModifiedAt
+		if task.ModifiedAt.Nanosecond() == 0 { // formats such as iCalendar only keep whole seconds
+			existingModifiedAt = existingModifiedAt.Truncate(time.Second)
+		}
+		switch {
+		case !ok:
+			report.Created++
+		case task.ModifiedAt.Equal(existingModifiedAt):
+			report.Unchanged++
+			continue
+		case task.ModifiedAt.After(existingModifiedAt):
+			report.Updated++
+		default:
+			report.Conflicts = append(report.Conflicts, domain.ImportConflict{
+				TaskID:             task.ID,
+				Title:              task.Title,
+				ImportedModifiedAt: task.ModifiedAt,
+				ExistingModifiedAt: existing.ModifiedAt,
+			})
+			continue
+		}
+		importedTasks = append(importedTasks, task)
+	}
+
+	// an imported parent can still close a cycle with tasks that are kept as they are
+	if mode == domain.ImportMerge {
+		parents := make(map[string]string, len(existingTasks))
+		for id, task := range existingTasks {
+			if task.ParentID != nil {
+				parents[id] = *task.ParentID
+			}
+		}
+		for _, task := range importedTasks {
+			delete(parents, task.ID)
+			if task.ParentID != nil {
+				parents[task.ID] = *task.ParentID
+			}
+		}
+		for _, task := range importedTasks {
+			if inParentCycle(task.ID, parents) {
+				return domain.ImportReport{}, domain.NewValidationError("tasks", domain.ValidationInvalid, fmt.Sprintf("task %s would become its own ancestor", task.ID))
+			}
+		}
+	}
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	err = t.modifier.ImportData(importCtx, importedProjects, importedTasks, mode == domain.ImportReplace)
+	if err != nil {
+		return domain.ImportReport{}, handleError(op, err)
+	}
+
+	return report, nil
+}
+
+// validateImportedTasks checks the fields every task needs, the index of the first invalid task is reported.
+func validateImportedTasks(tasks []domain.Task) error {
+	seen := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		err := validation.ValidateStruct(&task,
+			validation.Field(&task.ID, validation.Required),
+			validation.Field(&task.Title, validation.Required, validation.By(validateTitle)),
+			validation.Field(&task.Status, validation.Required, validation.In(domain.TaskStatusTodo, domain.TaskStatusDone)),
+			validation.Field(&task.Priority, validation.In(
+				domain.TaskPriorityNone,
+				domain.TaskPriorityLow,
+				domain.TaskPriorityMedium,
+				domain.TaskPriorityHigh,
+			)),
+			validation.Field(&task.Recurrence, validation.By(validateRecurrence)),
+			validation.Field(&task.Description, validation.By(validateDescription)),
+			validation.Field(&task.Tags, validation.By(validateTags)),
+			validation.Field(&task.Reminders, validation.By(validateReminders)),
+		)
+		if err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+		if seen[task.ID] {
+			return fmt.Errorf("task %d: duplicate id %s", i, task.ID)
+		}
+		seen[task.ID] = true
+	}
+
+	parents := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		if task.ParentID != nil {
+			parents[task.ID] = *task.ParentID
+		}
+	}
+	for i, task := range tasks {
+		if inParentCycle(task.ID, parents) {
+			return fmt.Errorf("task %d: is its own ancestor", i)
+		}
+	}
+
+	return nil
+}
+
+// inParentCycle reports whether following the parents up from the task leads back to it.
+func inParentCycle(id string, parents map[string]string) bool {
+	visited := make(map[string]bool)
+	for current, ok := parents[id]; ok; current, ok = parents[current] {
+		if current == id {
+			return true
+		}
+		if visited[current] { // a cycle above the task, reported for the tasks in it
+			return false
+		}
+		visited[current] = true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/mocks"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TransferSuite struct {
+	mockTransferProvider *mocks.TransferProvider
+	mockTransferModifier *mocks.TransferModifier
+	transferService      Transfer
+}
+
+func newTransferSuite(t *testing.T) *TransferSuite {
+	mockTransferProvider := mocks.NewTransferProvider(t)
+	mockTransferModifier := mocks.NewTransferModifier(t)
+	return &TransferSuite{
+		mockTransferProvider: mockTransferProvider,
+		mockTransferModifier: mockTransferModifier,
+		transferService:      NewTransfer(mockTransferProvider, mockTransferModifier),
+	}
+}
+
+func TestTransfer_ExportJSON(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	projectID := "p1"
+	tasks := []domain.Task{{ID: "1", ProjectID: &projectID, Title: "Write report", Tags: []string{"work"}, Status: domain.TaskStatusTodo}}
+
+	suite.mockTransferProvider.On("GetAllProjects", ctx).Return([]domain.Project{{ID: "p1", Name: "Work"}}, nil)
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return(tasks, nil)
+
+	var buf bytes.Buffer
+	err := suite.transferService.ExportJSON(ctx, &buf)
+
+	assert.NoError(t, err)
+	var document domain.ExportDocument
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, domain.ExportVersion, document.Version)
+	assert.Equal(t, tasks, document.Tasks)
+	assert.Len(t, document.Projects, 1)
+}
+
+func TestTransfer_ImportJSON_Merge(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	now := time.Now()
+	missingProject := "gone"
+	document := domain.ExportDocument{
+		Version: domain.ExportVersion,
+		Tasks: []domain.Task{
+			{ID: "new", Title: "New task", Status: domain.TaskStatusTodo, ProjectID: &missingProject, ModifiedAt: now},
+			{ID: "newer", Title: "Newer task", Status: domain.TaskStatusDone, ModifiedAt: now},
+			{ID: "same", Title: "Same task", Status: domain.TaskStatusTodo, ModifiedAt: now},
+			{ID: "older", Title: "Older task", Status: domain.TaskStatusTodo, ModifiedAt: now.Add(-time.Hour)},
+		},
+	}
+	data, _ := json.Marshal(document)
+
+	suite.mockTransferProvider.On("GetAllProjects", ctx).Return(nil, nil)
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return([]domain.Task{
+		{ID: "newer", ModifiedAt: now.Add(-time.Hour)},
+		{ID: "same", ModifiedAt: now},
+		{ID: "older", ModifiedAt: now},
+	}, nil)
+	suite.mockTransferModifier.On("ImportData", mock.Anything, []domain.Project(nil), mock.MatchedBy(func(tasks []domain.Task) bool {
+		return len(tasks) == 2 && tasks[0].ID == "new" && tasks[0].ProjectID == nil && tasks[0].Priority == domain.TaskPriorityNone && tasks[1].ID == "newer"
+	}), false).Return(nil)
+
+	report, err := suite.transferService.ImportJSON(ctx, bytes.NewReader(data), domain.ImportMerge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	if assert.Len(t, report.Conflicts, 1) {
+		assert.Equal(t, "older", report.Conflicts[0].TaskID)
+	}
+}
+
+func TestTransfer_ImportJSON_Replace(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	document := `{"version": 1, "projects": [{"id": "p1", "name": "Work"}], "tasks": [{"id": "1", "title": "Task one", "status": "todo", "project_id": "p1"}]}`
+
+	suite.mockTransferModifier.On("ImportData", mock.Anything, mock.AnythingOfType("[]domain.Project"), mock.MatchedBy(func(tasks []domain.Task) bool {
+		return len(tasks) == 1 && *tasks[0].ProjectID == "p1"
+	}), true).Return(nil)
+
+	report, err := suite.transferService.ImportJSON(ctx, strings.NewReader(document), domain.ImportReplace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	suite.mockTransferProvider.AssertNotCalled(t, "ExportTasks", mock.Anything)
+}
+
+func TestTransfer_ImportJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		mode     domain.ImportMode
+	}{
+		{"malformed", `{"version": 1, "tasks": [`, domain.ImportMerge},
+		{"unsupported version", `{"version": 99, "tasks": []}`, domain.ImportMerge},
+		{"unknown mode", `{"version": 1, "tasks": []}`, "append"},
+		{"missing title", `{"version": 1, "tasks": [{"id": "1", "status": "todo"}]}`, domain.ImportMerge},
+		{"duplicate id", `{"version": 1, "tasks": [{"id": "1", "title": "One", "status": "todo"}, {"id": "1", "title": "Two", "status": "todo"}]}`, domain.ImportMerge},
+		{"own parent", `{"version": 1, "tasks": [{"id": "1", "title": "One", "status": "todo", "parent_id": "1"}]}`, domain.ImportMerge},
+		{"parent cycle", `{"version": 1, "tasks": [{"id": "1", "title": "One", "status": "todo", "parent_id": "2"}, {"id": "2", "title": "Two", "status": "todo", "parent_id": "1"}]}`, domain.ImportReplace},
+		{"short tag", `{"version": 1, "tasks": [{"id": "1", "title": "One", "status": "todo", "tags": ["x"]}]}`, domain.ImportMerge},
+		{"reminder after due date", `{"version": 1, "tasks": [{"id": "1", "title": "One", "status": "todo", "reminders": [{"id": "r1", "before_minutes": -5}]}]}`, domain.ImportMerge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := newTransferSuite(t)
+
+			_, err := suite.transferService.ImportJSON(context.Background(), strings.NewReader(tt.document), tt.mode)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+			suite.mockTransferModifier.AssertNotCalled(t, "ImportData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTransfer_ImportJSON_ParentCycleWithExistingTask(t *testing.T) {
+	suite := newTransferSuite(t)
+	ctx := context.Background()
+	now := time.Now()
+	parentID, childID := "parent", "child"
+	document := domain.ExportDocument{
+		Version: domain.ExportVersion,
+		Tasks:   []domain.Task{{ID: "parent", Title: "Parent", Status: domain.TaskStatusTodo, ParentID: &childID, ModifiedAt: now}},
+	}
+	data, _ := json.Marshal(document)
+
+	suite.mockTransferProvider.On("GetAllProjects", ctx).Return(nil, nil)
+	suite.mockTransferProvider.On("ExportTasks", ctx).Return([]domain.Task{
+		{ID: "parent", ModifiedAt: now.Add(-time.Hour)},
+		{ID: "child", ParentID: &parentID, ModifiedAt: now},
+	}, nil)
+
+	_, err := suite.transferService.ImportJSON(ctx, bytes.NewReader(data), domain.ImportMerge)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	suite.mockTransferModifier.AssertNotCalled(t, "ImportData", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransfer_ImportJSON_RecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	tasks, transfer := NewTask(storage, storage, storage), NewTransfer(storage, storage)
+
+	task, err := tasks.Create(ctx, domain.CreateTaskRequest{Title: "Write report", Priority: domain.TaskPriorityNone})
+	require.NoError(t, err)
+	imported := task
+	imported.ID, imported.Title = "imported", "Read report"
+	task.Priority, task.ModifiedAt = domain.TaskPriorityHigh, task.ModifiedAt.Add(time.Minute)
+	data, err := json.Marshal(domain.ExportDocument{Version: domain.ExportVersion, Tasks: []domain.Task{task, imported}})
+	require.NoError(t, err)
+
+	_, err = transfer.ImportJSON(ctx, bytes.NewReader(data), domain.ImportMerge)
+	require.NoError(t, err)
+
+	history, err := tasks.GetTaskHistory(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.TaskEventUpdated, history[1].Action)
+	assert.Equal(t, []domain.TaskFieldChange{{Field: "priority", Old: "none", New: "high"}}, history[1].Changes)
+
+	history, err = tasks.GetTaskHistory(ctx, imported.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, domain.TaskEventCreated, history[0].Action)
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+)
+
+// GetTrash returns the tasks in the trash, most recently deleted first.
+func (t Task) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	const op = "service.task.get_trash"
+
+	tasks, err := t.provider.GetTrash(ctx)
+	if err != nil {
+		return nil, handleError(op, err)
+	}
+
+	return tasks, nil
+}
+
+// Restore brings the task back from the trash together with the subtasks deleted along with it.
+func (t Task) Restore(ctx context.Context, id string) (domain.Task, error) {
+	const op = "service.task.restore"
+
+	restoreCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	task, err := t.modifier.RestoreTask(restoreCtx, id)
+	if err != nil {
+		return domain.Task{}, handleError(op, err)
+	}
+
+	return task, nil
+}
+
+// EmptyTrash permanently deletes every task in the trash and returns how many were deleted.
+func (t Task) EmptyTrash(ctx context.Context) (int64, error) {
+	const op = "service.task.empty_trash"
+
+	emptyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deleted, err := t.modifier.EmptyTrash(emptyCtx)
+	if err != nil {
+		return 0, handleError(op, err)
+	}
+
+	return deleted, nil
+}
+
+// PurgeTrash permanently deletes the tasks that have been in the trash for longer than retention.
+func (t Task) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "service.task.purge_trash"
+
+	if retention <= 0 {
+		return 0, domain.NewValidationError("retention", domain.ValidationTooSmall, "must be positive")
+	}
+
+	purgeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deleted, err := t.modifier.PurgeTrash(purgeCtx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, handleError(op, err)
+	}
+
+	return deleted, nil
+}
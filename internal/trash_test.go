@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTask_Restore(t *testing.T) {
+	t.Run("task in trash", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		suite.mockTaskModifier.On("RestoreTask", mock.Anything, "123").Return(domain.Task{ID: "123", Title: "Restored"}, nil)
+
+		task, err := suite.taskService.Restore(ctx, "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Restored", task.Title)
+		assert.Nil(t, task.DeletedAt)
+	})
+
+	t.Run("task not in trash", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		suite.mockTaskModifier.On("RestoreTask", mock.Anything, "123").Return(domain.Task{}, domain.ErrTaskNotFound)
+
+		_, err := suite.taskService.Restore(ctx, "123")
+
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestTask_PurgeTrash(t *testing.T) {
+	t.Run("deletes tasks older than retention", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+		retention := 7 * 24 * time.Hour
+
+		suite.mockTaskModifier.On("PurgeTrash", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before)-retention < time.Minute && time.Since(before) >= retention
+		})).Return(int64(2), nil)
+
+		deleted, err := suite.taskService.PurgeTrash(ctx, retention)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		suite := newSuite(t)
+		ctx := context.Background()
+
+		_, err := suite.taskService.PurgeTrash(ctx, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+		suite.mockTaskModifier.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_Trash_Storage(t *testing.T) {
+	ctx := context.Background()
+	newTrash := func(t *testing.T) (*sqlite.Storage, Task) {
+		storage, err := sqlite.NewStorage(filepath.Join(t.TempDir(), "tasks.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { storage.Close() })
+		return storage, NewTask(storage, storage, storage)
+	}
+	create := func(t *testing.T, tasks Task, parentID, title string) domain.Task {
+		request := domain.CreateTaskRequest{Title: title, Priority: domain.TaskPriorityNone}
+		if parentID == "" {
+			task, err := tasks.Create(ctx, request)
+			require.NoError(t, err)
+			return task
+		}
+		task, err := tasks.CreateSubtask(ctx, parentID, request)
+		require.NoError(t, err)
+		return task
+	}
+	titles := func(list []domain.Task) []string {
+		titles := make([]string, 0, len(list))
+		for _, task := range list {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+	trash := func(t *testing.T, tasks Task) []string {
+		list, err := tasks.GetTrash(ctx)
+		require.NoError(t, err)
+		return titles(list)
+	}
+	children := func(t *testing.T, tasks Task, parentID string) []string {
+		list, err := tasks.GetChildren(ctx, parentID)
+		require.NoError(t, err)
+		return titles(list)
+	}
+
+	t.Run("the subtree is trashed and restored together", func(t *testing.T) {
+		_, tasks := newTrash(t)
+		trip := create(t, tasks, "", "Plan trip")
+		flights := create(t, tasks, trip.ID, "Book flights")
+		create(t, tasks, flights.ID, "Compare prices")
+		hotel := create(t, tasks, trip.ID, "Book hotel")
+		require.NoError(t, tasks.Delete(ctx, hotel.ID))
+		time.Sleep(time.Millisecond) // the subtree is told apart by its deletion time
+
+		require.NoError(t, tasks.Delete(ctx, trip.ID))
+		assert.Equal(t, []string{"Plan trip", "Book hotel"}, trash(t, tasks), "the subtasks are listed with their parent")
+		_, err := tasks.GetByID(ctx, flights.ID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+		restored, err := tasks.Restore(ctx, trip.ID)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, []string{"Book flights"}, children(t, tasks, trip.ID), "the subtask trashed on its own stays in the trash")
+		assert.Equal(t, []string{"Compare prices"}, children(t, tasks, flights.ID))
+		assert.Equal(t, []string{"Book hotel"}, trash(t, tasks))
+	})
+
+	t.Run("a subtask whose parent is in the trash is restored to the top level", func(t *testing.T) {
+		_, tasks := newTrash(t)
+		trip := create(t, tasks, "", "Plan trip")
+		flights := create(t, tasks, trip.ID, "Book flights")
+		require.NoError(t, tasks.Delete(ctx, flights.ID))
+		require.NoError(t, tasks.Delete(ctx, trip.ID))
+
+		restored, err := tasks.Restore(ctx, flights.ID)
+		require.NoError(t, err)
+		assert.Nil(t, restored.ParentID)
+		assert.Equal(t, []string{"Plan trip"}, trash(t, tasks))
+	})
+
+	t.Run("a task whose project is gone is restored to the inbox", func(t *testing.T) {
+		storage, tasks := newTrash(t)
+		deletedAt := time.Now().Add(-time.Hour)
+		missing := "missing"
+		require.NoError(t, storage.ImportData(ctx, nil, []domain.Task{{
+			ID:         "call-mom",
+			ProjectID:  &missing,
+			Title:      "Call mom",
+			Status:     domain.TaskStatusTodo,
+			Priority:   domain.TaskPriorityNone,
+			CreatedAt:  deletedAt,
+			ModifiedAt: deletedAt,
+			DeletedAt:  &deletedAt,
+		}}, false))
+
+		restored, err := tasks.Restore(ctx, "call-mom")
+		require.NoError(t, err)
+		assert.Nil(t, restored.ProjectID)
+	})
+
+	t.Run("restoring a task that is not in the trash", func(t *testing.T) {
+		_, tasks := newTrash(t)
+		task := create(t, tasks, "", "Plan trip")
+
+		_, err := tasks.Restore(ctx, task.ID)
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+
+	t.Run("purge deletes the tasks trashed before the cutoff", func(t *testing.T) {
+		storage, tasks := newTrash(t)
+		old := create(t, tasks, "", "Old")
+		oldChild := create(t, tasks, old.ID, "Old subtask")
+		recent := create(t, tasks, "", "Recent")
+		create(t, tasks, "", "Live")
+		require.NoError(t, tasks.Delete(ctx, old.ID))
+		time.Sleep(10 * time.Millisecond)
+		cutoff := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, tasks.Delete(ctx, recent.ID))
+
+		// the cutoff is compared as a point in time, whatever its time zone
+		purged, err := storage.PurgeTrash(ctx, cutoff.In(time.FixedZone("UTC+5", 5*60*60)))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		assert.Equal(t, []string{"Recent"}, trash(t, tasks))
+		inTrash, err := storage.IsInTrash(ctx, oldChild.ID)
+		require.NoError(t, err)
+		assert.False(t, inTrash, "the subtasks are purged with their parent")
+
+		purged, err = tasks.PurgeTrash(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, purged, "nothing has been in the trash for an hour")
+	})
+
+	t.Run("empty deletes the whole trash", func(t *testing.T) {
+		_, tasks := newTrash(t)
+		trip := create(t, tasks, "", "Plan trip")
+		create(t, tasks, trip.ID, "Book flights")
+		milk := create(t, tasks, "", "Buy milk")
+		create(t, tasks, "", "Live")
+		require.NoError(t, tasks.Delete(ctx, trip.ID))
+		require.NoError(t, tasks.Delete(ctx, milk.ID))
+
+		deleted, err := tasks.EmptyTrash(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.Empty(t, trash(t, tasks))
+		page, err := tasks.List(ctx, domain.TaskQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Live"}, titles(page.Tasks))
+	})
+}
@@ -1,11 +1,113 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+var hexColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validationCodes maps the error codes of ozzo-validation to the codes the frontend knows.
+var validationCodes = map[string]domain.ValidationCode{
+	"validation_required":                        domain.ValidationRequired,
+	"validation_nil_or_not_empty_required":       domain.ValidationRequired,
+	"validation_not_nil_required":                domain.ValidationRequired,
+	"validation_length_too_short":                domain.ValidationTooShort,
+	"validation_length_too_long":                 domain.ValidationTooLong,
+	"validation_min_greater_equal_than_required": domain.ValidationTooSmall,
+	"validation_min_greater_than_required":       domain.ValidationTooSmall,
+	"validation_max_less_equal_than_required":    domain.ValidationTooLarge,
+	"validation_max_less_than_required":          domain.ValidationTooLarge,
+	"validation_nil":                             domain.ValidationNotAllowed,
+	"validation_empty":                           domain.ValidationNotAllowed,
+	"validation_length_empty_required":           domain.ValidationNotAllowed,
+}
+
+// invalidArguments turns the errors of ozzo-validation into a *domain.ValidationError
+// with a code and a message for every invalid field.
+func invalidArguments(err error) error {
+	validationErr := &domain.ValidationError{}
+	collectFieldErrors(validationErr, "", err)
+	validationErr.Sort()
+	return validationErr
+}
+
+// invalidField returns a *domain.ValidationError for a value validated on its own.
+func invalidField(field string, err error) error {
+	return invalidArguments(validation.Errors{field: err})
+}
+
+func collectFieldErrors(validationErr *domain.ValidationError, field string, err error) {
+	var fieldErr validation.Error
+	var errs validation.Errors
+	var nested *domain.ValidationError
+	switch {
+	case errors.As(err, &errs):
+		for name, err := range errs {
+			collectFieldErrors(validationErr, strings.TrimPrefix(field+"."+name, "."), err)
+		}
+	case errors.As(err, &nested):
+		for _, fieldErr := range nested.Fields {
+			fieldErr.Field = strings.Trim(field+"."+fieldErr.Field, ".")
+			validationErr.Fields = append(validationErr.Fields, fieldErr)
+		}
+	case errors.As(err, &fieldErr):
+		code, ok := validationCodes[fieldErr.Code()]
+		if !ok {
+			code = domain.ValidationCode(fieldErr.Code())
+		}
+		if !validCode(code) {
+			code = domain.ValidationInvalid
+		}
+		validationErr.Fields = append(validationErr.Fields, domain.FieldError{Field: field, Code: code, Message: fieldErr.Error()})
+	default:
+		validationErr.Fields = append(validationErr.Fields, domain.FieldError{Field: field, Code: domain.ValidationInvalid, Message: err.Error()})
+	}
+}
+
+func validCode(code domain.ValidationCode) bool {
+	for _, c := range domain.AllValidationCode {
+		if c.Value == code {
+			return true
+		}
+	}
+	return false
+}
+
+// length works like validation.Length, but tells a value that is too short from one that is too long.
+func length(min, max int, message string) validation.Rule {
+	return validation.By(func(value any) error {
+		value, isNil := validation.Indirect(value)
+		if isNil || validation.IsEmpty(value) {
+			return nil
+		}
+
+		var n int
+		if s, ok := value.(string); ok {
+			n = utf8.RuneCountInString(s)
+		} else {
+			var err error
+			if n, err = validation.LengthOfValue(value); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case n < min:
+			return validation.NewError(string(domain.ValidationTooShort), message)
+		case n > max:
+			return validation.NewError(string(domain.ValidationTooLong), message)
+		}
+		return nil
+	})
+}
+
 func validateTitle(value any) error {
 	title, ok := value.(string)
 	if !ok {
@@ -13,10 +115,25 @@ func validateTitle(value any) error {
 	}
 
 	return validation.Validate(title,
-		validation.Length(3, 250).Error("must be between 3 and 250 characters"),
+		length(3, 250, "must be between 3 and 250 characters"),
 	)
 }
 
+// validatePriority accepts the known priorities and the empty one, which is defaulted or left unchanged by the caller.
+func validatePriority(value any) error {
+	priority, ok := value.(domain.TaskPriority)
+	if !ok {
+		return fmt.Errorf("must be a domain.TaskPriority")
+	}
+
+	return validation.Validate(priority, validation.In(
+		domain.TaskPriorityNone,
+		domain.TaskPriorityLow,
+		domain.TaskPriorityMedium,
+		domain.TaskPriorityHigh,
+	).Error("must be none, low, medium or high"))
+}
+
 func validateDueDate(value any) error {
 	dueDate, ok := value.(*time.Time)
 	if !ok {
@@ -28,25 +145,121 @@ func validateDueDate(value any) error {
 	)
 }
 
+func validateRecurrence(value any) error {
+	recurrence, ok := value.(*domain.Recurrence)
+	if !ok {
+		return fmt.Errorf("must be a *domain.Recurrence")
+	}
+	if recurrence == nil || recurrence.Frequency == "" {
+		return nil
+	}
+
+	return validation.ValidateStruct(recurrence,
+		validation.Field(&recurrence.Frequency, validation.In(
+			domain.RecurrenceDaily,
+			domain.RecurrenceWeekly,
+			domain.RecurrenceMonthly,
+			domain.RecurrenceAfterCompletion,
+		).Error("must be one of daily, weekly, monthly or after_completion")),
+		validation.Field(&recurrence.Interval, validation.Min(0), validation.Max(365).Error("must be no greater than 365")),
+		validation.Field(&recurrence.Weekdays,
+			validation.When(recurrence.Frequency != domain.RecurrenceWeekly, validation.Empty.Error("are only allowed for weekly recurrence")),
+			validation.Each(validation.Min(time.Sunday), validation.Max(time.Saturday)),
+		),
+		validation.Field(&recurrence.MonthDay,
+			validation.When(recurrence.Frequency != domain.RecurrenceMonthly, validation.Empty.Error("is only allowed for monthly recurrence")),
+			validation.Min(0), validation.Max(31),
+		),
+	)
+}
+
 func validateDescription(value any) error {
 	description, ok := value.(*string)
+	if text, isString := value.(string); isString {
+		description, ok = &text, true
+	}
 	if !ok {
-		return fmt.Errorf("must be a *string")
+		return fmt.Errorf("must be a string or a *string")
 	}
 
 	return validation.Validate(description,
-		validation.Length(0, 1000).Error("must be less than 1000 characters"),
+		length(0, 1000, "must be less than 1000 characters"),
 	)
 }
 
 func validateTags(value any) error {
 	tags, ok := value.([]string)
+	if array, isArray := value.(domain.StringArray); isArray {
+		tags, ok = array, true
+	}
 	if !ok {
 		return fmt.Errorf("must be a []string")
 	}
 
 	return validation.Validate(tags,
-		validation.Length(0, 15).Error("must be less than 15 tags"),
-		validation.Each(validation.Length(3, 50).Error("must be between 3 and 50 characters")),
+		length(0, 15, "must be less than 15 tags"),
+		validation.Each(validation.By(validateTagName)),
+	)
+}
+
+func validateTagName(value any) error {
+	name, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+
+	return validation.Validate(name,
+		length(3, 50, "must be between 3 and 50 characters"),
+	)
+}
+
+func validateProjectName(value any) error {
+	name, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+
+	return validation.Validate(name,
+		length(1, 100, "must be between 1 and 100 characters"),
+	)
+}
+
+func validateColor(value any) error {
+	color, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string")
+	}
+
+	return validation.Validate(color,
+		validation.Match(hexColorRegexp).Error("must be a hex color like #3b82f6"),
+	)
+}
+
+func validateReminders(value any) error {
+	reminders, ok := value.([]domain.Reminder)
+	if stored, isStored := value.(domain.Reminders); isStored {
+		reminders, ok = stored, true
+	}
+	if !ok {
+		return fmt.Errorf("must be a []domain.Reminder")
+	}
+
+	return validation.Validate(reminders,
+		length(0, 10, "must be no more than 10 reminders"),
+		validation.Each(validation.By(validateReminder)),
+	)
+}
+
+func validateReminder(value any) error {
+	reminder, ok := value.(domain.Reminder)
+	if !ok {
+		return fmt.Errorf("must be a domain.Reminder")
+	}
+
+	return validation.ValidateStruct(&reminder,
+		validation.Field(&reminder.BeforeMinutes,
+			validation.Min(0).Error("must not go off after the due date"),
+			validation.Max(60*24*30).Error("must be no more than 30 days before the due date"),
+		),
 	)
 }
@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
@@ -107,3 +109,97 @@ func TestTagsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRecurrenceValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence *domain.Recurrence
+		expectErr  bool
+	}{
+		{"NoRecurrence", nil, false},
+		{"EmptyFrequency", &domain.Recurrence{}, false},
+		{"Daily", &domain.Recurrence{Frequency: domain.RecurrenceDaily, Interval: 2}, false},
+		{"WeeklyWithWeekdays", &domain.Recurrence{Frequency: domain.RecurrenceWeekly, Weekdays: []time.Weekday{time.Monday}}, false},
+		{"MonthlyByDay", &domain.Recurrence{Frequency: domain.RecurrenceMonthly, MonthDay: 15}, false},
+		{"UnknownFrequency", &domain.Recurrence{Frequency: "yearly"}, true},
+		{"NegativeInterval", &domain.Recurrence{Frequency: domain.RecurrenceDaily, Interval: -1}, true},
+		{"WeekdaysOnDaily", &domain.Recurrence{Frequency: domain.RecurrenceDaily, Weekdays: []time.Weekday{time.Monday}}, true},
+		{"InvalidWeekday", &domain.Recurrence{Frequency: domain.RecurrenceWeekly, Weekdays: []time.Weekday{7}}, true},
+		{"MonthDayOnWeekly", &domain.Recurrence{Frequency: domain.RecurrenceWeekly, MonthDay: 3}, true},
+		{"MonthDayOutOfRange", &domain.Recurrence{Frequency: domain.RecurrenceMonthly, MonthDay: 32}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRecurrence(tt.recurrence)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	dueDate := time.Now().AddDate(0, 0, -2)
+	request := domain.CreateTaskRequest{
+		Title:      "No",
+		DueDate:    &dueDate,
+		Recurrence: &domain.Recurrence{Frequency: "yearly"},
+		Reminders:  []domain.Reminder{{BeforeMinutes: -5}},
+	}
+	err := validation.ValidateStruct(&request,
+		validation.Field(&request.Title, validation.Required, validation.By(validateTitle)),
+		validation.Field(&request.DueDate, validation.By(validateDueDate)),
+		validation.Field(&request.Recurrence, validation.By(validateRecurrence)),
+		validation.Field(&request.Reminders, validation.By(validateReminders)),
+	)
+
+	err = invalidArguments(err)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidArguments)
+	var validationErr *domain.ValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		assert.Equal(t, []domain.FieldError{
+			{Field: "due_date", Code: domain.ValidationTooSmall, Message: "must be at least tomorrow"},
+			{Field: "recurrence.frequency", Code: domain.ValidationInvalid, Message: "must be one of daily, weekly, monthly or after_completion"},
+			{Field: "reminders.0.before_minutes", Code: domain.ValidationTooSmall, Message: "must not go off after the due date"},
+			{Field: "title", Code: domain.ValidationTooShort, Message: "must be between 3 and 250 characters"},
+		}, validationErr.Fields)
+	}
+	assert.Equal(t, "invalid arguments: due_date: must be at least tomorrow; "+
+		"recurrence.frequency: must be one of daily, weekly, monthly or after_completion; "+
+		"reminders.0.before_minutes: must not go off after the due date; "+
+		"title: must be between 3 and 250 characters", err.Error())
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		code  domain.ValidationCode
+	}{
+		{"empty", "", ""},
+		{"counts characters", "ёжик", ""},
+		{"too short", "ab", domain.ValidationTooShort},
+		{"too long", "abcdefghijk", domain.ValidationTooLong},
+		{"too many items", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}, domain.ValidationTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Validate(tt.value, length(3, 10, "must be between 3 and 10"))
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var fieldErr validation.Error
+			if assert.ErrorAs(t, err, &fieldErr) {
+				assert.Equal(t, string(tt.code), fieldErr.Code())
+				assert.Equal(t, "must be between 3 and 10", fieldErr.Message())
+			}
+		})
+	}
+}
@@ -2,35 +2,86 @@ package main
 
 import (
 	"embed"
+	"errors"
+	"flag"
+	"github.com/ARUMANDESU/todo-app/internal/config"
 	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/ARUMANDESU/todo-app/internal/storage/sqlite"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"os"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	var flags config.Flags
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flagSet.StringVar(&flags.Config, "config", "", "path of the config file")
+	flagSet.StringVar(&flags.Database, "db", "", "path of the database of the active profile")
+	flagSet.StringVar(&flags.Profile, "profile", "", "name of the profile to open")
+	if err := flagSet.Parse(os.Args[1:]); errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	} else if err != nil {
+		os.Exit(2) // the flag set has printed the error and the usage
+	}
+
+	cfg, err := config.Load(flags)
+	if err != nil {
+		println("Error:", err.Error())
+		os.Exit(1)
+	}
+	if moved, err := cfg.MigrateLegacyData(); err != nil {
+		println("Error moving the database out of the cache directory:", err.Error())
+	} else if moved {
+		println("Moved the database to", cfg.DatabasePath(config.DefaultProfile))
+	}
+
 	// Create an instance of the app structure
-	app := NewApp()
+	app, err := NewApp(cfg)
+	if err != nil {
+		println("Error opening the database:", err.Error())
+		if errors.Is(err, sqlite.ErrMigration) {
+			println("Run \"todo migrate status\" to check its schema and \"todo migrate -h\" for how to repair it.")
+			println("The database is backed up before migrating, see the backups directory next to it.")
+		}
+		os.Exit(1)
+	}
+
+	settings := app.currentSettings()
 
 	// Create application with options
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:  "Todo App",
-		Width:  1024,
-		Height: 768,
+		Width:  settings.WindowWidth,
+		Height: settings.WindowHeight,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 125, G: 38, B: 4, A: 1},
 		OnStartup:        app.startup,
+		ErrorFormatter:   formatError,
 		Bind: []interface{}{
 			app,
 		},
 		EnumBind: []interface{}{
 			domain.AllTaskPriority,
 			domain.AllTaskStatus,
+			domain.AllRecurrenceFrequency,
+			domain.AllProjectDeleteMode,
+			domain.AllTaskSortKey,
+			domain.AllSortDirection,
+			domain.AllHistoryAction,
+			domain.AllTaskEventAction,
+			domain.AllImportMode,
+			domain.AllCSVColumn,
+			domain.AllMarkdownGrouping,
+			domain.AllBackupReason,
+			domain.AllDateFormat,
+			domain.AllTheme,
+			domain.AllValidationCode,
 		},
 	})
 
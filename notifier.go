@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"github.com/ARUMANDESU/todo-app/internal/domain"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ReminderEvent is emitted to the frontend with a domain.ReminderNotification when a reminder goes off.
+const ReminderEvent = "reminder"
+
+// eventNotifier delivers reminders to the frontend as Wails runtime events.
+type eventNotifier struct{}
+
+func (eventNotifier) Notify(ctx context.Context, notification domain.ReminderNotification) error {
+	runtime.EventsEmit(ctx, ReminderEvent, notification)
+	return nil
+}